@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// auditDirEnv names a directory to write JSONL audit records to. Setting
+	// it alone is enough to enable the "file" sink without also setting
+	// auditSinkEnv.
+	auditDirEnv = "PROXY_AUDIT_DIR"
+	// auditSinkEnv selects the audit sink explicitly. Defaults to "file" if
+	// auditDirEnv is set, otherwise "stdout".
+	auditSinkEnv = "PROXY_AUDIT_SINK"
+	// auditBodyCapBytesEnv overrides the number of request/response body
+	// bytes retained per audit record.
+	auditBodyCapBytesEnv = "PROXY_AUDIT_BODY_CAP_BYTES"
+	// auditS3BucketEnv, auditS3PrefixEnv, and auditS3RegionEnv configure the
+	// "s3" sink.
+	auditS3BucketEnv = "PROXY_AUDIT_S3_BUCKET"
+	auditS3PrefixEnv = "PROXY_AUDIT_S3_PREFIX"
+	auditS3RegionEnv = "PROXY_AUDIT_S3_REGION"
+
+	auditSinkStdout = "stdout"
+	auditSinkFile   = "file"
+	auditSinkS3     = "s3"
+
+	// auditDefaultBodyCapBytes bounds how much of a request/response body is
+	// retained verbatim in an audit record; the rest is accounted for in
+	// size but not embedded, so a multi-gigabyte upload or completion can't
+	// make the audit trail itself a memory or disk hazard.
+	auditDefaultBodyCapBytes = 1 << 20 // 1 MiB
+)
+
+// auditRecord is one JSONL line emitted per proxied request, regardless of
+// whether the upstream call succeeded.
+type auditRecord struct {
+	Timestamp             time.Time   `json:"timestamp"`
+	Client                string      `json:"client"`
+	Provider              string      `json:"provider"`
+	Method                string      `json:"method"`
+	Path                  string      `json:"path"`
+	RequestHeaders        http.Header `json:"request_headers"`
+	RequestBody           string      `json:"request_body,omitempty"`
+	RequestBodyTruncated  bool        `json:"request_body_truncated,omitempty"`
+	UpstreamStatus        int         `json:"upstream_status"`
+	ResponseHeaders       http.Header `json:"response_headers"`
+	ResponseBody          string      `json:"response_body,omitempty"`
+	ResponseBodyTruncated bool        `json:"response_body_truncated,omitempty"`
+	FirstByteLatencyMS    int64       `json:"first_byte_latency_ms"`
+	TotalLatencyMS        int64       `json:"total_latency_ms"`
+	Model                 string      `json:"model,omitempty"`
+	PromptTokens          int         `json:"prompt_tokens,omitempty"`
+	CompletionTokens      int         `json:"completion_tokens,omitempty"`
+}
+
+// auditSink persists audit records somewhere durable.
+type auditSink interface {
+	write(ctx context.Context, rec auditRecord) error
+}
+
+// auditConfig bundles the configured sink with the body capture limit, so
+// Audit doesn't need to re-read environment variables per request.
+type auditConfig struct {
+	sink         auditSink
+	bodyCapBytes int
+}
+
+// loadAuditConfig builds the audit subsystem from PROXY_AUDIT_DIR/
+// PROXY_AUDIT_SINK. It returns a nil *auditConfig, not an error, when
+// neither is set: auditing is opt-in.
+func loadAuditConfig(ctx context.Context) (*auditConfig, error) {
+	dir := strings.TrimSpace(os.Getenv(auditDirEnv))
+	kind := strings.TrimSpace(os.Getenv(auditSinkEnv))
+	if dir == "" && kind == "" {
+		return nil, nil
+	}
+	if kind == "" {
+		kind = auditSinkFile
+	}
+
+	bodyCap := auditDefaultBodyCapBytes
+	if raw := strings.TrimSpace(os.Getenv(auditBodyCapBytesEnv)); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%s must be a positive integer, got %q", auditBodyCapBytesEnv, raw)
+		}
+		bodyCap = n
+	}
+
+	var sink auditSink
+	var err error
+	switch kind {
+	case auditSinkStdout:
+		sink = newStdoutAuditSink()
+	case auditSinkFile:
+		if dir == "" {
+			return nil, fmt.Errorf("audit sink %q requires %s", auditSinkFile, auditDirEnv)
+		}
+		sink, err = newFileAuditSink(dir)
+	case auditSinkS3:
+		sink, err = newS3AuditSink(ctx)
+	default:
+		return nil, fmt.Errorf("unknown %s %q (want %s, %s, or %s)", auditSinkEnv, kind, auditSinkStdout, auditSinkFile, auditSinkS3)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditConfig{sink: sink, bodyCapBytes: bodyCap}, nil
+}
+
+// stdoutAuditSink writes one JSON object per line to the process's stdout.
+type stdoutAuditSink struct {
+	mu sync.Mutex
+}
+
+func newStdoutAuditSink() *stdoutAuditSink {
+	return &stdoutAuditSink{}
+}
+
+func (s *stdoutAuditSink) write(_ context.Context, rec auditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.NewEncoder(os.Stdout).Encode(rec)
+}
+
+// fileAuditSink appends JSONL records to a file per UTC day under dir, so a
+// long-running deployment doesn't accumulate one unbounded file.
+type fileAuditSink struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func newFileAuditSink(dir string) (*fileAuditSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	return &fileAuditSink{dir: dir}, nil
+}
+
+func (s *fileAuditSink) write(_ context.Context, rec auditRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := filepath.Join(s.dir, fmt.Sprintf("audit-%s.jsonl", rec.Timestamp.Format("20060102")))
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// s3AuditSink writes one object per audit record, since S3 has no native
+// append. Objects are keyed by day and a random suffix so concurrent
+// requests never collide.
+type s3AuditSink struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newS3AuditSink(ctx context.Context) (*s3AuditSink, error) {
+	bucket := strings.TrimSpace(os.Getenv(auditS3BucketEnv))
+	if bucket == "" {
+		return nil, fmt.Errorf("audit sink %q requires %s", auditSinkS3, auditS3BucketEnv)
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region := strings.TrimSpace(os.Getenv(auditS3RegionEnv)); region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+
+	return &s3AuditSink{
+		client: s3.NewFromConfig(awsCfg),
+		bucket: bucket,
+		prefix: strings.Trim(os.Getenv(auditS3PrefixEnv), "/"),
+	}, nil
+}
+
+func (s *s3AuditSink) write(ctx context.Context, rec auditRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s.json", rec.Timestamp.Format("2006/01/02"), newRequestID())
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
+		Key:         &key,
+		Body:        bytes.NewReader(data),
+		ContentType: awsStringPtr("application/json"),
+	})
+	return err
+}
+
+func awsStringPtr(s string) *string { return &s }
+
+// Audit records each proxied request/response as an auditRecord once the
+// response completes, regardless of whether the upstream errored. It runs
+// after StripSensitiveHeaders so the headers it captures never carry the
+// client's bearer token or API key.
+func Audit(p provider, cfg *auditConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		if cfg == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			method := r.Method
+			path := r.URL.Path
+			reqHeaders := r.Header.Clone()
+
+			reqCapture := newBoundedBuffer(cfg.bodyCapBytes)
+			defer reqCapture.close()
+			if r.Body != nil {
+				r.Body = io.NopCloser(io.TeeReader(r.Body, reqCapture))
+			}
+
+			rec := newAuditResponseRecorder(w, cfg.bodyCapBytes)
+			defer rec.capture.close()
+
+			next.ServeHTTP(rec, r)
+
+			totalLatency := time.Since(start)
+			firstByteLatency := rec.firstByteLatency
+			if firstByteLatency == 0 {
+				firstByteLatency = totalLatency
+			}
+
+			responseBody := rec.capture.bytes()
+			model, promptTokens, completionTokens := extractUsage(rec.Header().Get("Content-Type"), responseBody)
+
+			record := auditRecord{
+				Timestamp:             start.UTC(),
+				Client:                clientName(r.Context()),
+				Provider:              p.name,
+				Method:                method,
+				Path:                  path,
+				RequestHeaders:        reqHeaders,
+				RequestBody:           reqCapture.String(),
+				RequestBodyTruncated:  reqCapture.truncated(),
+				UpstreamStatus:        rec.status,
+				ResponseHeaders:       rec.Header().Clone(),
+				ResponseBody:          string(responseBody),
+				ResponseBodyTruncated: rec.capture.truncated(),
+				FirstByteLatencyMS:    firstByteLatency.Milliseconds(),
+				TotalLatencyMS:        totalLatency.Milliseconds(),
+				Model:                 model,
+				PromptTokens:          promptTokens,
+				CompletionTokens:      completionTokens,
+			}
+
+			writeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := cfg.sink.write(writeCtx, record); err != nil {
+				log.Printf("proxy warning [%s]: audit sink write failed: %v", p.name, err)
+			}
+		})
+	}
+}
+
+// auditResponseRecorder wraps an http.ResponseWriter to capture its status,
+// a bounded copy of its body, and the latency until its first byte was
+// written, while still forwarding http.Flusher for streaming responses.
+type auditResponseRecorder struct {
+	http.ResponseWriter
+	status           int
+	wroteHeader      bool
+	capture          *boundedBuffer
+	start            time.Time
+	wroteBody        bool
+	firstByteLatency time.Duration
+}
+
+func newAuditResponseRecorder(w http.ResponseWriter, bodyCapBytes int) *auditResponseRecorder {
+	return &auditResponseRecorder{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+		capture:        newBoundedBuffer(bodyCapBytes),
+		start:          time.Now(),
+	}
+}
+
+func (r *auditResponseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *auditResponseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+	}
+	if !r.wroteBody {
+		r.wroteBody = true
+		r.firstByteLatency = time.Since(r.start)
+	}
+	_, _ = r.capture.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *auditResponseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// boundedBuffer retains up to cap bytes of a stream in memory; anything
+// past that is spilled to a temp file rather than buffered, so auditing an
+// enormous upload or completion can't exhaust memory. The spilled bytes
+// aren't read back: they exist only to bound memory use, and the audit
+// record reports the body as truncated with its true total size.
+type boundedBuffer struct {
+	cap       int
+	buf       bytes.Buffer
+	total     int64
+	spillFile *os.File
+}
+
+func newBoundedBuffer(cap int) *boundedBuffer {
+	return &boundedBuffer{cap: cap}
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	b.total += int64(n)
+
+	if room := b.cap - b.buf.Len(); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		b.buf.Write(p[:room])
+		p = p[room:]
+	}
+
+	if len(p) > 0 {
+		if b.spillFile == nil {
+			f, err := os.CreateTemp("", "proxy-audit-*.spill")
+			if err != nil {
+				// Spilling is only a memory bound, not part of the audit
+				// record itself; if it fails, just stop retaining overflow.
+				return n, nil
+			}
+			b.spillFile = f
+		}
+		_, _ = b.spillFile.Write(p)
+	}
+
+	return n, nil
+}
+
+func (b *boundedBuffer) truncated() bool { return b.total > int64(b.buf.Len()) }
+
+func (b *boundedBuffer) bytes() []byte { return b.buf.Bytes() }
+
+func (b *boundedBuffer) String() string { return b.buf.String() }
+
+func (b *boundedBuffer) close() {
+	if b.spillFile != nil {
+		name := b.spillFile.Name()
+		b.spillFile.Close()
+		os.Remove(name)
+	}
+}
+
+// tokenUsage covers both OpenAI's (prompt_tokens/completion_tokens) and
+// Anthropic's (input_tokens/output_tokens) usage field names.
+type tokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	InputTokens      int `json:"input_tokens"`
+	OutputTokens     int `json:"output_tokens"`
+}
+
+// usageFrame is the shape of a single JSON response body, or a single SSE
+// data frame, that might carry a model name and/or token usage. Anthropic
+// nests both inside "message" on its message_start event.
+type usageFrame struct {
+	Model   string      `json:"model"`
+	Usage   *tokenUsage `json:"usage"`
+	Message *struct {
+		Model string      `json:"model"`
+		Usage *tokenUsage `json:"usage"`
+	} `json:"message"`
+}
+
+// extractUsage infers the model name and prompt/completion token counts
+// from a response body: a single JSON document for ordinary responses, or
+// a sequence of "data: {...}" SSE frames for streaming ones.
+func extractUsage(contentType string, body []byte) (model string, promptTokens, completionTokens int) {
+	if strings.Contains(contentType, "text/event-stream") {
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			data, ok := bytes.CutPrefix(line, []byte("data:"))
+			if !ok {
+				continue
+			}
+			data = bytes.TrimSpace(data)
+			if len(data) == 0 || string(data) == "[DONE]" {
+				continue
+			}
+			applyUsageFrame(data, &model, &promptTokens, &completionTokens)
+		}
+		return model, promptTokens, completionTokens
+	}
+
+	applyUsageFrame(body, &model, &promptTokens, &completionTokens)
+	return model, promptTokens, completionTokens
+}
+
+func applyUsageFrame(data []byte, model *string, promptTokens, completionTokens *int) {
+	var frame usageFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return
+	}
+	if frame.Model != "" {
+		*model = frame.Model
+	}
+	if frame.Usage != nil {
+		*promptTokens = maxInt(*promptTokens, maxInt(frame.Usage.PromptTokens, frame.Usage.InputTokens))
+		*completionTokens = maxInt(*completionTokens, maxInt(frame.Usage.CompletionTokens, frame.Usage.OutputTokens))
+	}
+	if frame.Message != nil {
+		if frame.Message.Model != "" {
+			*model = frame.Message.Model
+		}
+		if frame.Message.Usage != nil {
+			*promptTokens = maxInt(*promptTokens, frame.Message.Usage.InputTokens)
+			*completionTokens = maxInt(*completionTokens, frame.Message.Usage.OutputTokens)
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
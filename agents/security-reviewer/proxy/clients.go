@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// clientsFileEnv names the environment variable pointing at the YAML
+	// (or JSON, which parses as a YAML subset) file declaring the client
+	// registry.
+	clientsFileEnv = "PROXY_CLIENTS_FILE"
+	// clientsJSONEnv carries the client registry inline as YAML or JSON
+	// text, for deployments that would rather set an env var than mount a
+	// file.
+	clientsJSONEnv = "PROXY_CLIENTS_JSON"
+	// legacyClientName is the display name given to the single client
+	// synthesized from PROXY_API_KEY when neither clientsFileEnv nor
+	// clientsJSONEnv is set.
+	legacyClientName = "default"
+)
+
+// clientSpec is a single entry in the client registry: a bearer token and
+// what it's allowed to do.
+type clientSpec struct {
+	// Name identifies the client in logs.
+	Name string `yaml:"name"`
+	// Token is the bearer secret presented in the inbound Authorization
+	// header. Only its SHA-256 hash is retained once the registry loads.
+	Token string `yaml:"token"`
+	// AllowedPrefixes restricts the client to these provider prefixes
+	// (e.g. "/openai/"). Empty means every provider is allowed.
+	AllowedPrefixes []string `yaml:"allowedPrefixes,omitempty"`
+	// RequestsPerMinute caps requests per rolling minute. Zero means
+	// unlimited.
+	RequestsPerMinute int `yaml:"requestsPerMinute,omitempty"`
+	// RequestsPerDay caps requests per rolling day. Zero means unlimited.
+	RequestsPerDay int `yaml:"requestsPerDay,omitempty"`
+}
+
+// clientsFile is the on-disk shape of PROXY_CLIENTS_FILE/PROXY_CLIENTS_JSON.
+type clientsFile struct {
+	Clients []clientSpec `yaml:"clients"`
+}
+
+// principal is a clientSpec resolved into something ready to authenticate
+// and rate-limit requests with: its token hashed, its scope compiled into a
+// set, and its budgets compiled into token buckets.
+type principal struct {
+	name            string
+	tokenHash       [sha256.Size]byte
+	allowedPrefixes map[string]bool // nil means every provider is allowed
+	minuteBucket    *tokenBucket
+	dayBucket       *tokenBucket
+}
+
+// clientRegistry authenticates bearer tokens against the configured
+// principals.
+type clientRegistry struct {
+	principals []*principal
+}
+
+// loadClientSpecs reads the client registry from PROXY_CLIENTS_FILE or
+// PROXY_CLIENTS_JSON, or, if neither is set, synthesizes a single
+// unrestricted, unlimited client from the legacy PROXY_API_KEY so existing
+// deployments keep working unchanged.
+func loadClientSpecs() ([]clientSpec, error) {
+	if path := strings.TrimSpace(os.Getenv(clientsFileEnv)); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		specs, err := parseClientsFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return specs, nil
+	}
+
+	if inline := os.Getenv(clientsJSONEnv); strings.TrimSpace(inline) != "" {
+		specs, err := parseClientsFile([]byte(inline))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", clientsJSONEnv, err)
+		}
+		return specs, nil
+	}
+
+	token := strings.TrimSpace(os.Getenv("PROXY_API_KEY"))
+	if token == "" {
+		return nil, fmt.Errorf("PROXY_API_KEY must be set (or configure %s/%s)", clientsFileEnv, clientsJSONEnv)
+	}
+	return []clientSpec{{Name: legacyClientName, Token: token}}, nil
+}
+
+func parseClientsFile(data []byte) ([]clientSpec, error) {
+	var file clientsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Clients) == 0 {
+		return nil, errors.New("no clients declared")
+	}
+	return file.Clients, nil
+}
+
+// buildClientRegistry resolves every clientSpec into a principal, hashing
+// its token and compiling its scope/quota rules. It fails closed if any
+// client has no name or no token.
+func buildClientRegistry(specs []clientSpec) (*clientRegistry, error) {
+	registry := &clientRegistry{}
+	for _, spec := range specs {
+		p, err := buildPrincipal(spec)
+		if err != nil {
+			return nil, fmt.Errorf("client %s: %w", firstNonEmpty(spec.Name, "<unnamed>"), err)
+		}
+		registry.principals = append(registry.principals, p)
+	}
+	return registry, nil
+}
+
+func buildPrincipal(spec clientSpec) (*principal, error) {
+	if spec.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	token := strings.TrimSpace(spec.Token)
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+
+	var allowed map[string]bool
+	if len(spec.AllowedPrefixes) > 0 {
+		allowed = make(map[string]bool, len(spec.AllowedPrefixes))
+		for _, prefix := range spec.AllowedPrefixes {
+			allowed[normalizePrefix(prefix)] = true
+		}
+	}
+
+	return &principal{
+		name:            spec.Name,
+		tokenHash:       sha256.Sum256([]byte(token)),
+		allowedPrefixes: allowed,
+		minuteBucket:    newTokenBucket(spec.RequestsPerMinute, time.Minute),
+		dayBucket:       newTokenBucket(spec.RequestsPerDay, 24*time.Hour),
+	}, nil
+}
+
+// authenticate looks up the principal matching the bearer token carried in
+// header, hashing the presented token with SHA-256 and comparing it
+// against each candidate's stored hash with a constant-time comparison.
+func (r *clientRegistry) authenticate(header string) (*principal, bool) {
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return nil, false
+	}
+	presented := sha256.Sum256([]byte(strings.TrimSpace(parts[1])))
+
+	for _, p := range r.principals {
+		if subtle.ConstantTimeCompare(presented[:], p.tokenHash[:]) == 1 {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// allowsPrefix reports whether p may reach the provider mounted at prefix.
+func (p *principal) allowsPrefix(prefix string) bool {
+	if p.allowedPrefixes == nil {
+		return true
+	}
+	return p.allowedPrefixes[prefix]
+}
+
+// checkQuota enforces p's per-minute and per-day request budgets,
+// reporting whether the request may proceed and, if not, how long the
+// caller should wait before retrying.
+func (p *principal) checkQuota() (ok bool, retryAfter time.Duration) {
+	if ok, retryAfter := p.minuteBucket.allow(); !ok {
+		return false, retryAfter
+	}
+	if ok, retryAfter := p.dayBucket.allow(); !ok {
+		p.minuteBucket.refund()
+		return false, retryAfter
+	}
+	return true, 0
+}
+
+// principalContextKey is the context.Context key withPrincipal/
+// principalFromContext store and read the authenticated principal under.
+type principalContextKey struct{}
+
+// withPrincipal returns a context carrying p, for withLogging to read back
+// once the request has been authenticated.
+func withPrincipal(ctx context.Context, p *principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// principalFromContext returns the principal stashed by withPrincipal, if
+// any.
+func principalFromContext(ctx context.Context) (*principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(*principal)
+	return p, ok
+}
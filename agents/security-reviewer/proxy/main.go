@@ -2,13 +2,11 @@ package main
 
 import (
 	"context"
-	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log"
-	"net"
+	"math"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"os"
 	"os/signal"
@@ -38,42 +36,31 @@ const (
 	headerAnthropicAPIKey = "X-Api-Key"
 )
 
-// providerProxy defines how to forward requests to a specific upstream API.
-type providerProxy struct {
-	// Prefix is the inbound path prefix handled by the provider.
-	Prefix string
-	// Target is the upstream endpoint used to service requests for the provider.
-	Target *url.URL
-	// HeaderName is the outbound header carrying the provider-specific credential.
-	HeaderName string
-	// HeaderValue is the credential value set on outbound requests.
-	HeaderValue string
-	// DisplayName is the human-readable name of the provider used in logs.
-	DisplayName string
-}
-
 // main configures the proxy service and starts the HTTP server.
 func main() {
-	cfg, err := loadConfig()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Fatalf("proxy configuration error: %v", err)
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
 	mux := http.NewServeMux()
 	mux.HandleFunc(healthPath, handleHealth)
+	mux.HandleFunc(metricsPath, handleMetrics)
+	mux.HandleFunc(readinessPath, newReadinessChecker(cfg.providers).handleReadiness)
 
-	mountProxy(mux, cfg.openAIProxy, cfg.clientToken)
-	mountProxy(mux, cfg.anthropicProxy, cfg.clientToken)
+	for _, p := range cfg.providers {
+		mountProxy(mux, p, cfg.clients, cfg.audit)
+	}
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 	})
 
 	server := &http.Server{
 		Addr:        cfg.listenAddr,
-		Handler:     withLogging(mux),
+		Handler:     mux,
 		ReadTimeout: 15 * time.Second,
 		// WriteTimeout needs to be relatively high because it limits how long
 		// the upstream inference API has to respond.
@@ -81,8 +68,7 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("proxy listening on %s (OpenAI -> %s, Anthropic -> %s)",
-		cfg.listenAddr, cfg.openAIProxy.Target.String(), cfg.anthropicProxy.Target.String())
+	log.Printf("proxy listening on %s (%s)", cfg.listenAddr, describeProviders(cfg.providers))
 
 	go func() {
 		<-ctx.Done()
@@ -100,138 +86,76 @@ func main() {
 
 // proxyConfig captures runtime settings for the reverse proxy.
 type proxyConfig struct {
-	listenAddr     string
-	openAIProxy    providerProxy
-	anthropicProxy providerProxy
-	clientToken    string
-}
-
-// loadConfig reads environment variables and constructs the proxy configuration.
-func loadConfig() (proxyConfig, error) {
+	listenAddr string
+	providers  []provider
+	clients    *clientRegistry
+	audit      *auditConfig
+}
+
+// loadConfig reads environment variables and the provider/client registry
+// files (if any) and constructs the proxy configuration. Every declared
+// provider's credential and every declared client's token are resolved up
+// front, so a misconfiguration fails startup instead of surfacing only
+// when a request arrives.
+func loadConfig(ctx context.Context) (proxyConfig, error) {
 	listen := firstNonEmpty(os.Getenv("PROXY_LISTEN_ADDR"), defaultListenAddr)
 
-	clientToken := strings.TrimSpace(os.Getenv("PROXY_API_KEY"))
-	if clientToken == "" {
-		return proxyConfig{}, errors.New("PROXY_API_KEY must be set")
-	}
-
-	openAIBase, err := parseBaseURL(firstNonEmpty(os.Getenv("PROXY_OPENAI_BASE_URL"), defaultOpenAIBaseURL))
+	clientSpecs, err := loadClientSpecs()
 	if err != nil {
-		return proxyConfig{}, fmt.Errorf("parse OpenAI base URL: %w", err)
+		return proxyConfig{}, fmt.Errorf("load clients: %w", err)
 	}
-	anthropicBase, err := parseBaseURL(firstNonEmpty(os.Getenv("PROXY_ANTHROPIC_BASE_URL"), defaultAnthropicBaseURL))
+	clients, err := buildClientRegistry(clientSpecs)
 	if err != nil {
-		return proxyConfig{}, fmt.Errorf("parse Anthropic base URL: %w", err)
+		return proxyConfig{}, err
 	}
 
-	openAIKey := strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
-	anthropicKey := strings.TrimSpace(os.Getenv("ANTHROPIC_API_KEY"))
+	specs, err := loadProviderSpecs(strings.TrimSpace(os.Getenv(providersFileEnv)))
+	if err != nil {
+		return proxyConfig{}, fmt.Errorf("load providers: %w", err)
+	}
 
-	openAIProxy := providerProxy{
-		Prefix:      openAIInboundPrefix,
-		Target:      openAIBase,
-		HeaderName:  headerAuthorization,
-		HeaderValue: bearerValue(openAIKey),
-		DisplayName: "OpenAI",
+	providers, err := buildProviders(ctx, specs)
+	if err != nil {
+		return proxyConfig{}, err
 	}
-	anthropicProxy := providerProxy{
-		Prefix:      anthropicInboundPrefix,
-		Target:      anthropicBase,
-		HeaderName:  headerAnthropicAPIKey,
-		HeaderValue: anthropicKey,
-		DisplayName: "Anthropic",
+
+	audit, err := loadAuditConfig(ctx)
+	if err != nil {
+		return proxyConfig{}, fmt.Errorf("load audit config: %w", err)
 	}
 
 	return proxyConfig{
-		listenAddr:     listen,
-		openAIProxy:    openAIProxy,
-		anthropicProxy: anthropicProxy,
-		clientToken:    clientToken,
+		listenAddr: listen,
+		providers:  providers,
+		clients:    clients,
+		audit:      audit,
 	}, nil
 }
 
-// mountProxy attaches a provider proxy to the HTTP mux.
-func mountProxy(mux *http.ServeMux, provider providerProxy, clientToken string) {
-	handler := buildProviderHandler(provider, clientToken)
-	mux.Handle(provider.Prefix, handler)
-}
-
-// buildProviderHandler creates an HTTP handler that forwards requests to the provider.
-func buildProviderHandler(provider providerProxy, clientToken string) http.Handler {
-	proxy := httputil.NewSingleHostReverseProxy(provider.Target)
-	originalDirector := proxy.Director
-	proxy.Director = func(req *http.Request) {
-		inboundPath := req.URL.Path
-		inboundRawPath := req.URL.RawPath
-		originalDirector(req)
-		rewriteRequest(req, inboundPath, inboundRawPath, provider)
-	}
-	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("proxy error [%s]: %v", provider.DisplayName, err)
-		http.Error(w, "upstream request failed", http.StatusBadGateway)
+// describeProviders renders a one-line "name -> target" summary of the
+// active providers for the startup log line.
+func describeProviders(providers []provider) string {
+	parts := make([]string, len(providers))
+	for i, p := range providers {
+		parts[i] = fmt.Sprintf("%s -> %s", p.name, p.target.String())
 	}
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.HasPrefix(r.URL.Path, provider.Prefix) {
-			http.NotFound(w, r)
-			return
-		}
-		if provider.HeaderValue == "" {
-			log.Printf("proxy warning [%s]: request rejected due to missing API key", provider.DisplayName)
-			http.Error(w, "upstream API key is not configured", http.StatusServiceUnavailable)
-			return
-		}
-		if !validateClientToken(r.Header.Get(headerAuthorization), clientToken) {
-			log.Printf("proxy warning [%s]: request rejected due to missing or invalid client bearer token", provider.DisplayName)
-			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
-			return
-		}
-
-		proxy.ServeHTTP(w, r)
-	})
+	return strings.Join(parts, ", ")
 }
 
-// rewriteRequest adjusts the outbound request before it is sent upstream.
-func rewriteRequest(req *http.Request, inboundPath, inboundRawPath string, provider providerProxy) {
-	req.URL.Scheme = provider.Target.Scheme
-	req.URL.Host = provider.Target.Host
-	req.Host = provider.Target.Host
-
-	trimmedPath := strings.TrimPrefix(inboundPath, provider.Prefix)
-	if trimmedPath == inboundPath {
-		trimmedPath = ""
-	}
-
-	basePath := provider.Target.Path
-	extraPath := singleLeadingSlash(trimmedPath)
-	req.URL.Path = joinURLPath(basePath, extraPath)
-
-	trimmedRaw := ""
-	if inboundRawPath != "" {
-		trimmedRaw = strings.TrimPrefix(inboundRawPath, provider.Prefix)
-		if trimmedRaw == inboundRawPath {
-			trimmedRaw = ""
-		}
-	}
-	if trimmedRaw != "" {
-		req.URL.RawPath = joinURLPath(basePath, singleLeadingSlash(trimmedRaw))
-	} else {
-		req.URL.RawPath = req.URL.Path
-	}
-
-	stripSensitiveHeaders(req.Header)
-
-	if provider.HeaderName == headerAuthorization {
-		req.Header.Set(headerAuthorization, provider.HeaderValue)
-	} else if provider.HeaderName != "" {
-		req.Header.Set(provider.HeaderName, provider.HeaderValue)
-	}
+// mountProxy attaches a provider to the HTTP mux.
+func mountProxy(mux *http.ServeMux, p provider, clients *clientRegistry, audit *auditConfig) {
+	handler := buildProviderHandler(p, clients, audit)
+	mux.Handle(p.prefix, handler)
 }
 
-// stripSensitiveHeaders removes inbound authentication headers that should not propagate upstream.
-func stripSensitiveHeaders(header http.Header) {
-	header.Del(headerAuthorization)
-	header.Del(headerAnthropicAPIKey)
+// retryAfterSeconds rounds d up to whole seconds, with a floor of one
+// second so a Retry-After header is never advertised as already elapsed.
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
 }
 
 // joinURLPath concatenates base and additional path segments.
@@ -257,28 +181,6 @@ func singleLeadingSlash(path string) string {
 	return path
 }
 
-// withLogging wraps the handler with structured request logging.
-func withLogging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		next.ServeHTTP(w, r)
-		duration := time.Since(start)
-		remote := remoteAddr(r.Context(), r.RemoteAddr)
-		if r.URL.Path != healthPath {
-			log.Printf("proxy request method=%s path=%s remote=%s duration=%s",
-				r.Method, r.URL.Path, remote, duration)
-		}
-	})
-}
-
-// remoteAddr normalizes the remote address for logging.
-func remoteAddr(ctx context.Context, fallback string) string {
-	if peer, ok := ctx.Value(http.LocalAddrContextKey).(net.Addr); ok {
-		return peer.String()
-	}
-	return fallback
-}
-
 // handleHealth responds to health check requests.
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
@@ -318,20 +220,3 @@ func firstNonEmpty(values ...string) string {
 	}
 	return ""
 }
-
-// validateClientToken ensures inbound requests present the proxy bearer secret using
-// a constant-time comparison to avoid leaking timing information.
-func validateClientToken(headerValue, expectedToken string) bool {
-	if expectedToken == "" {
-		return false
-	}
-	parts := strings.SplitN(headerValue, " ", 2)
-	if len(parts) != 2 {
-		return false
-	}
-	if !strings.EqualFold(parts[0], "bearer") {
-		return false
-	}
-	provided := strings.TrimSpace(parts[1])
-	return subtle.ConstantTimeCompare([]byte(provided), []byte(expectedToken)) == 1
-}
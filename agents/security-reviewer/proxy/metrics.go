@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsPath is the HTTP endpoint serving Prometheus text-format metrics.
+const metricsPath = "/metrics"
+
+// requestDurationBuckets are the upper bounds, in seconds, of the
+// proxy_request_duration_seconds histogram. They're spread wide and
+// log-ish to cover both fast metadata calls and multi-minute streaming
+// completions, since WriteTimeout allows responses up to an hour.
+var requestDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// proxyRequestsTotal counts completed requests by provider, client, and
+// final status code.
+var proxyRequestsTotal = newCounterVec("proxy_requests_total", "Total proxied requests.", "provider", "client", "status")
+
+// proxyUpstreamErrorsTotal counts requests where the upstream (or the proxy
+// itself, via RecoverPanics) returned a 5xx.
+var proxyUpstreamErrorsTotal = newCounterVec("proxy_upstream_errors_total", "Requests that failed with a 5xx response.", "provider")
+
+// proxyBytesSentTotal counts response bytes written back to clients.
+var proxyBytesSentTotal = newCounterVec("proxy_bytes_sent_total", "Response bytes sent to clients.", "provider")
+
+// proxyBytesReceivedTotal counts request body bytes read from clients.
+var proxyBytesReceivedTotal = newCounterVec("proxy_bytes_received_total", "Request body bytes received from clients.", "provider")
+
+// proxyInflightRequests tracks requests currently being handled, per
+// provider.
+var proxyInflightRequests = newGaugeVec("proxy_inflight_requests", "Requests currently being proxied.", "provider")
+
+// proxyRequestDurationSeconds observes end-to-end request latency.
+var proxyRequestDurationSeconds = newHistogramVec("proxy_request_duration_seconds", "End-to-end proxied request latency in seconds.", requestDurationBuckets, "provider", "client")
+
+// Metrics instruments every request reaching p's upstream: in-flight
+// gauge, byte counters, the duration histogram, and the status/error
+// counters. It sits innermost in the chain (see buildProviderHandler) so
+// it measures the same latency and byte counts the client actually
+// experiences.
+func Metrics(p provider) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxyInflightRequests.inc(p.name)
+			defer proxyInflightRequests.dec(p.name)
+
+			if r.Body != nil {
+				r.Body = &countingReadCloser{ReadCloser: r.Body, provider: p.name}
+			}
+
+			rec := newMetricsRecorder(w, p.name)
+			start := time.Now()
+			next.ServeHTTP(rec, r)
+			duration := time.Since(start)
+
+			client := clientName(r.Context())
+			status := strconv.Itoa(rec.status)
+
+			proxyRequestsTotal.inc(p.name, client, status)
+			proxyRequestDurationSeconds.observe(duration.Seconds(), p.name, client)
+			if rec.status >= 500 {
+				proxyUpstreamErrorsTotal.inc(p.name)
+			}
+		})
+	}
+}
+
+// countingReadCloser tallies bytes read from a request body into
+// proxyBytesReceivedTotal as they're consumed.
+type countingReadCloser struct {
+	io.ReadCloser
+	provider string
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		proxyBytesReceivedTotal.add(int64(n), c.provider)
+	}
+	return n, err
+}
+
+// metricsRecorder wraps an http.ResponseWriter to capture its status and
+// tally bytes written into proxyBytesSentTotal, while still forwarding
+// http.Flusher for streaming responses.
+type metricsRecorder struct {
+	http.ResponseWriter
+	provider    string
+	status      int
+	wroteHeader bool
+}
+
+func newMetricsRecorder(w http.ResponseWriter, provider string) *metricsRecorder {
+	return &metricsRecorder{ResponseWriter: w, provider: provider, status: http.StatusOK}
+}
+
+func (r *metricsRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *metricsRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+	}
+	n, err := r.ResponseWriter.Write(b)
+	if n > 0 {
+		proxyBytesSentTotal.add(int64(n), r.provider)
+	}
+	return n, err
+}
+
+func (r *metricsRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// handleMetrics renders every registered metric in Prometheus text
+// exposition format.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	proxyRequestsTotal.render(bw)
+	proxyUpstreamErrorsTotal.render(bw)
+	proxyBytesSentTotal.render(bw)
+	proxyBytesReceivedTotal.render(bw)
+	proxyInflightRequests.render(bw)
+	proxyRequestDurationSeconds.render(bw)
+}
+
+// metricLabelKey joins label values into a map key, in the declared label
+// order, so two metrics with the same labels dedupe correctly.
+func metricLabelKey(values ...string) string {
+	return strings.Join(values, "\xff")
+}
+
+// counterVec is a monotonic counter keyed by a label tuple.
+type counterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]int64
+	labels map[string][]string
+}
+
+func newCounterVec(name, help string, labelNames ...string) *counterVec {
+	return &counterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]int64),
+		labels:     make(map[string][]string),
+	}
+}
+
+func (c *counterVec) inc(labelValues ...string) { c.add(1, labelValues...) }
+
+func (c *counterVec) add(delta int64, labelValues ...string) {
+	key := metricLabelKey(labelValues...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += delta
+	if _, ok := c.labels[key]; !ok {
+		c.labels[key] = append([]string(nil), labelValues...)
+	}
+}
+
+func (c *counterVec) render(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %d\n", c.name, formatLabels(c.labelNames, c.labels[key]), c.values[key])
+	}
+}
+
+// gaugeVec is a counter that can also move down, e.g. in-flight counts.
+type gaugeVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]int64
+	labels map[string][]string
+}
+
+func newGaugeVec(name, help string, labelNames ...string) *gaugeVec {
+	return &gaugeVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]int64),
+		labels:     make(map[string][]string),
+	}
+}
+
+func (g *gaugeVec) inc(labelValues ...string) { g.add(1, labelValues...) }
+func (g *gaugeVec) dec(labelValues ...string) { g.add(-1, labelValues...) }
+
+func (g *gaugeVec) add(delta int64, labelValues ...string) {
+	key := metricLabelKey(labelValues...)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[key] += delta
+	if _, ok := g.labels[key]; !ok {
+		g.labels[key] = append([]string(nil), labelValues...)
+	}
+}
+
+func (g *gaugeVec) render(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+	for _, key := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %d\n", g.name, formatLabels(g.labelNames, g.labels[key]), g.values[key])
+	}
+}
+
+// histogramVec buckets observations the way Prometheus expects: each
+// bucket's count is cumulative over every observation less than or equal
+// to its upper bound, alongside a running sum and count for the metric's
+// _sum/_count lines.
+type histogramVec struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+
+	mu          sync.Mutex
+	bucketCount map[string][]int64
+	sum         map[string]float64
+	count       map[string]int64
+	labels      map[string][]string
+}
+
+func newHistogramVec(name, help string, buckets []float64, labelNames ...string) *histogramVec {
+	return &histogramVec{
+		name:        name,
+		help:        help,
+		buckets:     buckets,
+		labelNames:  labelNames,
+		bucketCount: make(map[string][]int64),
+		sum:         make(map[string]float64),
+		count:       make(map[string]int64),
+		labels:      make(map[string][]string),
+	}
+}
+
+func (h *histogramVec) observe(value float64, labelValues ...string) {
+	key := metricLabelKey(labelValues...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts, ok := h.bucketCount[key]
+	if !ok {
+		counts = make([]int64, len(h.buckets))
+		h.bucketCount[key] = counts
+		h.labels[key] = append([]string(nil), labelValues...)
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			counts[i]++
+		}
+	}
+	h.sum[key] += value
+	h.count[key]++
+}
+
+func (h *histogramVec) render(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.count) {
+		labels := h.labels[key]
+		counts := h.bucketCount[key]
+		for i, bound := range h.buckets {
+			bucketLabels := append(append([]string(nil), labels...), strconv.FormatFloat(bound, 'g', -1, 64))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(append(append([]string(nil), h.labelNames...), "le"), bucketLabels), counts[i])
+		}
+		infLabels := append(append([]string(nil), labels...), "+Inf")
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(append(append([]string(nil), h.labelNames...), "le"), infLabels), h.count[key])
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, labels), strconv.FormatFloat(h.sum[key], 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, labels), h.count[key])
+	}
+}
+
+// formatLabels renders a Prometheus label set, e.g. `{provider="OpenAI"}`,
+// or "" when there are no label names.
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%q", name, values[i])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// sortedKeys returns m's keys in a stable order, so /metrics output (and
+// its tests) don't flap between scrapes.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware wraps a handler with additional behavior: logging, auth, rate
+// limiting, request rewriting, and so on. It's free to run code before
+// calling next, after, both, or instead of (e.g. to short-circuit with an
+// error response).
+type Middleware func(next http.Handler) http.Handler
+
+// Chain composes middlewares into a single Middleware. The first
+// middleware listed is outermost: Chain(a, b, c)(h) behaves like
+// a(b(c(h))), so a sees the request first and runs last on the way out.
+func Chain(mw ...Middleware) Middleware {
+	return func(final http.Handler) http.Handler {
+		h := final
+		for i := len(mw) - 1; i >= 0; i-- {
+			h = mw[i](h)
+		}
+		return h
+	}
+}
+
+// ForkMiddlewares lets a fork of this proxy insert its own middleware
+// (e.g. content moderation, PII redaction) into every provider's chain
+// without editing buildProviderHandler. They run after RateLimit and
+// before StripSensitiveHeaders: by then the request carries an
+// authenticated, in-quota principal, but its headers and URL haven't yet
+// been rewritten for the upstream. Populate this from an init() in an
+// additional file so upstream merges don't conflict with it.
+var ForkMiddlewares []Middleware
+
+// buildProviderHandler composes the standard middleware chain around the
+// given provider's reverse proxy.
+func buildProviderHandler(p provider, clients *clientRegistry, audit *auditConfig) http.Handler {
+	proxy := newReverseProxy(p)
+
+	mw := []Middleware{
+		RequestID,
+		AccessLog,
+		AuthClient(clients),
+		AuthorizeProvider(p),
+		RateLimit(p),
+	}
+	mw = append(mw, ForkMiddlewares...)
+	mw = append(mw,
+		StripSensitiveHeaders,
+		Audit(p, audit),
+		RewriteUpstream(p),
+		InjectUpstreamAuth(p),
+		RecoverPanics,
+		Metrics(p),
+	)
+
+	return matchPrefix(p.prefix, Chain(mw...)(proxy))
+}
+
+// matchPrefix 404s any request outside prefix before it enters the
+// middleware chain.
+func matchPrefix(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
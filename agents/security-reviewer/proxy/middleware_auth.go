@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// AuthClient authenticates the bearer token in the inbound Authorization
+// header against clients, stashing the matched principal in the request
+// context for later middlewares (and AccessLog) to read. It responds 401
+// and doesn't call next on failure.
+func AuthClient(clients *clientRegistry) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, ok := clients.authenticate(r.Header.Get(headerAuthorization))
+			if !ok {
+				log.Printf("proxy warning: request rejected due to missing or invalid client bearer token")
+				http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+			*r = *r.WithContext(withPrincipal(r.Context(), client))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthorizeProvider rejects requests from a principal (set by AuthClient,
+// which must run first) that isn't scoped to p's prefix, with 403 rather
+// than 401 since the token itself is valid.
+func AuthorizeProvider(p provider) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _ := principalFromContext(r.Context())
+			if client == nil || !client.allowsPrefix(p.prefix) {
+				log.Printf("proxy warning [%s]: client=%s rejected, not scoped to this provider", p.name, clientName(r.Context()))
+				http.Error(w, "client is not authorized for this provider", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimit enforces the authenticated principal's per-minute and per-day
+// request budgets, responding 429 with Retry-After on exhaustion.
+func RateLimit(p provider) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _ := principalFromContext(r.Context())
+			if client == nil {
+				http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+			if ok, retryAfter := client.checkQuota(); !ok {
+				log.Printf("proxy warning [%s]: client=%s rejected, quota exhausted", p.name, client.name)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+				http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// requestIDContextKey is the context.Context key RequestID stores the
+// per-request ID under.
+type requestIDContextKey struct{}
+
+// RequestID assigns every request a short random ID, stashing it in the
+// request context and echoing it back as the X-Request-Id response
+// header, so a single log line (or an upstream bug report) can be
+// correlated across AccessLog and any fork-added middleware.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-Id", id)
+		*r = *r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDContextKey{}).(string); ok {
+		return id
+	}
+	return "-"
+}
+
+func newRequestID() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// AccessLog logs one line per request (other than health checks) once it
+// completes, carrying the request ID, the authenticated client's display
+// name (set by AuthClient, "-" if authentication never succeeded), and the
+// response status.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == healthPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := newStatusRecorder(w)
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		duration := time.Since(start)
+
+		log.Printf("proxy request id=%s method=%s path=%s remote=%s client=%s status=%d duration=%s",
+			requestIDFromContext(r.Context()), r.Method, r.URL.Path, remoteAddr(r.Context(), r.RemoteAddr),
+			clientName(r.Context()), rec.status, duration)
+	})
+}
+
+// clientName returns the authenticated principal's display name, or "-" if
+// the request was never successfully authenticated.
+func clientName(ctx context.Context) string {
+	if p, ok := principalFromContext(ctx); ok {
+		return p.name
+	}
+	return "-"
+}
+
+// remoteAddr normalizes the remote address for logging.
+func remoteAddr(ctx context.Context, fallback string) string {
+	if peer, ok := ctx.Value(http.LocalAddrContextKey).(net.Addr); ok {
+		return peer.String()
+	}
+	return fallback
+}
+
+// RecoverPanics recovers a panic from the handlers it wraps, logging it
+// and responding 500 instead of crashing the server. It sits close to the
+// actual proxying rather than outermost, so the simpler middlewares ahead
+// of it (auth, rate limiting) are trusted not to panic.
+func RecoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("proxy error: recovered from panic handling %s: %v", r.URL.Path, rec)
+				http.Error(w, "internal proxy error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code
+// written, while still forwarding http.Flusher so streaming provider
+// responses aren't buffered.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
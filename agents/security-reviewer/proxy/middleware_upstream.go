@@ -0,0 +1,105 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strings"
+)
+
+// newReverseProxy returns a reverse proxy for p whose Director is a no-op:
+// RewriteUpstream, earlier in the middleware chain, fully rewrites the
+// request's URL and Host before the proxy ever sees it, since
+// httputil.ReverseProxy clones the request after the chain has already run.
+func newReverseProxy(p provider) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(*http.Request) {},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("proxy error [%s]: %v", p.name, err)
+			http.Error(w, "upstream request failed", http.StatusBadGateway)
+		},
+	}
+}
+
+// StripSensitiveHeaders removes inbound authentication headers before the
+// request is rewritten for the upstream, so a client's own bearer token or
+// an Anthropic key meant for a different provider never leaks upstream.
+func StripSensitiveHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(headerAuthorization)
+		r.Header.Del(headerAnthropicAPIKey)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RewriteUpstream rebases the request's scheme, host, and path onto p's
+// target and applies p's path/query rewrite rules (e.g. Azure's
+// api-version and deployment-name injection).
+func RewriteUpstream(p provider) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rewriteRequest(r, p)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// InjectUpstreamAuth stamps the provider's own credential onto the
+// outbound request, once its path and headers are otherwise final.
+func InjectUpstreamAuth(p provider) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := p.applyAuth(r); err != nil {
+				log.Printf("proxy error [%s]: injecting credential: %v", p.name, err)
+				http.Error(w, "upstream request failed", http.StatusBadGateway)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rewriteRequest adjusts the outbound request's scheme, host, and path to
+// target p's upstream.
+func rewriteRequest(req *http.Request, p provider) {
+	inboundPath := req.URL.Path
+	inboundRawPath := req.URL.RawPath
+
+	req.URL.Scheme = p.target.Scheme
+	req.URL.Host = p.target.Host
+	req.Host = p.target.Host
+
+	trimmedPath := strings.TrimPrefix(inboundPath, p.prefix)
+	if trimmedPath == inboundPath {
+		trimmedPath = ""
+	}
+
+	basePath := p.target.Path
+	extraPath := singleLeadingSlash(trimmedPath)
+	if p.rewrite.DeploymentEnv != "" {
+		if deployment := strings.TrimSpace(os.Getenv(p.rewrite.DeploymentEnv)); deployment != "" {
+			extraPath = joinURLPath("/openai/deployments/"+deployment, extraPath)
+		}
+	}
+	req.URL.Path = joinURLPath(basePath, extraPath)
+
+	trimmedRaw := ""
+	if inboundRawPath != "" {
+		trimmedRaw = strings.TrimPrefix(inboundRawPath, p.prefix)
+		if trimmedRaw == inboundRawPath {
+			trimmedRaw = ""
+		}
+	}
+	if trimmedRaw != "" {
+		req.URL.RawPath = joinURLPath(basePath, singleLeadingSlash(trimmedRaw))
+	} else {
+		req.URL.RawPath = req.URL.Path
+	}
+
+	if p.rewrite.APIVersion != "" {
+		q := req.URL.Query()
+		q.Set("api-version", p.rewrite.APIVersion)
+		req.URL.RawQuery = q.Encode()
+	}
+}
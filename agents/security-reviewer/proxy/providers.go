@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// providersFileEnv names the environment variable pointing at the YAML
+	// (or JSON, which parses as a YAML subset) file declaring the provider
+	// registry. When unset, the proxy falls back to the built-in OpenAI and
+	// Anthropic providers configured via the legacy PROXY_*_BASE_URL and
+	// *_API_KEY variables.
+	providersFileEnv = "PROXY_PROVIDERS_FILE"
+
+	// authSchemeBearer sets "Authorization: Bearer <credential>".
+	authSchemeBearer = "bearer"
+	// authSchemeAPIKeyHeader sets the Anthropic-style "X-Api-Key" header.
+	authSchemeAPIKeyHeader = "x-api-key"
+	// authSchemeAzureAPIKey sets Azure OpenAI's "api-key" header.
+	authSchemeAzureAPIKey = "azure-api-key"
+	// authSchemeAWSSigV4 signs the outbound request with AWS SigV4, e.g.
+	// for Bedrock, resolving credentials through the standard AWS SDK chain.
+	authSchemeAWSSigV4 = "aws-sigv4"
+	// authSchemeHeader sets an arbitrary header named by Auth.Header, e.g.
+	// Gemini's "x-goog-api-key".
+	authSchemeHeader = "header"
+	// authSchemeNone sends the request upstream unauthenticated, e.g. for a
+	// local Ollama instance.
+	authSchemeNone = "none"
+
+	// headerAzureAPIKey is the header Azure OpenAI expects the API key on.
+	headerAzureAPIKey = "api-key"
+)
+
+// credentialSpec declares where a provider's credential value is read from.
+// Exactly one field should be set; it is ignored for auth schemes that
+// don't need one (authSchemeNone, authSchemeAWSSigV4).
+type credentialSpec struct {
+	// Env names an environment variable holding the credential.
+	Env string `yaml:"env,omitempty"`
+	// File names a file whose trimmed contents are the credential.
+	File string `yaml:"file,omitempty"`
+	// Stdin reads the credential once from standard input at startup.
+	Stdin bool `yaml:"stdin,omitempty"`
+}
+
+// authSpec declares how a provider authenticates outbound requests.
+type authSpec struct {
+	// Scheme selects one of the built-in authentication schemes above.
+	Scheme string `yaml:"scheme"`
+	// Header is the outbound header name, required for authSchemeHeader.
+	Header string `yaml:"header,omitempty"`
+	// Region is the AWS region to sign for, required for authSchemeAWSSigV4.
+	Region string `yaml:"region,omitempty"`
+	// Service is the AWS service name to sign for, defaulting to "bedrock".
+	Service string `yaml:"service,omitempty"`
+}
+
+// readinessSpec declares how /health/readiness probes this provider's
+// reachability. Most upstreams accept a bare HEAD at their base URL; a few
+// (e.g. local Ollama) reject HEAD/OPTIONS and need a real endpoint instead.
+type readinessSpec struct {
+	// Method is the HTTP method used to probe the provider. Defaults to
+	// "HEAD".
+	Method string `yaml:"method,omitempty"`
+	// Path, if set, is probed instead of the provider's base URL, e.g.
+	// "/v1/models".
+	Path string `yaml:"path,omitempty"`
+}
+
+// rewriteSpec declares how an inbound request path is translated for the
+// upstream provider.
+type rewriteSpec struct {
+	// APIVersion, when set, is injected as the "api-version" query
+	// parameter, as Azure OpenAI requires.
+	APIVersion string `yaml:"apiVersion,omitempty"`
+	// DeploymentEnv, when set, names an environment variable holding an
+	// Azure OpenAI deployment name, inserted into the outbound path as
+	// "/openai/deployments/<name>".
+	DeploymentEnv string `yaml:"deploymentEnv,omitempty"`
+}
+
+// providerSpec is a single entry in the providers registry file.
+type providerSpec struct {
+	// Name is the human-readable provider name used in logs and errors.
+	Name string `yaml:"name"`
+	// Prefix is the inbound path prefix routed to this provider.
+	Prefix string `yaml:"prefix"`
+	// BaseURL is the upstream endpoint requests are forwarded to.
+	BaseURL string `yaml:"baseURL"`
+	// Auth declares how outbound requests are authenticated.
+	Auth authSpec `yaml:"auth"`
+	// Credential declares where the auth credential comes from.
+	Credential credentialSpec `yaml:"credential,omitempty"`
+	// Rewrite declares provider-specific path/query rewriting.
+	Rewrite rewriteSpec `yaml:"rewrite,omitempty"`
+	// Readiness declares how /health/readiness probes this provider.
+	Readiness readinessSpec `yaml:"readiness,omitempty"`
+}
+
+// providersFile is the on-disk shape of PROXY_PROVIDERS_FILE.
+type providersFile struct {
+	Providers []providerSpec `yaml:"providers"`
+}
+
+// provider is a providerSpec fully resolved into something ready to serve:
+// its credential has been read and its auth scheme compiled into a closure
+// that stamps outbound requests.
+type provider struct {
+	name          string
+	prefix        string
+	target        *url.URL
+	rewrite       rewriteSpec
+	readiness     readinessSpec
+	applyAuth     func(req *http.Request) error
+	hasCredential bool
+}
+
+// loadProviderSpecs reads the provider registry from path, or returns the
+// built-in OpenAI/Anthropic providers when path is empty.
+func loadProviderSpecs(path string) ([]providerSpec, error) {
+	if path == "" {
+		return defaultProviderSpecs(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var file providersFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(file.Providers) == 0 {
+		return nil, fmt.Errorf("%s declares no providers", path)
+	}
+
+	return file.Providers, nil
+}
+
+// defaultProviderSpecs reproduces the proxy's original hard-coded OpenAI and
+// Anthropic upstreams, so existing deployments that don't set
+// PROXY_PROVIDERS_FILE keep working unchanged.
+func defaultProviderSpecs() []providerSpec {
+	return []providerSpec{
+		{
+			Name:       "OpenAI",
+			Prefix:     openAIInboundPrefix,
+			BaseURL:    firstNonEmpty(os.Getenv("PROXY_OPENAI_BASE_URL"), defaultOpenAIBaseURL),
+			Auth:       authSpec{Scheme: authSchemeBearer},
+			Credential: credentialSpec{Env: "OPENAI_API_KEY"},
+		},
+		{
+			Name:       "Anthropic",
+			Prefix:     anthropicInboundPrefix,
+			BaseURL:    firstNonEmpty(os.Getenv("PROXY_ANTHROPIC_BASE_URL"), defaultAnthropicBaseURL),
+			Auth:       authSpec{Scheme: authSchemeAPIKeyHeader},
+			Credential: credentialSpec{Env: "ANTHROPIC_API_KEY"},
+		},
+	}
+}
+
+// buildProviders resolves every spec into a ready-to-serve provider,
+// rejecting the whole configuration (fail closed) if any provider's
+// credential can't be resolved or its prefix collides with another's.
+func buildProviders(ctx context.Context, specs []providerSpec) ([]provider, error) {
+	providers := make([]provider, 0, len(specs))
+	prefixOwners := make(map[string]string, len(specs))
+
+	for _, spec := range specs {
+		p, err := buildProvider(ctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", displayName(spec), err)
+		}
+
+		if owner, ok := prefixOwners[p.prefix]; ok {
+			return nil, fmt.Errorf("providers %s and %s both claim prefix %s", owner, p.name, p.prefix)
+		}
+		prefixOwners[p.prefix] = p.name
+
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
+
+func displayName(spec providerSpec) string {
+	return firstNonEmpty(spec.Name, spec.Prefix, "<unnamed>")
+}
+
+// buildProvider resolves a single providerSpec's base URL, credential, and
+// auth scheme into a provider.
+func buildProvider(ctx context.Context, spec providerSpec) (provider, error) {
+	if spec.Name == "" {
+		return provider{}, errors.New("name is required")
+	}
+	if spec.Prefix == "" {
+		return provider{}, errors.New("prefix is required")
+	}
+
+	target, err := parseBaseURL(spec.BaseURL)
+	if err != nil {
+		return provider{}, fmt.Errorf("parse base URL: %w", err)
+	}
+
+	applyAuth, err := buildAuthApplier(ctx, spec)
+	if err != nil {
+		return provider{}, err
+	}
+
+	return provider{
+		name:          spec.Name,
+		prefix:        normalizePrefix(spec.Prefix),
+		target:        target,
+		rewrite:       spec.Rewrite,
+		readiness:     spec.Readiness,
+		applyAuth:     applyAuth,
+		hasCredential: spec.Auth.Scheme != authSchemeNone,
+	}, nil
+}
+
+// buildAuthApplier compiles spec's auth scheme into a closure that stamps
+// an outbound request with the resolved credential. Credentials are
+// resolved once at startup, so a provider with no credential available
+// fails closed here rather than on the first inbound request.
+func buildAuthApplier(ctx context.Context, spec providerSpec) (func(req *http.Request) error, error) {
+	switch spec.Auth.Scheme {
+	case authSchemeNone:
+		return func(*http.Request) error { return nil }, nil
+
+	case authSchemeAWSSigV4:
+		return buildSigV4Applier(ctx, spec)
+
+	case authSchemeBearer:
+		cred, err := resolveCredential(spec.Credential)
+		if err != nil {
+			return nil, err
+		}
+		return func(req *http.Request) error {
+			req.Header.Set(headerAuthorization, bearerValue(cred))
+			return nil
+		}, nil
+
+	case authSchemeAPIKeyHeader:
+		cred, err := resolveCredential(spec.Credential)
+		if err != nil {
+			return nil, err
+		}
+		return func(req *http.Request) error {
+			req.Header.Set(headerAnthropicAPIKey, cred)
+			return nil
+		}, nil
+
+	case authSchemeAzureAPIKey:
+		cred, err := resolveCredential(spec.Credential)
+		if err != nil {
+			return nil, err
+		}
+		return func(req *http.Request) error {
+			req.Header.Set(headerAzureAPIKey, cred)
+			return nil
+		}, nil
+
+	case authSchemeHeader:
+		if spec.Auth.Header == "" {
+			return nil, fmt.Errorf("auth scheme %q requires \"header\"", authSchemeHeader)
+		}
+		cred, err := resolveCredential(spec.Credential)
+		if err != nil {
+			return nil, err
+		}
+		headerName := spec.Auth.Header
+		return func(req *http.Request) error {
+			req.Header.Set(headerName, cred)
+			return nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported auth scheme %q", spec.Auth.Scheme)
+	}
+}
+
+// resolveCredential reads the credential named by spec, failing if none of
+// its sources are configured or the resolved value is empty.
+func resolveCredential(spec credentialSpec) (string, error) {
+	switch {
+	case spec.Env != "":
+		v := strings.TrimSpace(os.Getenv(spec.Env))
+		if v == "" {
+			return "", fmt.Errorf("environment variable %s is not set", spec.Env)
+		}
+		return v, nil
+
+	case spec.File != "":
+		data, err := os.ReadFile(spec.File)
+		if err != nil {
+			return "", fmt.Errorf("reading credential file %s: %w", spec.File, err)
+		}
+		v := strings.TrimSpace(string(data))
+		if v == "" {
+			return "", fmt.Errorf("credential file %s is empty", spec.File)
+		}
+		return v, nil
+
+	case spec.Stdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading credential from stdin: %w", err)
+		}
+		v := strings.TrimSpace(string(data))
+		if v == "" {
+			return "", errors.New("no credential provided on stdin")
+		}
+		return v, nil
+
+	default:
+		return "", errors.New("no credential source configured (set credential.env, credential.file, or credential.stdin)")
+	}
+}
+
+// normalizePrefix ensures prefix has both a leading and a trailing slash,
+// as required by http.ServeMux's prefix-matching pattern syntax.
+func normalizePrefix(prefix string) string {
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket enforces a request budget over a fixed window using the
+// classic token-bucket algorithm: capacity tokens are available up front,
+// refilling continuously at capacity/window per second, so bursts up to
+// capacity are allowed while the long-run rate is capped at capacity per
+// window. A nil bucket is unlimited.
+type tokenBucket struct {
+	mu         sync.Mutex
+	capacity   float64
+	tokens     float64
+	refillRate float64 // tokens per second
+	last       time.Time
+}
+
+// newTokenBucket returns a bucket allowing up to capacity requests per
+// window, or nil (meaning unlimited) when capacity is zero or negative.
+func newTokenBucket(capacity int, window time.Duration) *tokenBucket {
+	if capacity <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		capacity:   float64(capacity),
+		tokens:     float64(capacity),
+		refillRate: float64(capacity) / window.Seconds(),
+		last:       time.Now(),
+	}
+}
+
+// allow reports whether a request may proceed, consuming a token if so. A
+// nil bucket always allows. On denial, retryAfter estimates how long until
+// the next token is available.
+func (b *tokenBucket) allow() (ok bool, retryAfter time.Duration) {
+	if b == nil {
+		return true, 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refillLocked()
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / b.refillRate * float64(time.Second))
+	return false, wait
+}
+
+// refund returns a previously consumed token, for when a later quota check
+// in the same request fails and the earlier one shouldn't count against
+// the caller.
+func (b *tokenBucket) refund() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = minFloat(b.tokens+1, b.capacity)
+}
+
+func (b *tokenBucket) refillLocked() {
+	now := time.Now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens = minFloat(b.tokens+elapsed*b.refillRate, b.capacity)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
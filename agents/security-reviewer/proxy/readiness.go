@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// readinessPath is the HTTP endpoint probed by orchestrators that want
+	// to know the proxy can actually reach its upstreams, as opposed to
+	// healthPath which only confirms the process is alive.
+	readinessPath = "/health/readiness"
+	// readinessCacheTTL bounds how often each provider is actually probed;
+	// concurrent readiness checks within the window reuse the last result
+	// instead of hammering the upstream.
+	readinessCacheTTL = 30 * time.Second
+	// readinessProbeTimeout bounds a single upstream reachability probe.
+	readinessProbeTimeout = 5 * time.Second
+	// readinessDefaultMethod is used when a provider doesn't declare one.
+	readinessDefaultMethod = http.MethodHead
+)
+
+// readinessChecker probes every configured provider's reachability,
+// caching each result for readinessCacheTTL so a readiness check storm
+// (e.g. a Kubernetes liveness+readiness probe on every replica) doesn't
+// turn into a request storm against upstream APIs.
+type readinessChecker struct {
+	providers []provider
+	client    *http.Client
+
+	mu    sync.Mutex
+	cache map[string]readinessResult
+}
+
+// readinessResult is the cached outcome of probing one provider.
+type readinessResult struct {
+	ok        bool
+	detail    string
+	checkedAt time.Time
+}
+
+func newReadinessChecker(providers []provider) *readinessChecker {
+	return &readinessChecker{
+		providers: providers,
+		client:    &http.Client{Timeout: readinessProbeTimeout},
+		cache:     make(map[string]readinessResult),
+	}
+}
+
+// readinessReport is the JSON body served at /health/readiness.
+type readinessReport struct {
+	Status  string                     `json:"status"`
+	Failing []string                   `json:"failing,omitempty"`
+	Checked map[string]readinessDetail `json:"providers"`
+}
+
+// readinessDetail describes one provider's cached probe result.
+type readinessDetail struct {
+	OK        bool      `json:"ok"`
+	Detail    string    `json:"detail,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// handleReadiness responds 200 when every provider is reachable and its
+// credential is non-empty, or 503 with a JSON body naming the providers
+// that aren't.
+func (rc *readinessChecker) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := readinessReport{Checked: make(map[string]readinessDetail, len(rc.providers))}
+
+	for _, p := range rc.providers {
+		result := rc.check(r.Context(), p)
+		report.Checked[p.name] = readinessDetail{OK: result.ok, Detail: result.detail, CheckedAt: result.checkedAt}
+		if !result.ok {
+			report.Failing = append(report.Failing, p.name)
+		}
+	}
+
+	status := http.StatusOK
+	report.Status = "ok"
+	if len(report.Failing) > 0 {
+		status = http.StatusServiceUnavailable
+		report.Status = "degraded"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// check returns the cached result for p if it's still within
+// readinessCacheTTL, otherwise probes it and caches the fresh result.
+func (rc *readinessChecker) check(ctx context.Context, p provider) readinessResult {
+	rc.mu.Lock()
+	cached, ok := rc.cache[p.name]
+	rc.mu.Unlock()
+	if ok && time.Since(cached.checkedAt) < readinessCacheTTL {
+		return cached
+	}
+
+	result := rc.probe(ctx, p)
+
+	rc.mu.Lock()
+	rc.cache[p.name] = result
+	rc.mu.Unlock()
+	return result
+}
+
+// probe performs the actual reachability check and credential check for p.
+func (rc *readinessChecker) probe(ctx context.Context, p provider) readinessResult {
+	now := time.Now()
+
+	if !p.hasCredential {
+		return readinessResult{ok: false, detail: "credential is empty", checkedAt: now}
+	}
+
+	method := firstNonEmpty(p.readiness.Method, readinessDefaultMethod)
+	target := *p.target
+	if p.readiness.Path != "" {
+		target.Path = joinURLPath(target.Path, p.readiness.Path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return readinessResult{ok: false, detail: err.Error(), checkedAt: now}
+	}
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return readinessResult{ok: false, detail: err.Error(), checkedAt: now}
+	}
+	defer resp.Body.Close()
+
+	// Any response at all, even a 4xx (e.g. a bare HEAD against an
+	// endpoint that demands auth or a POST body), proves the upstream
+	// host is reachable; only transport-level failures above count as
+	// "down".
+	return readinessResult{ok: true, detail: "", checkedAt: now}
+}
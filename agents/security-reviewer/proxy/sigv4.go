@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4signer "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// buildSigV4Applier returns an auth applier that signs outbound requests
+// with AWS SigV4, e.g. for Bedrock. Credentials are resolved once at
+// startup through the standard AWS SDK chain (environment variables, the
+// shared config/credentials files, or a container/instance role), so a
+// provider with no AWS credentials available fails closed immediately
+// rather than on the first inbound request.
+func buildSigV4Applier(ctx context.Context, spec providerSpec) (func(req *http.Request) error, error) {
+	if spec.Auth.Region == "" {
+		return nil, fmt.Errorf("auth scheme %q requires \"region\"", authSchemeAWSSigV4)
+	}
+	service := firstNonEmpty(spec.Auth.Service, "bedrock")
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(spec.Auth.Region))
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolving AWS credentials: %w", err)
+	}
+
+	signer := v4signer.NewSigner()
+	region := spec.Auth.Region
+
+	return func(req *http.Request) error {
+		return signRequest(req, signer, creds, service, region)
+	}, nil
+}
+
+// signRequest buffers req's body (SigV4 signs over a hash of the payload),
+// restores it for the proxy to forward, and signs the request in place.
+func signRequest(req *http.Request, signer *v4signer.Signer, creds aws.Credentials, service, region string) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("buffering request body for signing: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+
+	return signer.SignHTTP(req.Context(), creds, req, payloadHash, service, region, time.Now())
+}
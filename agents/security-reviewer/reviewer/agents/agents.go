@@ -0,0 +1,99 @@
+// Package agents is a registry of reviewer agent implementations, keyed by
+// name, so the reviewer binary can select one by flag/env without itself
+// knowing about every CLI it wraps. Each implementation lives in its own
+// subpackage (agents/claude, agents/codex, ...) and registers itself from
+// an init(), the same pattern agents/security-reviewer/proxy uses for its
+// provider registry.
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Invocation captures execution hints passed to an agent.
+type Invocation struct {
+	// Prompt is the rendered instruction text passed over stdin.
+	Prompt string
+	// Model identifies the model to invoke, when the agent supports overrides.
+	Model string
+	// ExtraArgs contains caller-supplied CLI arguments for the agent.
+	ExtraArgs string
+	// WorkingDir specifies the directory where the agent command executes.
+	WorkingDir string
+}
+
+// Agent defines the behavior required of each reviewer agent implementation.
+type Agent interface {
+	Name() string
+	// ModelEnvVar returns the environment variable that overrides the agent's model, or empty when not applicable.
+	ModelEnvVar() string
+	// DefaultAllowedTools reports which tools/capabilities the agent grants
+	// itself by default, for `agents list` to surface without having to
+	// invoke the CLI. A nil slice means the agent doesn't restrict tools at
+	// all, e.g. because it bypasses approvals/sandboxing entirely.
+	DefaultAllowedTools() []string
+	// BuildCommand returns the configured command used to invoke the agent.
+	BuildCommand(ctx context.Context, inv Invocation) (*exec.Cmd, error)
+}
+
+var (
+	mu        sync.Mutex
+	factories = make(map[string]func(cfg AgentConfig) (Agent, error))
+)
+
+// RegisterAgent adds an agent factory under name, so a later Get(name, cfg)
+// can construct it. Implementations call this from their own package's
+// init(). cfg is whatever AgentConfig a .mcp-registry/agents.yaml declared
+// for name, or the zero value when none was; built-in agents use it to let
+// a config entry override their executable, model env var, extra args, and
+// so on without forking the Go implementation.
+func RegisterAgent(name string, factory func(cfg AgentConfig) (Agent, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+// Get constructs the agent registered under name, passing it cfg (the
+// config entry declared for name, if any). If name has no Go factory but
+// cfg declares an Executable, Get builds a genericAgent from cfg directly -
+// this is how agents.yaml adds a reviewer (e.g. "gemini", "ollama") without
+// patching this package.
+func Get(name string, cfg AgentConfig) (Agent, error) {
+	mu.Lock()
+	factory, ok := factories[name]
+	mu.Unlock()
+	if ok {
+		return factory(cfg)
+	}
+	if cfg.Executable != "" {
+		return newGenericAgent(name, cfg), nil
+	}
+	return nil, fmt.Errorf("unsupported review agent: %s (known agents: %s)", name, strings.Join(names(), ", "))
+}
+
+// RegisteredNames returns the names of every agent with a built-in Go
+// factory, sorted for deterministic output. It doesn't include names only
+// discovered via agents.yaml or a plugin manifest - callers that want the
+// full picture (e.g. the `agents list` subcommand) fold those in
+// separately, since only the caller knows where to load them from.
+func RegisteredNames() []string {
+	return names()
+}
+
+// names returns every currently registered agent name, sorted for
+// deterministic error messages.
+func names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]string, 0, len(factories))
+	for name := range factories {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,207 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// fakeAgent is a throwaway agents.Agent used to exercise RegisterAgent/Get
+// without shelling out to a real reviewer CLI.
+type fakeAgent struct {
+	name string
+	cfg  AgentConfig
+}
+
+func (a fakeAgent) Name() string                  { return a.name }
+func (a fakeAgent) ModelEnvVar() string           { return a.cfg.ModelEnvVar }
+func (a fakeAgent) DefaultAllowedTools() []string { return nil }
+func (a fakeAgent) BuildCommand(ctx context.Context, inv Invocation) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, "true"), nil
+}
+
+func TestRegisterAgentRoundTrip(t *testing.T) {
+	const name = "fake-test-agent"
+	RegisterAgent(name, func(cfg AgentConfig) (Agent, error) {
+		return fakeAgent{name: name, cfg: cfg}, nil
+	})
+
+	agent, err := Get(name, AgentConfig{ModelEnvVar: "FAKE_MODEL"})
+	if err != nil {
+		t.Fatalf("Get(%q): %v", name, err)
+	}
+	if agent.Name() != name {
+		t.Errorf("Name() = %q, want %q", agent.Name(), name)
+	}
+	if agent.ModelEnvVar() != "FAKE_MODEL" {
+		t.Errorf("ModelEnvVar() = %q, want FAKE_MODEL", agent.ModelEnvVar())
+	}
+}
+
+func TestGetUnregisteredWithExecutableIsGeneric(t *testing.T) {
+	agent, err := Get("unregistered-generic-agent", AgentConfig{Executable: "/bin/true"})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if agent.Name() != "unregistered-generic-agent" {
+		t.Errorf("Name() = %q, want unregistered-generic-agent", agent.Name())
+	}
+}
+
+func TestRegisteredNamesIncludesBuiltIns(t *testing.T) {
+	const name = "fake-registered-names-agent"
+	RegisterAgent(name, func(cfg AgentConfig) (Agent, error) {
+		return fakeAgent{name: name, cfg: cfg}, nil
+	})
+
+	found := false
+	for _, n := range RegisteredNames() {
+		if n == name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("RegisteredNames() = %v, want it to include %q", RegisteredNames(), name)
+	}
+}
+
+func TestGenericAgentDefaultAllowedTools(t *testing.T) {
+	agent := newGenericAgent("gemini", AgentConfig{DefaultAllowedTools: "Read,Grep"})
+	want := []string{"Read,Grep"}
+	got := agent.DefaultAllowedTools()
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("DefaultAllowedTools() = %v, want %v", got, want)
+	}
+
+	empty := newGenericAgent("gemini", AgentConfig{})
+	if got := empty.DefaultAllowedTools(); got != nil {
+		t.Errorf("DefaultAllowedTools() with no config = %v, want nil", got)
+	}
+}
+
+func TestGetUnregisteredWithoutExecutableFails(t *testing.T) {
+	if _, err := Get("totally-unknown-agent", AgentConfig{}); err == nil {
+		t.Fatal("Get: expected error for unregistered agent with no Executable, got nil")
+	}
+}
+
+func TestLoadConfigMissingFileIsEmpty(t *testing.T) {
+	configs, err := LoadConfig(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("LoadConfig of a missing file = %v, want empty", configs)
+	}
+}
+
+func TestLoadConfigParsesAgents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	const contents = `
+agents:
+  - name: gemini
+    executable: gemini
+    modelEnvVar: GEMINI_REVIEW_MODEL
+    extraArgs: ["--yolo"]
+    timeout: 45m
+    promptTemplateFile: /opt/security-reviewer/gemini-prompt-template.md
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	configs, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	cfg, ok := configs["gemini"]
+	if !ok {
+		t.Fatalf("LoadConfig: no entry for gemini, got %v", configs)
+	}
+	if cfg.Executable != "gemini" || cfg.ModelEnvVar != "GEMINI_REVIEW_MODEL" {
+		t.Errorf("cfg = %+v, want executable=gemini modelEnvVar=GEMINI_REVIEW_MODEL", cfg)
+	}
+	if len(cfg.ExtraArgs) != 1 || cfg.ExtraArgs[0] != "--yolo" {
+		t.Errorf("cfg.ExtraArgs = %v, want [--yolo]", cfg.ExtraArgs)
+	}
+}
+
+func TestLoadPluginDirMissingDirIsEmpty(t *testing.T) {
+	configs, err := LoadPluginDir(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("LoadPluginDir: %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("LoadPluginDir of a missing dir = %v, want empty", configs)
+	}
+}
+
+func TestLoadPluginDirParsesManifests(t *testing.T) {
+	dir := t.TempDir()
+	const contents = `
+name: gemini
+executable: gemini
+modelEnvVar: GEMINI_REVIEW_MODEL
+defaultAllowedTools: "Read,Grep"
+promptTransport: arg
+argTemplate: "--prompt $PROMPT --tools $ALLOWED_TOOLS"
+`
+	if err := os.WriteFile(filepath.Join(dir, "gemini.yaml"), []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	configs, err := LoadPluginDir(dir)
+	if err != nil {
+		t.Fatalf("LoadPluginDir: %v", err)
+	}
+
+	cfg, ok := configs["gemini"]
+	if !ok {
+		t.Fatalf("LoadPluginDir: no entry for gemini, got %v", configs)
+	}
+	if cfg.Executable != "gemini" || cfg.PromptTransport != PromptTransportArg {
+		t.Errorf("cfg = %+v, want executable=gemini promptTransport=arg", cfg)
+	}
+}
+
+func TestLoadPluginDirRejectsManifestMissingExecutable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("name: broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPluginDir(dir); err == nil {
+		t.Fatal("LoadPluginDir: expected error for manifest missing executable, got nil")
+	}
+}
+
+func TestGenericAgentBuildCommandArgTemplate(t *testing.T) {
+	agent := newGenericAgent("gemini", AgentConfig{
+		Executable:          "/bin/echo",
+		DefaultAllowedTools: "Read,Grep",
+		PromptTransport:     PromptTransportArg,
+		ArgTemplate:         "--prompt $PROMPT --tools $ALLOWED_TOOLS",
+	})
+
+	cmd, err := agent.BuildCommand(context.Background(), Invocation{Prompt: "hello world"})
+	if err != nil {
+		t.Fatalf("BuildCommand: %v", err)
+	}
+	if cmd.Stdin != nil {
+		t.Error("BuildCommand with arg transport set Stdin, want nil")
+	}
+	want := []string{"/bin/echo", "--prompt", "hello world", "--tools", "Read,Grep"}
+	if len(cmd.Args) != len(want) {
+		t.Fatalf("cmd.Args = %v, want %v", cmd.Args, want)
+	}
+	for i, arg := range want {
+		if cmd.Args[i] != arg {
+			t.Errorf("cmd.Args[%d] = %q, want %q", i, cmd.Args[i], arg)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package claude implements agents.Agent for Claude Code.
+package claude
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+
+	"github.com/docker/mcp-registry/agents/security-reviewer/reviewer/agents"
+)
+
+// Name is the stable identifier used in REVIEW_AGENT and the registry.
+const Name = "claude"
+
+// defaultModelEnvVar is the environment variable Claude Code reads its
+// target model from, absent a config override.
+const defaultModelEnvVar = "CLAUDE_REVIEW_MODEL"
+
+func init() {
+	agents.RegisterAgent(Name, func(cfg agents.AgentConfig) (agents.Agent, error) {
+		modelEnvVar := cfg.ModelEnvVar
+		if modelEnvVar == "" {
+			modelEnvVar = defaultModelEnvVar
+		}
+		executable := cfg.Executable
+		if executable == "" {
+			executable = "claude"
+		}
+		return reviewerAgent{executable: executable, modelEnvVar: modelEnvVar}, nil
+	})
+}
+
+// reviewerAgent implements agents.Agent for Claude Code.
+type reviewerAgent struct {
+	executable  string
+	modelEnvVar string
+}
+
+// Name returns the stable identifier for the Claude agent implementation.
+func (reviewerAgent) Name() string {
+	return Name
+}
+
+// ModelEnvVar exposes the environment variable used to override the model.
+func (r reviewerAgent) ModelEnvVar() string {
+	return r.modelEnvVar
+}
+
+// DefaultAllowedTools reports that Claude Code runs with
+// --dangerously-skip-permissions, so there's no allowlist to report: every
+// tool is available by default.
+func (reviewerAgent) DefaultAllowedTools() []string {
+	return []string{"*"}
+}
+
+// BuildCommand constructs the Claude CLI invocation for a review run.
+func (r reviewerAgent) BuildCommand(ctx context.Context, inv agents.Invocation) (*exec.Cmd, error) {
+	// When running Claude Code in non-interactive mode, the only output format
+	// that gives regular progress updates is stream-json - anything else waits
+	// for the full analysis to complete and then provides all the output at
+	// once. It would be nice if Claude Code had something like a stream-text
+	// mode, and there's a request for that here:
+	//   https://github.com/anthropics/claude-code/issues/4346
+	// In the meantime, I think we'll just live with the JSON output, since at
+	// least that gives some indication of progress and what's happening.
+	args := []string{
+		"--print", "--verbose",
+		"--output-format", "stream-json",
+		"--dangerously-skip-permissions",
+	}
+	if strings.TrimSpace(inv.Model) != "" {
+		args = append(args, "--model", inv.Model)
+	}
+	if strings.TrimSpace(inv.ExtraArgs) != "" {
+		parsed, err := shellwords.Parse(inv.ExtraArgs)
+		if err != nil {
+			return nil, fmt.Errorf("parse extra args: %w", err)
+		}
+		args = append(args, parsed...)
+	}
+
+	cmd := exec.CommandContext(ctx, r.executable, args...)
+	cmd.Stdin = strings.NewReader(inv.Prompt)
+	if inv.WorkingDir != "" {
+		cmd.Dir = inv.WorkingDir
+	}
+
+	return cmd, nil
+}
@@ -0,0 +1,85 @@
+// Package codex implements agents.Agent for the OpenAI Codex CLI.
+package codex
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+
+	"github.com/docker/mcp-registry/agents/security-reviewer/reviewer/agents"
+)
+
+// Name is the stable identifier used in REVIEW_AGENT and the registry.
+const Name = "codex"
+
+// defaultModelEnvVar is the environment variable Codex shells read from
+// when provided, absent a config override.
+const defaultModelEnvVar = "CODEX_REVIEW_MODEL"
+
+func init() {
+	agents.RegisterAgent(Name, func(cfg agents.AgentConfig) (agents.Agent, error) {
+		modelEnvVar := cfg.ModelEnvVar
+		if modelEnvVar == "" {
+			modelEnvVar = defaultModelEnvVar
+		}
+		executable := cfg.Executable
+		if executable == "" {
+			executable = "codex"
+		}
+		return reviewerAgent{executable: executable, modelEnvVar: modelEnvVar}, nil
+	})
+}
+
+// reviewerAgent implements agents.Agent for the OpenAI Codex CLI.
+type reviewerAgent struct {
+	executable  string
+	modelEnvVar string
+}
+
+// Name returns the stable identifier for the Codex agent implementation.
+func (reviewerAgent) Name() string {
+	return Name
+}
+
+// ModelEnvVar exposes the environment variable used to override Codex models.
+func (r reviewerAgent) ModelEnvVar() string {
+	return r.modelEnvVar
+}
+
+// DefaultAllowedTools reports that Codex runs with
+// --dangerously-bypass-approvals-and-sandbox, so there's no allowlist to
+// report: every tool is available by default.
+func (reviewerAgent) DefaultAllowedTools() []string {
+	return []string{"*"}
+}
+
+// BuildCommand constructs the Codex CLI invocation for a review run.
+func (r reviewerAgent) BuildCommand(ctx context.Context, inv agents.Invocation) (*exec.Cmd, error) {
+	args := []string{
+		"exec",
+		"--skip-git-repo-check",
+		"--dangerously-bypass-approvals-and-sandbox",
+	}
+	if strings.TrimSpace(inv.Model) != "" {
+		args = append(args, "--model", inv.Model)
+	}
+	if strings.TrimSpace(inv.ExtraArgs) != "" {
+		parsed, err := shellwords.Parse(inv.ExtraArgs)
+		if err != nil {
+			return nil, fmt.Errorf("parse extra args: %w", err)
+		}
+		args = append(args, parsed...)
+	}
+	args = append(args, "-")
+
+	cmd := exec.CommandContext(ctx, r.executable, args...)
+	cmd.Stdin = strings.NewReader(inv.Prompt)
+	if inv.WorkingDir != "" {
+		cmd.Dir = inv.WorkingDir
+	}
+
+	return cmd, nil
+}
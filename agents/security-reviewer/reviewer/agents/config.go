@@ -0,0 +1,262 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PromptTransportStdin writes the rendered prompt to the agent
+	// process's standard input. This is the default, used implicitly
+	// when PromptTransport is unset, and matches how the built-in
+	// Claude/Codex agents take their prompt.
+	PromptTransportStdin = "stdin"
+	// PromptTransportFile writes the rendered prompt to a temporary file
+	// and substitutes its path into ArgTemplate as $PROMPT_FILE, for CLIs
+	// that expect a path rather than stdin.
+	PromptTransportFile = "file"
+	// PromptTransportArg substitutes the prompt text itself into
+	// ArgTemplate as $PROMPT, for CLIs that take it as a positional or
+	// flag argument.
+	PromptTransportArg = "arg"
+)
+
+// AgentConfig declares (or overrides) one reviewer agent, as read from a
+// .mcp-registry/agents.yaml file. Every field is optional: a config entry
+// for a built-in name (e.g. "claude") only overrides the fields it sets,
+// and an entry for an unregistered name needs at least Executable to be
+// usable (see Get).
+type AgentConfig struct {
+	// Name is the registry key this config applies to, e.g. "gemini". When
+	// loaded from the agents list in a config file, it's taken from the
+	// list entry itself rather than this field.
+	Name string `yaml:"name"`
+	// Executable is the CLI to invoke, e.g. "gemini" or "/usr/local/bin/llm".
+	// Required for agents with no built-in Go implementation.
+	Executable string `yaml:"executable"`
+	// ModelEnvVar is the environment variable REVIEW_AGENT's model override
+	// is read from (see Invocation.Model). Overrides a built-in agent's
+	// default when set.
+	ModelEnvVar string `yaml:"modelEnvVar"`
+	// ExtraArgs are CLI arguments appended before REVIEW_AGENT_EXTRA_ARGS,
+	// e.g. flags a custom agent always needs to run non-interactively.
+	ExtraArgs []string `yaml:"extraArgs"`
+	// Timeout bounds how long the reviewer waits for this agent, overriding
+	// the caller's default when non-zero.
+	Timeout time.Duration `yaml:"timeout"`
+	// PromptTemplateFile overrides the prompt template path the reviewer
+	// renders for this agent, for agents that expect a different prompt
+	// format than the bundled default.
+	PromptTemplateFile string `yaml:"promptTemplateFile"`
+	// DefaultAllowedTools is substituted into ArgTemplate as
+	// $ALLOWED_TOOLS, for generic agents whose CLI takes a tool
+	// allowlist flag.
+	DefaultAllowedTools string `yaml:"defaultAllowedTools"`
+	// PromptTransport selects how a generic agent receives the rendered
+	// prompt: one of PromptTransportStdin (default), PromptTransportFile,
+	// or PromptTransportArg.
+	PromptTransport string `yaml:"promptTransport"`
+	// ArgTemplate is a space-separated argument template for generic
+	// agents. It's shell-word-parsed into the command's argument list,
+	// and any token exactly equal to $PROMPT, $PROMPT_FILE, $MODEL, or
+	// $ALLOWED_TOOLS is then replaced with the corresponding value as a
+	// single argument. Ignored by agents with a dedicated Go
+	// implementation, which build their own argument list.
+	ArgTemplate string `yaml:"argTemplate"`
+}
+
+// agentConfigFile is the on-disk shape of a .mcp-registry/agents.yaml.
+type agentConfigFile struct {
+	Agents []AgentConfig `yaml:"agents"`
+}
+
+// LoadConfig reads the agent definitions declared in path (a
+// .mcp-registry/agents.yaml or equivalent), keyed by name. A missing file
+// is not an error: it's the common case when no custom agents are
+// configured, so callers get an empty map back.
+func LoadConfig(path string) (map[string]AgentConfig, error) {
+	configs := map[string]AgentConfig{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return configs, nil
+		}
+		return nil, fmt.Errorf("read agent config %s: %w", path, err)
+	}
+
+	var file agentConfigFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse agent config %s: %w", path, err)
+	}
+
+	for _, cfg := range file.Agents {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("agent config %s: entry missing name", path)
+		}
+		configs[cfg.Name] = cfg
+	}
+	return configs, nil
+}
+
+// LoadPluginDir scans dir for single-agent manifests, one YAML file per
+// agent (see AgentConfig), keyed by name. This is how an operator adds a
+// reviewer CLI (gemini, aider, a local llama.cpp wrapper, ...) by dropping
+// a manifest into the image, rather than editing a shared agents.yaml or
+// recompiling the reviewer. A missing directory is not an error, mirroring
+// LoadConfig.
+func LoadPluginDir(dir string) (map[string]AgentConfig, error) {
+	configs := map[string]AgentConfig{}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return configs, nil
+		}
+		return nil, fmt.Errorf("read agent plugin dir %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isManifestFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read agent plugin %s: %w", path, err)
+		}
+
+		var cfg AgentConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse agent plugin %s: %w", path, err)
+		}
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("agent plugin %s: manifest missing name", path)
+		}
+		if cfg.Executable == "" {
+			return nil, fmt.Errorf("agent plugin %s: manifest missing executable", path)
+		}
+		configs[cfg.Name] = cfg
+	}
+	return configs, nil
+}
+
+// isManifestFile reports whether name looks like a plugin manifest rather
+// than an unrelated file an operator left alongside one.
+func isManifestFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// genericAgent implements Agent for a reviewer CLI declared purely through
+// an agents.yaml entry, with no dedicated Go implementation.
+type genericAgent struct {
+	name string
+	cfg  AgentConfig
+}
+
+// newGenericAgent builds a genericAgent for name, invoking cfg.Executable.
+func newGenericAgent(name string, cfg AgentConfig) genericAgent {
+	return genericAgent{name: name, cfg: cfg}
+}
+
+func (a genericAgent) Name() string {
+	return a.name
+}
+
+func (a genericAgent) ModelEnvVar() string {
+	return a.cfg.ModelEnvVar
+}
+
+// DefaultAllowedTools reports cfg.DefaultAllowedTools verbatim, wrapped in a
+// single-element slice: this package doesn't know whether (or how) the
+// target CLI's $ALLOWED_TOOLS value is itself delimited, so it's not split
+// any further here.
+func (a genericAgent) DefaultAllowedTools() []string {
+	if a.cfg.DefaultAllowedTools == "" {
+		return nil
+	}
+	return []string{a.cfg.DefaultAllowedTools}
+}
+
+// BuildCommand runs cfg.Executable with cfg.ExtraArgs, cfg.ArgTemplate
+// (rendered for cfg.PromptTransport), and then the invocation's own
+// ExtraArgs, the same shell-word parsing built-in agents use for
+// REVIEW_AGENT_EXTRA_ARGS. Since a generic agent's CLI syntax for
+// selecting a model isn't known here, inv.Model is passed through
+// cfg.ModelEnvVar rather than as a flag - the agent's own entrypoint reads
+// it from its environment the same way it would standalone.
+func (a genericAgent) BuildCommand(ctx context.Context, inv Invocation) (*exec.Cmd, error) {
+	transport := a.cfg.PromptTransport
+	if transport == "" {
+		transport = PromptTransportStdin
+	}
+
+	var promptFile string
+	if transport == PromptTransportFile {
+		f, err := os.CreateTemp("", "security-reviewer-prompt-*.md")
+		if err != nil {
+			return nil, fmt.Errorf("create prompt file: %w", err)
+		}
+		if _, err := f.WriteString(inv.Prompt); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("write prompt file: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return nil, fmt.Errorf("write prompt file: %w", err)
+		}
+		promptFile = f.Name()
+	}
+
+	args := append([]string{}, a.cfg.ExtraArgs...)
+	if a.cfg.ArgTemplate != "" {
+		// Parse the template first and substitute placeholders token-by-
+		// token afterward, rather than substituting into the raw string
+		// and re-parsing, so a multi-word prompt or allowlist lands in a
+		// single argument instead of being word-split by shellwords.
+		parsed, err := shellwords.Parse(a.cfg.ArgTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("parse arg template: %w", err)
+		}
+		substitutions := map[string]string{
+			"$PROMPT":        inv.Prompt,
+			"$PROMPT_FILE":   promptFile,
+			"$MODEL":         inv.Model,
+			"$ALLOWED_TOOLS": a.cfg.DefaultAllowedTools,
+		}
+		for i, tok := range parsed {
+			if val, ok := substitutions[tok]; ok {
+				parsed[i] = val
+			}
+		}
+		args = append(args, parsed...)
+	}
+	if strings.TrimSpace(inv.ExtraArgs) != "" {
+		parsed, err := shellwords.Parse(inv.ExtraArgs)
+		if err != nil {
+			return nil, fmt.Errorf("parse extra args: %w", err)
+		}
+		args = append(args, parsed...)
+	}
+
+	cmd := exec.CommandContext(ctx, a.cfg.Executable, args...)
+	if transport == PromptTransportStdin {
+		cmd.Stdin = strings.NewReader(inv.Prompt)
+	}
+	if inv.WorkingDir != "" {
+		cmd.Dir = inv.WorkingDir
+	}
+	if a.cfg.ModelEnvVar != "" && inv.Model != "" {
+		cmd.Env = append(os.Environ(), a.cfg.ModelEnvVar+"="+inv.Model)
+	}
+	return cmd, nil
+}
@@ -1,17 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/docker/mcp-registry/agents/security-reviewer/reviewer/agents"
+	_ "github.com/docker/mcp-registry/agents/security-reviewer/reviewer/agents/claude"
+	_ "github.com/docker/mcp-registry/agents/security-reviewer/reviewer/agents/codex"
+	"github.com/docker/mcp-registry/pkg/sandbox"
+	"github.com/docker/mcp-registry/pkg/sarif"
 )
 
 const (
@@ -27,12 +38,27 @@ const (
 	defaultReportPath = "/workspace/output/report.md"
 	// defaultLabelsPath is the expected location for the agent's label output.
 	defaultLabelsPath = "/workspace/output/labels.txt"
+	// defaultSarifPath is the expected location for the agent's structured findings.
+	defaultSarifPath = "/workspace/output/report.sarif"
+	// sarifToolName identifies this agent as the SARIF log's tool.driver.name.
+	sarifToolName = "mcp-registry-security-reviewer"
 	// defaultReviewAgent is the reviewer implementation used when none is specified.
 	defaultReviewAgent = "claude"
 	// defaultAgentWorkingDir is the directory from which the agent executes.
 	defaultAgentWorkingDir = "/workspace"
 	// defaultTimeout bounds how long the reviewer will wait for the agent to complete.
 	defaultTimeout = time.Hour
+	// defaultAgentConfigPath is where a repo can declare custom reviewer
+	// agents (see agents.LoadConfig), absent --agent-config/$REVIEW_AGENT_CONFIG.
+	defaultAgentConfigPath = ".mcp-registry/agents.yaml"
+	// defaultAgentPluginDir is scanned for single-agent manifests (see
+	// agents.LoadPluginDir), absent --agent-plugin-dir/$REVIEW_AGENT_PLUGIN_DIR.
+	// Operators drop a manifest here to add a reviewer CLI without
+	// rebuilding the image or patching agentConfigPath's shared file.
+	defaultAgentPluginDir = "/opt/security-reviewer/agents.d"
+	// defaultMaxRetries bounds how many times runAgent retries a failed
+	// invocation - rate-limited or merely transient - before giving up.
+	defaultMaxRetries = 3
 
 	// envReviewAgent selects which reviewer agent to run.
 	envReviewAgent = "REVIEW_AGENT"
@@ -46,6 +72,16 @@ const (
 	envReviewTarget = "REVIEW_TARGET_LABEL"
 	// envReviewTimeout allows callers to override the agent execution timeout in seconds.
 	envReviewTimeout = "REVIEW_TIMEOUT_SECS"
+	// envAgentConfigPath allows callers to override defaultAgentConfigPath.
+	envAgentConfigPath = "REVIEW_AGENT_CONFIG"
+	// envAgentPluginDir allows callers to override defaultAgentPluginDir.
+	envAgentPluginDir = "REVIEW_AGENT_PLUGIN_DIR"
+	// envReviewSarifPath allows callers to override defaultSarifPath.
+	envReviewSarifPath = "REVIEW_SARIF_PATH"
+	// envReviewSandbox selects the sandbox.Mode the agent runs under.
+	envReviewSandbox = "REVIEW_SANDBOX"
+	// envReviewMaxRetries allows callers to override defaultMaxRetries.
+	envReviewMaxRetries = "REVIEW_MAX_RETRIES"
 )
 
 // ReviewMode enumerates supported security review modes.
@@ -58,18 +94,6 @@ const (
 	ReviewModeDifferential ReviewMode = "differential"
 )
 
-// agentInvocation captures execution hints per reviewer agent.
-type agentInvocation struct {
-	// Prompt is the rendered instruction text passed over stdin.
-	Prompt string
-	// Model identifies the model to invoke, when the agent supports overrides.
-	Model string
-	// ExtraArgs contains caller-supplied CLI arguments for the agent.
-	ExtraArgs string
-	// WorkingDir specifies the directory where the agent command executes.
-	WorkingDir string
-}
-
 // promptPlaceholders stores values substituted into the static prompt template.
 type promptPlaceholders struct {
 	// ModeLabel is the human friendly descriptor for the review mode.
@@ -92,24 +116,112 @@ type promptPlaceholders struct {
 	ReportPath string
 	// LabelsPath denotes where the agent should write labels for automation.
 	LabelsPath string
+	// SarifPath denotes where the agent should write structured findings.
+	SarifPath string
 	// ReportTemplatePath tells the agent which template to follow exactly.
 	ReportTemplatePath string
 }
 
 // main configures logging, resolves environment, and runs the selected agent.
 func main() {
+	// Must run before anything else: on Linux, this is also how the
+	// sandbox trampoline dispatches back into this binary to apply
+	// restrictions before exec'ing the real agent (see pkg/sandbox).
+	if sandbox.Init() {
+		return
+	}
+
+	// "agents list" is the only subcommand this binary has, so it's
+	// dispatched ahead of the ordinary flag set rather than pulling in a
+	// CLI framework for one verb.
+	if len(os.Args) > 1 && os.Args[1] == "agents" {
+		if err := runAgentsCommand(os.Args[2:]); err != nil {
+			logError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	agentConfigPath := flag.String("agent-config", "", "Path to a YAML file declaring custom reviewer agents (default: $REVIEW_AGENT_CONFIG or "+defaultAgentConfigPath+")")
+	agentPluginDir := flag.String("agent-plugin-dir", "", "Directory of single-agent YAML manifests to discover reviewer agents from (default: $REVIEW_AGENT_PLUGIN_DIR or "+defaultAgentPluginDir+")")
+	flag.Parse()
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	// Run the review workflow and exit non-zero on failure so the container signals an error.
-	if err := run(ctx); err != nil {
+	if err := run(ctx, *agentConfigPath, *agentPluginDir); err != nil {
 		logError(err)
 		os.Exit(1)
 	}
 }
 
+// runAgentsCommand implements the "agents" subcommand, currently just
+// "agents list": enumerating every reviewer agent selectable via
+// REVIEW_AGENT/--agent, including ones discovered via --agent-config/
+// --agent-plugin-dir, alongside their ModelEnvVar() and
+// DefaultAllowedTools().
+func runAgentsCommand(args []string) error {
+	fs := flag.NewFlagSet("agents", flag.ContinueOnError)
+	agentConfigPath := fs.String("agent-config", "", "Path to a YAML file declaring custom reviewer agents (default: $REVIEW_AGENT_CONFIG or "+defaultAgentConfigPath+")")
+	agentPluginDir := fs.String("agent-plugin-dir", "", "Directory of single-agent YAML manifests to discover reviewer agents from (default: $REVIEW_AGENT_PLUGIN_DIR or "+defaultAgentPluginDir+")")
+
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("usage: %s agents list [--agent-config path] [--agent-plugin-dir dir]", filepath.Base(os.Args[0]))
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	agentConfigs, _, _, err := resolveAgentConfigs(*agentConfigPath, *agentPluginDir)
+	if err != nil {
+		return err
+	}
+	return listAgents(os.Stdout, agentConfigs)
+}
+
+// listAgents prints one line per agent name known either from a built-in Go
+// factory or from agentConfigs, sorted for stable output.
+func listAgents(out io.Writer, agentConfigs map[string]agents.AgentConfig) error {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range agents.RegisteredNames() {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range agentConfigs {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		agent, err := agents.Get(name, agentConfigs[name])
+		if err != nil {
+			return err
+		}
+		modelEnvVar := agent.ModelEnvVar()
+		if modelEnvVar == "" {
+			modelEnvVar = "-"
+		}
+		allowedTools := strings.Join(agent.DefaultAllowedTools(), ",")
+		if allowedTools == "" {
+			allowedTools = "-"
+		}
+		fmt.Fprintf(out, "%s\tmodel_env=%s\tallowed_tools=%s\n", name, modelEnvVar, allowedTools)
+	}
+	return nil
+}
+
 // run orchestrates prompt generation and agent execution.
-func run(ctx context.Context) error {
+func run(ctx context.Context, agentConfigPath, agentPluginDir string) error {
+	agentConfigs, agentConfigPath, agentPluginDir, err := resolveAgentConfigs(agentConfigPath, agentPluginDir)
+	if err != nil {
+		return err
+	}
+
 	// Parse review configuration from the environment.
 	headSHA, err := fetchEnv(envReviewHeadSHA, true)
 	if err != nil {
@@ -130,36 +242,51 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	sarifPath := defaultSarifPath
+	if fromEnv := strings.TrimSpace(os.Getenv(envReviewSarifPath)); fromEnv != "" {
+		sarifPath = fromEnv
+	}
+
 	// Ensure the repository mount is present before processing.
 	if err = ensureDirectory(defaultRepositoryPath); err != nil {
 		return err
 	}
 
-	promptContent, err := buildPromptContent(mode, targetLabel, headSHA, baseSHA)
+	// Select the reviewer implementation before rendering the prompt, since
+	// a config entry can point it at a different prompt template.
+	agentName, err := fetchEnv(envReviewAgent, false)
 	if err != nil {
 		return err
 	}
-	if err = ensureParent(defaultPromptPath); err != nil {
+	if agentName == "" {
+		agentName = defaultReviewAgent
+	}
+	agentKey := strings.ToLower(strings.TrimSpace(agentName))
+	agentCfg := agentConfigs[agentKey]
+	agent, err := agents.Get(agentKey, agentCfg)
+	if err != nil {
+		if discovered := discoveredAgentNames(agentConfigs); len(discovered) > 0 {
+			return fmt.Errorf("%w (discovered via %s/%s: %s)", err, agentConfigPath, agentPluginDir, strings.Join(discovered, ", "))
+		}
 		return err
 	}
-	if err = os.WriteFile(defaultPromptPath, []byte(promptContent), 0o644); err != nil {
-		return fmt.Errorf("write prompt: %w", err)
+
+	agentPromptTemplatePath := promptTemplatePath
+	if agentCfg.PromptTemplateFile != "" {
+		agentPromptTemplatePath = agentCfg.PromptTemplateFile
 	}
-	logInfo(fmt.Sprintf("Rendered prompt to %s.", defaultPromptPath))
 
-	// Select the reviewer implementation and build invocation parameters.
-	agentName, err := fetchEnv(envReviewAgent, false)
+	promptContent, err := buildPromptContent(agentPromptTemplatePath, mode, targetLabel, headSHA, baseSHA, sarifPath)
 	if err != nil {
 		return err
 	}
-	if agentName == "" {
-		agentName = defaultReviewAgent
-	}
-	agentKey := strings.ToLower(strings.TrimSpace(agentName))
-	agent, err := selectAgent(agentKey)
-	if err != nil {
+	if err = ensureParent(defaultPromptPath); err != nil {
 		return err
 	}
+	if err = os.WriteFile(defaultPromptPath, []byte(promptContent), 0o644); err != nil {
+		return fmt.Errorf("write prompt: %w", err)
+	}
+	logInfo(fmt.Sprintf("Rendered prompt to %s.", defaultPromptPath))
 
 	var model string
 	if envName := agent.ModelEnvVar(); envName != "" {
@@ -170,14 +297,17 @@ func run(ctx context.Context) error {
 	}
 
 	extraArgs, _ := fetchEnv(envAgentExtraArgs, false)
-	inv := agentInvocation{
+	inv := agents.Invocation{
 		Prompt:     promptContent,
 		Model:      model,
 		ExtraArgs:  extraArgs,
 		WorkingDir: defaultAgentWorkingDir,
 	}
 
-	timeout, err := resolveTimeout()
+	timeout := agentCfg.Timeout
+	if timeout <= 0 {
+		timeout, err = resolveTimeout()
+	}
 	if err != nil {
 		return err
 	}
@@ -197,8 +327,17 @@ func run(ctx context.Context) error {
 		))
 	}
 
+	sandboxCfg, err := buildSandboxConfig(agent)
+	if err != nil {
+		return err
+	}
+	maxRetries, err := resolveMaxRetries()
+	if err != nil {
+		return err
+	}
+
 	// Execute the agent command and relay its output streams.
-	if err := runAgent(agentCtx, agent, inv); err != nil {
+	if err := runAgent(agentCtx, agent, inv, sandboxCfg, maxRetries); err != nil {
 		return err
 	}
 
@@ -208,16 +347,146 @@ func run(ctx context.Context) error {
 	} else {
 		logWarn(fmt.Sprintf("Report not produced at %s.", defaultReportPath))
 	}
-	if fileExists(defaultLabelsPath) {
-		logInfo(fmt.Sprintf("Labels stored at %s.", defaultLabelsPath))
-	} else {
-		logWarn(fmt.Sprintf("Labels not produced at %s.", defaultLabelsPath))
+
+	// The SARIF log is required: it drives labeling, inline PR comments, and
+	// code-scanning uploads, so a malformed log fails the container rather
+	// than shipping a report automation can't act on.
+	sarifLog, err := sarif.ReadFile(sarifPath)
+	if err != nil {
+		return fmt.Errorf("validate %s: %w", sarifPath, err)
 	}
+	normalizeSarifLocations(sarifLog, defaultRepositoryPath)
+	logInfo(fmt.Sprintf("Findings stored at %s (%s).", sarifPath, formatSeverityCounts(sarif.CountByLevel(sarifLog))))
+
+	if err := writeLabelsFromSarif(defaultLabelsPath, sarifLog); err != nil {
+		return err
+	}
+	logInfo(fmt.Sprintf("Labels derived at %s.", defaultLabelsPath))
+
+	uploadArtifacts(ctx, mode, targetLabel, headSHA, defaultReportPath, sarifPath, defaultLabelsPath)
 
 	logInfo("Security review completed successfully.")
 	return nil
 }
 
+// normalizeSarifLocations rewrites each result's artifact URI in place to be
+// relative to repoPath, and drops results with a location that escapes the
+// repository entirely (e.g. an agent reporting against an absolute path
+// outside the checkout) - a code-scanning upload built from such a URI
+// would either be rejected or, worse, silently point somewhere unrelated.
+func normalizeSarifLocations(log sarif.Log, repoPath string) {
+	for runIdx, run := range log.Runs {
+		kept := run.Results[:0]
+		for _, result := range run.Results {
+			if normalizeResultLocations(result, repoPath) {
+				kept = append(kept, result)
+			}
+		}
+		log.Runs[runIdx].Results = kept
+	}
+}
+
+// normalizeResultLocations rewrites result's locations in place and reports
+// whether every one of them stayed within repoPath.
+func normalizeResultLocations(result sarif.Result, repoPath string) bool {
+	for locIdx, loc := range result.Locations {
+		rel, ok := repoRelativeURI(loc.PhysicalLocation.ArtifactLocation.URI, repoPath)
+		if !ok {
+			return false
+		}
+		result.Locations[locIdx].PhysicalLocation.ArtifactLocation.URI = rel
+	}
+	return true
+}
+
+// repoRelativeURI converts uri (absolute or already relative) to a slash
+// path relative to repoPath, reporting ok=false when the result escapes the
+// repository via a leading ".." segment or an absolute path outside it.
+func repoRelativeURI(uri, repoPath string) (rel string, ok bool) {
+	path := uri
+	if filepath.IsAbs(path) {
+		r, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return "", false
+		}
+		path = r
+	}
+	path = filepath.Clean(path)
+	if path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) || filepath.IsAbs(path) {
+		return "", false
+	}
+	return filepath.ToSlash(path), true
+}
+
+// formatSeverityCounts renders a per-level summary for the log line
+// emitted once the SARIF output has been validated, e.g. "2 error(s), 1
+// warning(s), 0 note(s)".
+func formatSeverityCounts(counts map[sarif.Level]int) string {
+	return fmt.Sprintf("%d error(s), %d warning(s), %d note(s)",
+		counts[sarif.LevelError], counts[sarif.LevelWarning], counts[sarif.LevelNote])
+}
+
+// writeLabelsFromSarif derives labels.txt from the highest severity level
+// present in the SARIF log, replacing the freeform labels the agent used to
+// author by hand.
+func writeLabelsFromSarif(path string, log sarif.Log) error {
+	if err := ensureParent(path); err != nil {
+		return err
+	}
+	label := sarif.HighestSeverityLabel(log)
+	if label == "" {
+		return os.WriteFile(path, nil, 0o644)
+	}
+	return os.WriteFile(path, []byte(label+"\n"), 0o644)
+}
+
+// resolveAgentConfigs fills in agentConfigPath/agentPluginDir from their
+// environment overrides or defaults when empty, then loads and merges the
+// agent configs found there. Plugin manifests are loaded first so an
+// explicit agents.yaml entry for the same name can still override fields on
+// top of them. It returns the resolved paths alongside the merged configs
+// so callers that only had the flag values (which may be empty) can report
+// or reuse what was actually loaded.
+func resolveAgentConfigs(agentConfigPath, agentPluginDir string) (map[string]agents.AgentConfig, string, string, error) {
+	if agentConfigPath == "" {
+		agentConfigPath = defaultAgentConfigPath
+		if fromEnv := strings.TrimSpace(os.Getenv(envAgentConfigPath)); fromEnv != "" {
+			agentConfigPath = fromEnv
+		}
+	}
+	if agentPluginDir == "" {
+		agentPluginDir = defaultAgentPluginDir
+		if fromEnv := strings.TrimSpace(os.Getenv(envAgentPluginDir)); fromEnv != "" {
+			agentPluginDir = fromEnv
+		}
+	}
+
+	agentConfigs, err := agents.LoadPluginDir(agentPluginDir)
+	if err != nil {
+		return nil, "", "", err
+	}
+	explicitConfigs, err := agents.LoadConfig(agentConfigPath)
+	if err != nil {
+		return nil, "", "", err
+	}
+	for name, cfg := range explicitConfigs {
+		agentConfigs[name] = cfg
+	}
+	return agentConfigs, agentConfigPath, agentPluginDir, nil
+}
+
+// discoveredAgentNames returns the names found in agentConfigs, sorted for
+// a deterministic error message, so a typo in REVIEW_AGENT points the
+// caller at what plugins/config entries were actually found.
+func discoveredAgentNames(agentConfigs map[string]agents.AgentConfig) []string {
+	names := make([]string, 0, len(agentConfigs))
+	for name := range agentConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // fetchEnv reads an environment variable and validates presence when required.
 func fetchEnv(name string, required bool) (string, error) {
 	value := strings.TrimSpace(os.Getenv(name))
@@ -236,20 +505,150 @@ func ensureParent(path string) error {
 	return os.MkdirAll(dir, 0o755)
 }
 
-// runAgent executes the reviewer agent command and captures output streams.
-func runAgent(ctx context.Context, agent reviewerAgent, inv agentInvocation) error {
+// runAgent executes the reviewer agent command, sandboxed per sandboxCfg,
+// re-invoking it with the same inv up to maxRetries times when an attempt
+// fails on a provider rate limit or an apparently transient error, as
+// surfaced through its stderr. A rate limit sleeps until the reset the
+// provider reported; any other transient failure backs off exponentially,
+// mirroring github.sleepWithBackoff's approach to a secondary rate limit.
+func runAgent(ctx context.Context, agent agents.Agent, inv agents.Invocation, sandboxCfg sandbox.Config, maxRetries int) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			logInfo(fmt.Sprintf("Retrying %s invocation (attempt %d/%d).", agent.Name(), attempt+1, maxRetries+1))
+		}
+
+		stderr, err := invokeAgent(ctx, agent, inv, sandboxCfg)
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s invocation failed: %w", agent.Name(), err)
+		if attempt == maxRetries {
+			break
+		}
+
+		var delay time.Duration
+		if resetDelay, ok := parseRateLimitDelay(stderr); ok {
+			delay = resetDelay
+			logInfo(fmt.Sprintf("Rate limit exceeded, waiting %d seconds for reset...", int64(delay.Seconds())))
+		} else if isTransientFailure(stderr) {
+			delay = retryBackoff(attempt)
+			logInfo(fmt.Sprintf("Transient failure, waiting %d seconds before retrying...", int64(delay.Seconds())))
+		} else {
+			return lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(delay):
+		}
+	}
+	return lastErr
+}
+
+// invokeAgent runs a single attempt of agent's command, relaying stdout and
+// stderr live while also capturing stderr so runAgent can inspect it for a
+// rate-limit or transient-failure signal if the attempt fails.
+func invokeAgent(ctx context.Context, agent agents.Agent, inv agents.Invocation, sandboxCfg sandbox.Config) (stderr string, err error) {
 	cmd, err := agent.BuildCommand(ctx, inv)
 	if err != nil {
-		return err
+		return "", err
 	}
 
+	cmd, err = sandbox.Wrap(cmd, sandboxCfg)
+	if err != nil {
+		return "", fmt.Errorf("sandbox %s: %w", agent.Name(), err)
+	}
+
+	var captured bytes.Buffer
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, &captured)
 
 	if err = cmd.Run(); err != nil {
-		return fmt.Errorf("%s invocation failed: %w", agent.Name(), err)
+		return captured.String(), err
 	}
-	return nil
+	return "", nil
+}
+
+// rateLimitHeaderPattern matches a retry hint an agent CLI echoed verbatim
+// from a provider's 429 response onto stderr: Anthropic's Retry-After (a
+// seconds count) or OpenAI's X-Ratelimit-Reset-* headers (a Go-style
+// duration like "6m0s", or a bare seconds count).
+var rateLimitHeaderPattern = regexp.MustCompile(`(?i)(?:retry-after|x-ratelimit-reset-\w+)["':\s]+([0-9.]+(?:m[0-9.]*s|s)?)`)
+
+// parseRateLimitDelay looks for a provider rate-limit header in stderr and
+// returns how long to wait before retrying.
+func parseRateLimitDelay(stderr string) (time.Duration, bool) {
+	match := rateLimitHeaderPattern.FindStringSubmatch(stderr)
+	if match == nil {
+		return 0, false
+	}
+	if delay, err := time.ParseDuration(match[1]); err == nil {
+		return delay, true
+	}
+	if secs, err := strconv.ParseFloat(match[1], 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// transientFailurePattern matches stderr text indicating a failure worth an
+// exponential-backoff retry: a 5xx response, or a network-level hiccup
+// reaching the provider.
+var transientFailurePattern = regexp.MustCompile(`(?i)\b5\d\d\b|connection reset|timeout|temporarily unavailable|EOF`)
+
+// isTransientFailure reports whether stderr looks like a transient failure
+// rather than a permanent one (a bad prompt, an auth failure, and so on).
+func isTransientFailure(stderr string) bool {
+	return transientFailurePattern.MatchString(stderr)
+}
+
+// retryBackoff returns an exponentially increasing delay for a transient
+// failure retry: 2s, 4s, 8s, and so on by attempt.
+func retryBackoff(attempt int) time.Duration {
+	return (2 * time.Second) << attempt
+}
+
+// resolveMaxRetries reads envReviewMaxRetries, defaulting to
+// defaultMaxRetries when unset.
+func resolveMaxRetries() (int, error) {
+	value := strings.TrimSpace(os.Getenv(envReviewMaxRetries))
+	if value == "" {
+		return defaultMaxRetries, nil
+	}
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("invalid %s value %q", envReviewMaxRetries, value)
+	}
+	return retries, nil
+}
+
+// buildSandboxConfig resolves REVIEW_SANDBOX and assembles the sandbox.Config
+// for agent: the repository checkout read-only, the reviewer's input/output
+// directories writable, and an environment trimmed to what the agent
+// actually needs rather than this process's full environment.
+func buildSandboxConfig(agent agents.Agent) (sandbox.Config, error) {
+	modeValue, err := fetchEnv(envReviewSandbox, false)
+	if err != nil {
+		return sandbox.Config{}, err
+	}
+	mode, err := sandbox.ParseMode(modeValue)
+	if err != nil {
+		return sandbox.Config{}, err
+	}
+
+	allow := []string{"PATH", "HOME", "ANTHROPIC_API_KEY"}
+	if modelEnvVar := agent.ModelEnvVar(); modelEnvVar != "" {
+		allow = append(allow, modelEnvVar)
+	}
+
+	return sandbox.Config{
+		Mode:          mode,
+		ReadOnlyPath:  defaultRepositoryPath,
+		WritablePaths: []string{filepath.Dir(defaultPromptPath), filepath.Dir(defaultReportPath)},
+		Env:           sandbox.Environ(allow...),
+	}, nil
 }
 
 func resolveTimeout() (time.Duration, error) {
@@ -264,8 +663,10 @@ func resolveTimeout() (time.Duration, error) {
 	return time.Duration(secs) * time.Second, nil
 }
 
-// buildPromptContent renders a concrete prompt for the selected review mode.
-func buildPromptContent(mode ReviewMode, targetLabel, headSHA, baseSHA string) (string, error) {
+// buildPromptContent renders a concrete prompt for the selected review mode,
+// from the template at templatePath (the agent's config override, or
+// promptTemplatePath by default).
+func buildPromptContent(templatePath string, mode ReviewMode, targetLabel, headSHA, baseSHA, sarifPath string) (string, error) {
 	displayLabel := strings.TrimSpace(targetLabel)
 	if displayLabel == "" {
 		displayLabel = "[Not provided]"
@@ -288,14 +689,15 @@ func buildPromptContent(mode ReviewMode, targetLabel, headSHA, baseSHA string) (
 		GitDiffHint:        gitDiffHint(mode, baseSHA, headSHA),
 		ReportPath:         defaultReportPath,
 		LabelsPath:         defaultLabelsPath,
+		SarifPath:          sarifPath,
 		ReportTemplatePath: reportTemplatePath,
 	}
-	return renderPrompt(ph)
+	return renderPrompt(templatePath, ph)
 }
 
-// renderPrompt injects placeholder values into the prompt template.
-func renderPrompt(ph promptPlaceholders) (string, error) {
-	templateBytes, err := os.ReadFile(promptTemplatePath)
+// renderPrompt injects placeholder values into the prompt template at templatePath.
+func renderPrompt(templatePath string, ph promptPlaceholders) (string, error) {
+	templateBytes, err := os.ReadFile(templatePath)
 	if err != nil {
 		return "", fmt.Errorf("read prompt template: %w", err)
 	}
@@ -310,6 +712,7 @@ func renderPrompt(ph promptPlaceholders) (string, error) {
 		"$GIT_DIFF_HINT", ph.GitDiffHint,
 		"$REPORT_PATH", ph.ReportPath,
 		"$LABELS_PATH", ph.LabelsPath,
+		"$SARIF_PATH", ph.SarifPath,
 		"$REPORT_TEMPLATE_PATH", ph.ReportTemplatePath,
 	)
 	return replacer.Replace(string(templateBytes)), nil
@@ -335,18 +738,37 @@ func modeLabel(mode ReviewMode) string {
 	}
 }
 
-// modeSummary explains the responsibilities associated with a review mode.
+// modeSummary explains the responsibilities associated with a review mode,
+// including the SARIF schema the agent must populate alongside the
+// narrative report so every finding carries a precise file/line location.
 func modeSummary(mode ReviewMode) string {
+	var intro string
 	switch mode {
 	case ReviewModeDifferential:
-		return "You are reviewing the changes introduced in a Git repository between the specified base and head commits. Prioritize spotting deliberately malicious additions alongside accidental vulnerabilities."
+		intro = "You are reviewing the changes introduced in a Git repository between the specified base and head commits. Prioritize spotting deliberately malicious additions alongside accidental vulnerabilities."
 	case ReviewModeFull:
-		return "You are auditing a Git repository snapshot at the specified head commit. Assume attackers may have hidden malicious logic and hunt for both intentional and accidental risks."
+		intro = "You are auditing a Git repository snapshot at the specified head commit. Assume attackers may have hidden malicious logic and hunt for both intentional and accidental risks."
 	default:
-		return "The review mode is unknown."
+		intro = "The review mode is unknown."
 	}
+	return intro + " " + sarifSchemaSummary
 }
 
+// sarifSchemaSummary describes the structured findings file the agent must
+// emit in addition to the narrative report. $LABELS_PATH is now derived
+// automatically from the highest severity level present, so the agent
+// should not write it directly.
+const sarifSchemaSummary = `In addition to the narrative report at $REPORT_PATH, write every finding ` +
+	`to $SARIF_PATH as a SARIF 2.1.0 log with exactly one run. Each result ` +
+	`requires: "ruleId" (a short stable identifier for the finding's class), ` +
+	`"level" (one of "error", "warning", "note", in decreasing severity), ` +
+	`"message.text" (the finding's description), and "locations" with one ` +
+	`entry whose "physicalLocation.artifactLocation.uri" is the affected ` +
+	`file's path relative to $REPOSITORY_PATH and whose ` +
+	`"physicalLocation.region" gives "startLine" and "endLine". Emit no ` +
+	`results if the review finds nothing reportable. $LABELS_PATH is derived ` +
+	`automatically from the SARIF log after you finish; do not write it.`
+
 // fileExists returns true when a non-zero length file exists at path.
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// envReviewS3Endpoint overrides the S3-compatible endpoint uploadArtifacts
+	// talks to, for anything other than real AWS S3 (MinIO, R2, and so on).
+	envReviewS3Endpoint = "REVIEW_S3_ENDPOINT"
+	// envReviewS3Region selects the region uploadArtifacts signs requests
+	// for.
+	envReviewS3Region = "REVIEW_S3_REGION"
+	// envReviewS3Bucket selects the destination bucket. Its presence is
+	// what turns uploadArtifacts on; every other REVIEW_S3_* variable is
+	// optional.
+	envReviewS3Bucket = "REVIEW_S3_BUCKET"
+	// envReviewS3AccessKey and envReviewS3SecretKey supply static
+	// credentials, for endpoints that don't honor the default AWS
+	// credential chain.
+	envReviewS3AccessKey = "REVIEW_S3_ACCESS_KEY"
+	envReviewS3SecretKey = "REVIEW_S3_SECRET_KEY"
+	// envReviewS3PathStyle forces path-style bucket addressing
+	// (https://host/bucket/key instead of https://bucket.host/key), which
+	// most non-AWS S3-compatible stores require.
+	envReviewS3PathStyle = "REVIEW_S3_PATH_STYLE"
+	// envReviewS3ACL sets the canned ACL applied to each uploaded object,
+	// left unset by default so the bucket's own default applies.
+	envReviewS3ACL = "REVIEW_S3_ACL"
+	// envReviewS3Prefix templates the key prefix uploaded objects are
+	// placed under; see s3UploadPrefix.
+	envReviewS3Prefix = "REVIEW_S3_PREFIX"
+
+	// defaultS3Prefix is used when envReviewS3Prefix is unset.
+	defaultS3Prefix = "$TARGET_LABEL/$HEAD_COMMIT"
+	// reviewModeMetadataKey is the x-amz-meta- header recording which
+	// review mode (full or differential) produced an uploaded artifact.
+	reviewModeMetadataKey = "review-mode"
+	// uploadTimeout bounds how long uploadArtifacts waits for all its S3
+	// puts combined, so a stalled connection to the object store can't hang
+	// the container past its own exit.
+	uploadTimeout = 2 * time.Minute
+)
+
+// uploadArtifacts uploads the rendered report, SARIF findings, and labels
+// to an S3-compatible bucket when REVIEW_S3_BUCKET is set; it's a no-op
+// otherwise. A failure here is never fatal to the review - the artifacts
+// are still on the local volume - so every error is logged and swallowed
+// rather than returned, letting the reviewer act as a self-contained job
+// without a separate uploader sidecar.
+func uploadArtifacts(ctx context.Context, mode ReviewMode, targetLabel, headSHA, reportPath, sarifPath, labelsPath string) {
+	bucket := strings.TrimSpace(os.Getenv(envReviewS3Bucket))
+	if bucket == "" {
+		return
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	client, err := newS3UploadClient(uploadCtx)
+	if err != nil {
+		logWarn(fmt.Sprintf("S3 upload skipped: %v", err))
+		return
+	}
+
+	acl := strings.TrimSpace(os.Getenv(envReviewS3ACL))
+	prefix := s3UploadPrefix(targetLabel, headSHA)
+
+	uploaded := 0
+	for _, path := range []string{reportPath, sarifPath, labelsPath} {
+		key := filepath.Base(path)
+		if prefix != "" {
+			key = prefix + "/" + key
+		}
+		if err := uploadArtifact(uploadCtx, client, bucket, key, path, acl, mode); err != nil {
+			logWarn(fmt.Sprintf("S3 upload of %s failed: %v", path, err))
+			continue
+		}
+		uploaded++
+	}
+
+	if uploaded > 0 {
+		logInfo(fmt.Sprintf("Uploaded %d artifact(s) to s3://%s/%s.", uploaded, bucket, prefix))
+	}
+}
+
+// newS3UploadClient builds an s3.Client from the REVIEW_S3_* environment:
+// an optional custom endpoint and path-style addressing for S3-compatible
+// stores, and static credentials when REVIEW_S3_ACCESS_KEY/
+// REVIEW_S3_SECRET_KEY are set rather than relying on the default AWS
+// credential chain.
+func newS3UploadClient(ctx context.Context) (*s3.Client, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region := strings.TrimSpace(os.Getenv(envReviewS3Region)); region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	accessKey := strings.TrimSpace(os.Getenv(envReviewS3AccessKey))
+	secretKey := strings.TrimSpace(os.Getenv(envReviewS3SecretKey))
+	if accessKey != "" || secretKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+
+	pathStyle, err := parseBoolEnv(envReviewS3PathStyle)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSpace(os.Getenv(envReviewS3Endpoint))
+
+	return s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = awsStringPtr(endpoint)
+		}
+		o.UsePathStyle = pathStyle
+	}), nil
+}
+
+// uploadArtifact PUTs the file at path to bucket under key, tagging it with
+// acl (if set) and an x-amz-meta-review-mode header recording mode.
+func uploadArtifact(ctx context.Context, client *s3.Client, bucket, key, path, acl string, mode ReviewMode) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      awsStringPtr(bucket),
+		Key:         awsStringPtr(key),
+		Body:        bytes.NewReader(data),
+		ContentType: awsStringPtr(contentTypeForPath(path)),
+		Metadata:    map[string]string{reviewModeMetadataKey: string(mode)},
+	}
+	if acl != "" {
+		input.ACL = types.ObjectCannedACL(acl)
+	}
+
+	_, err = client.PutObject(ctx, input)
+	return err
+}
+
+// s3UploadPrefix renders REVIEW_S3_PREFIX's $TARGET_LABEL/$HEAD_COMMIT
+// placeholders, defaulting to defaultS3Prefix when unset.
+func s3UploadPrefix(targetLabel, headSHA string) string {
+	template := strings.TrimSpace(os.Getenv(envReviewS3Prefix))
+	if template == "" {
+		template = defaultS3Prefix
+	}
+	replacer := strings.NewReplacer("$TARGET_LABEL", targetLabel, "$HEAD_COMMIT", headSHA)
+	return strings.Trim(replacer.Replace(template), "/")
+}
+
+// contentTypeForPath returns the Content-Type for one of the reviewer's own
+// output files, keyed by extension rather than sniffed, since the three
+// files it ever uploads are always one of these kinds.
+func contentTypeForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	case ".sarif", ".json":
+		return "application/sarif+json"
+	case ".txt":
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// parseBoolEnv parses name as a boolean environment variable, defaulting to
+// false when unset.
+func parseBoolEnv(name string) (bool, error) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value %q", name, value)
+	}
+	return parsed, nil
+}
+
+// awsStringPtr returns a pointer to s, for the AWS SDK's *string fields.
+func awsStringPtr(s string) *string { return &s }
@@ -6,19 +6,23 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/docker/mcp-registry/internal/mcp"
-	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/attest"
+	"github.com/docker/mcp-registry/pkg/builder"
 	"github.com/docker/mcp-registry/pkg/servers"
 )
 
 func main() {
 	listTools := flag.Bool("tools", false, "List the tools")
 	pullCommunity := flag.Bool("pull-community", false, "Pull images that are not in the mcp/ namespace")
+	driver := flag.String("driver", string(builder.DriverBuildKit), "Build driver to use: buildkit (dial buildkitd directly) or cli (shell out to docker buildx)")
+	progress := flag.String("progress", "", "Build progress output: auto, plain, tty, quiet, or json")
+	attestBuild := flag.Bool("attest", false, "Generate an SBOM and SLSA provenance attestation for the built image (buildkit driver only)")
+	sign := flag.Bool("sign", false, "Sign the built image with cosign after a successful build")
+	signKey := flag.String("sign-key", "", "cosign private key file to sign with; empty selects keyless (Fulcio/OIDC) signing")
 
 	flag.Parse()
 	args := flag.Args()
@@ -28,12 +32,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := run(context.Background(), args[0], *listTools, *pullCommunity); err != nil {
+	if err := run(context.Background(), args[0], *listTools, *pullCommunity, builder.Driver(*driver), *progress, *attestBuild, *sign, *signKey); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run(ctx context.Context, name string, listTools bool, pullCommunity bool) error {
+func run(ctx context.Context, name string, listTools, pullCommunity bool, driver builder.Driver, progress string, attestBuild, sign bool, signKey string) error {
 	server, err := servers.Read(filepath.Join("servers", name, "server.yaml"))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -44,15 +48,35 @@ func run(ctx context.Context, name string, listTools bool, pullCommunity bool) e
 
 	isMcpImage := strings.HasPrefix(server.Image, "mcp/")
 
+	opts := builder.Options{
+		Driver:      driver,
+		GitHubToken: os.Getenv("GITHUB_TOKEN"),
+		Attest:      attestBuild,
+	}
+
 	if isMcpImage {
-		if err := buildMcpImage(ctx, server); err != nil {
+		digest, err := buildMcpImage(ctx, server, opts, progress)
+		if err != nil {
 			return err
 		}
+		if sign {
+			if digest == "" {
+				return fmt.Errorf("signing %s: driver %q did not report a digest to sign", server.Image, driver)
+			}
+			ref := server.Image + "@" + digest
+			signers, err := attest.Sign(ctx, ref, attest.Options{KeyPath: signKey})
+			if err != nil {
+				return fmt.Errorf("signing %s: %w", ref, err)
+			}
+			for _, signer := range signers {
+				fmt.Println("✅ Signed by", signer.Identity)
+			}
+		}
 	} else {
 		if !pullCommunity {
 			return fmt.Errorf("server is not docker built (ie, in the 'mcp/' namespace), you must either build it yourself or pull it with `docker pull %s` if you want to use it", server.Image)
 		}
-		if err := pullCommunityImage(ctx, server); err != nil {
+		if err := builder.Pull(ctx, server, opts); err != nil {
 			return err
 		}
 	}
@@ -82,70 +106,37 @@ func run(ctx context.Context, name string, listTools bool, pullCommunity bool) e
 	return nil
 }
 
-func buildDockerEnv(additionalEnv ...string) []string {
-	env := []string{"PATH=" + os.Getenv("PATH")}
-	
-	// On Windows, Docker also needs ProgramW6432
-	// See https://github.com/docker/mcp-registry/issues/79 for more details
-	programW6432 := os.Getenv("ProgramW6432")
-	if runtime.GOOS == "windows" && programW6432 != "" {
-		env = append(env, "ProgramW6432="+programW6432)
-	}
-	
-	return append(env, additionalEnv...)
-}
-
-func buildMcpImage(ctx context.Context, server servers.Server) error {
-	projectURL := server.Source.Project
-	branch := server.Source.Branch
-	directory := server.Source.Directory
-
-	client := github.New()
-
-	repository, err := client.GetProjectRepository(ctx, projectURL)
-	if err != nil {
-		return err
-	}
-
-	if branch == "" {
-		branch = repository.GetDefaultBranch()
+// buildMcpImage builds server's image, rendering build events to stdout as
+// they arrive (DriverCLI streams the docker CLI's own terminal output
+// instead and ignores progress), and returns the built image's digest.
+func buildMcpImage(ctx context.Context, server servers.Server, opts builder.Options, progress string) (string, error) {
+	if opts.Driver == builder.DriverCLI {
+		return builder.Build(ctx, server, opts)
 	}
 
-	sha, err := client.GetCommitSHA1(ctx, projectURL, branch)
-	if err != nil {
-		return err
-	}
+	events := make(chan *builder.Event)
+	opts.Events = events
 
-	gitURL := projectURL + ".git#"
-	if branch != "" {
-		gitURL += branch
-	}
-	if directory != "" && directory != "." {
-		gitURL += ":" + directory
+	type buildResult struct {
+		digest string
+		err    error
 	}
-
-	var cmd *exec.Cmd
-	token := os.Getenv("GITHUB_TOKEN")
-
-	if token != "" {
-		cmd = exec.CommandContext(ctx, "docker", "buildx", "build", "--secret", "id=GIT_AUTH_TOKEN", "-f", server.GetDockerfile(), "-t", "check", "-t", server.Image, "--label", "org.opencontainers.image.revision="+sha, gitURL)
-		cmd.Env = buildDockerEnv("GIT_AUTH_TOKEN=" + token)
+	done := make(chan buildResult, 1)
+	go func() {
+		digest, err := builder.Build(ctx, server, opts)
+		done <- buildResult{digest, err}
+	}()
+
+	var renderErr error
+	if progress == "json" {
+		renderErr = builder.RenderJSON(os.Stdout, events)
 	} else {
-		cmd = exec.CommandContext(ctx, "docker", "buildx", "build", "-f", server.GetDockerfile(), "-t", "check", "-t", server.Image, "--label", "org.opencontainers.image.revision="+sha, gitURL)
-		cmd.Env = buildDockerEnv()
+		renderErr = builder.Render(os.Stdout, events, progress)
 	}
 
-	cmd.Dir = os.TempDir()
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
-}
-
-func pullCommunityImage(ctx context.Context, server servers.Server) error {
-	cmd := exec.CommandContext(ctx, "docker", "pull", server.Image)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
+	result := <-done
+	if result.err != nil {
+		return "", result.err
+	}
+	return result.digest, renderErr
 }
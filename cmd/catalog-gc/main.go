@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Command catalog-gc prunes stale catalog output: per-server catalog.yaml
+// directories and cached tools/readme artifacts under catalogs/ that no
+// servers/*/server.yaml roots any more, and, with --images, mcp/* Docker
+// Hub repositories no server.yaml declares. It's modelled on
+// `docker/distribution`'s garbage-collect subcommand - a mark-and-sweep
+// pass that treats every server.yaml as a root and reports (or removes)
+// everything it doesn't reach.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/docker/mcp-registry/pkg/catalog"
+	"github.com/docker/mcp-registry/pkg/hub"
+)
+
+func main() {
+	serversDir := flag.String("servers-dir", "servers", "Root directory containing one subdirectory per server.yaml")
+	catalogsDir := flag.String("catalogs-dir", "catalogs", "Root directory containing generated catalog output")
+	namespace := flag.String("namespace", "mcp", "Docker Hub namespace to check for orphaned images with --images")
+	images := flag.Bool("images", false, "Also report mcp/* Docker Hub repositories no server.yaml declares")
+	deleteFiles := flag.Bool("delete", false, "Remove orphaned local files instead of just reporting them")
+	pruneImages := flag.Bool("prune-images", false, "Delete orphaned Docker Hub repositories (implies --images)")
+	flag.Parse()
+
+	if err := run(context.Background(), *serversDir, *catalogsDir, *namespace, *images || *pruneImages, *deleteFiles, *pruneImages); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, serversDir, catalogsDir, namespace string, images, del, pruneImages bool) error {
+	report, err := catalog.GC(serversDir, catalogsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range report.OrphanedCatalogs {
+		fmt.Println("orphaned catalog:", path)
+	}
+	for _, path := range report.OrphanedArtifacts {
+		fmt.Println("orphaned artifact:", path)
+	}
+
+	if del {
+		for _, path := range append(append([]string{}, report.OrphanedCatalogs...), report.OrphanedArtifacts...) {
+			if err := os.RemoveAll(path); err != nil {
+				return fmt.Errorf("removing %s: %w", path, err)
+			}
+		}
+	}
+
+	if images {
+		orphanedImages, err := catalog.OrphanedImages(ctx, serversDir, namespace)
+		if err != nil {
+			return fmt.Errorf("checking %s/* on Docker Hub: %w", namespace, err)
+		}
+		for _, image := range orphanedImages {
+			fmt.Println("orphaned image:", image)
+		}
+
+		if pruneImages {
+			for _, image := range orphanedImages {
+				if err := pruneImage(ctx, image); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if report.Empty() && !images {
+		fmt.Println("✅ Nothing to prune")
+	}
+
+	return nil
+}
+
+// pruneImage deletes image (e.g. "mcp/some-orphaned-server") from Docker
+// Hub, printing its tags first so a --prune-images run leaves a record of
+// what it removed.
+func pruneImage(ctx context.Context, image string) error {
+	tags, err := hub.ListTags(ctx, image)
+	if err != nil {
+		return fmt.Errorf("listing tags for %s: %w", image, err)
+	}
+	for _, tag := range tags {
+		fmt.Printf("  deleting %s:%s\n", image, tag.Name)
+	}
+
+	if err := hub.DeleteRepository(ctx, image); err != nil {
+		return fmt.Errorf("pruning %s: %w", image, err)
+	}
+	return nil
+}
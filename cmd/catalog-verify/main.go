@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Command catalog-verify checks that a server's image carries a cosign
+// signature (and, with --trusted-key, was signed by one of a specific set
+// of keys rather than just any Fulcio identity), the way `task build
+// --sign` would have produced one.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/docker/mcp-registry/pkg/signing"
+)
+
+func main() {
+	serversDir := flag.String("servers-dir", "servers", "Root directory containing one subdirectory per server.yaml")
+	var trustedKeys stringSliceFlag
+	flag.Var(&trustedKeys, "trusted-key", "cosign public key file/URL to trust (repeatable); empty means keyless Fulcio/Rekor verification")
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) != 1 {
+		fmt.Println("Usage: catalog-verify [--trusted-key ...] <server>")
+		return
+	}
+
+	if err := run(context.Background(), *serversDir, args[0], trustedKeys); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, serversDir, name string, trustedKeys []string) error {
+	server, err := servers.Read(filepath.Join(serversDir, name, "server.yaml"))
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(server.Image, "mcp/") {
+		return fmt.Errorf("%s is not built by docker (image %q is not in the mcp/ namespace), nothing to verify", name, server.Image)
+	}
+
+	result, err := signing.Verify(ctx, server.Image, trustedKeys)
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", server.Image, err)
+	}
+	if !result.Verified() {
+		return fmt.Errorf("%s (%s) has no verified cosign signature", server.Image, result.Digest)
+	}
+
+	fmt.Println("✅", server.Image, "verified at", result.Digest)
+	for _, signer := range result.Signers {
+		fmt.Println("  signed by", signer.Identity, "(issuer:", signer.Issuer+")")
+	}
+	for _, att := range result.Attestations {
+		fmt.Println("  attested:", att.PredicateType, "by", att.Signer.Identity)
+	}
+	return nil
+}
+
+// stringSliceFlag collects repeated occurrences of a flag into a slice, the
+// way flag.String can't on its own.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
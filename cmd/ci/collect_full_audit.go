@@ -23,11 +23,16 @@ THE SOFTWARE.
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/mcp-registry/pkg/verify"
 )
 
 // runCollectFullAudit enumerates local servers (optionally filtered) and writes
@@ -38,6 +43,8 @@ func runCollectFullAudit(args []string) error {
 	workspace := flags.String("workspace", ".", "path to repository workspace")
 	filter := flags.String("servers", "", "optional comma-separated server filter")
 	outputJSON := flags.String("output-json", "", "path to write JSON context")
+	requireSigned := flags.Bool("require-signed", false, "drop targets whose pinned commit/image can't be verified against --trust-file")
+	trustFile := flags.String("trust-file", defaultTrustFile, "path to the trust policy used when --require-signed is set")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -51,6 +58,28 @@ func runCollectFullAudit(args []string) error {
 		return err
 	}
 
+	if *requireSigned {
+		policy, err := verify.LoadPolicy(*trustFile)
+		if err != nil {
+			return err
+		}
+
+		var verified []auditTarget
+		for _, target := range targets {
+			projectPolicy, matched := policy.For(target.Project)
+			if !matched {
+				fmt.Fprintf(os.Stderr, "excluding %s: verify: %s has no trust.yaml entry\n", target.Server, target.Project)
+				continue
+			}
+			if err := verifyPin(context.Background(), target.Project, target.Commit, target.Image, projectPolicy); err != nil {
+				fmt.Fprintf(os.Stderr, "excluding %s: %v\n", target.Server, err)
+				continue
+			}
+			verified = append(verified, target)
+		}
+		targets = verified
+	}
+
 	if len(targets) == 0 {
 		removeIfPresent(*outputJSON)
 		return nil
@@ -100,6 +129,7 @@ func collectAuditTargets(workspace, filter string) ([]auditTarget, error) {
 			Project:   project,
 			Commit:    commit,
 			Directory: strings.TrimSpace(doc.Source.Directory),
+			Image:     strings.TrimSpace(doc.Image),
 		})
 		return nil
 	})
@@ -23,12 +23,15 @@ THE SOFTWARE.
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/mcp-registry/pkg/verify"
 )
 
 // runCollectNewServers identifies newly added local servers between two git
@@ -42,6 +45,8 @@ func runCollectNewServers(args []string) error {
 	workspace := flags.String("workspace", ".", "path to repository workspace")
 	outputJSON := flags.String("output-json", "", "path to write JSON context")
 	summaryMD := flags.String("summary-md", "", "path to write Markdown summary")
+	requireSigned := flags.Bool("require-signed", false, "drop targets whose pinned commit/image can't be verified against --trust-file")
+	trustFile := flags.String("trust-file", defaultTrustFile, "path to the trust policy used when --require-signed is set")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -55,6 +60,15 @@ func runCollectNewServers(args []string) error {
 		return err
 	}
 
+	var dropped []unverifiedTarget
+	if *requireSigned {
+		policy, err := verify.LoadPolicy(*trustFile)
+		if err != nil {
+			return err
+		}
+		targets, dropped = verifyNewServerTargets(context.Background(), targets, policy)
+	}
+
 	if len(targets) == 0 {
 		removeIfPresent(*outputJSON)
 		removeIfPresent(*summaryMD)
@@ -65,10 +79,29 @@ func runCollectNewServers(args []string) error {
 		return err
 	}
 
-	summary := buildNewServerSummary(targets)
+	summary := buildNewServerSummary(targets) + buildUnverifiedSection(dropped)
 	return os.WriteFile(*summaryMD, []byte(summary), 0o644)
 }
 
+// verifyNewServerTargets checks each target's pinned commit (and image, if
+// set) against policy, splitting targets into those that verify and those
+// that don't.
+func verifyNewServerTargets(ctx context.Context, targets []newServerTarget, policy verify.Policy) (verified []newServerTarget, dropped []unverifiedTarget) {
+	for _, target := range targets {
+		projectPolicy, matched := policy.For(target.Project)
+		if !matched {
+			dropped = append(dropped, unverifiedTarget{Server: target.Server, Reason: fmt.Errorf("verify: %s has no trust.yaml entry", target.Project)})
+			continue
+		}
+		if err := verifyPin(ctx, target.Project, target.Commit, target.Image, projectPolicy); err != nil {
+			dropped = append(dropped, unverifiedTarget{Server: target.Server, Reason: err})
+			continue
+		}
+		verified = append(verified, target)
+	}
+	return verified, dropped
+}
+
 // collectNewServerTargets returns metadata for local servers that were added
 // between the supplied git revisions.
 func collectNewServerTargets(workspace, base, head string) ([]newServerTarget, error) {
@@ -65,7 +65,7 @@ func runCollectUpdatedPins(args []string) error {
 		return err
 	}
 
-	summary := buildPinSummary(targets)
+	summary := buildPinSummary(targets, nil)
 	return os.WriteFile(*summaryMD, []byte(summary), 0o644)
 }
 
@@ -119,8 +119,11 @@ func collectUpdatedPinTargets(workspace, base, head string) ([]pinTarget, error)
 }
 
 // buildPinSummary renders a Markdown section describing updated pin targets so
-// that review tooling and humans can understand what changed.
-func buildPinSummary(targets []pinTarget) string {
+// that review tooling and humans can understand what changed. bundles maps a
+// target's Server to the review bundle preparePinTarget generated for it; it
+// is nil when called right after collection, before prepare-updated-pins has
+// run, and populated when prepare-updated-pins rewrites the summary.
+func buildPinSummary(targets []pinTarget, bundles map[string]pinReviewBundle) string {
 	builder := strings.Builder{}
 	builder.WriteString("## Updated Commit Pins\n\n")
 
@@ -134,8 +137,29 @@ func buildPinSummary(targets []pinTarget) string {
 		}
 		builder.WriteString(fmt.Sprintf("- Previous commit: `%s`\n", target.OldCommit))
 		builder.WriteString(fmt.Sprintf("- New commit: `%s`\n", target.NewCommit))
-		builder.WriteString(fmt.Sprintf("- Diff path: /tmp/security-review/pins/%s/diff.patch\n\n", target.Server))
+		builder.WriteString(fmt.Sprintf("- Diff path: /tmp/security-review/pins/%s/diff.patch\n", target.Server))
+
+		if bundle, ok := bundles[target.Server]; ok {
+			builder.WriteString(fmt.Sprintf(
+				"- Review bundle: %d new dependenc%s, %d flagged license%s, %d suspected secret%s\n",
+				bundle.NewDependencyCount, plural(bundle.NewDependencyCount, "y", "ies"),
+				bundle.FlaggedLicenseCount, plural(bundle.FlaggedLicenseCount, "", "s"),
+				bundle.SuspectedSecretCount, plural(bundle.SuspectedSecretCount, "", "s"),
+			))
+			builder.WriteString(fmt.Sprintf("- SBOM path: %s\n", bundle.SBOMPath))
+			builder.WriteString(fmt.Sprintf("- License check path: %s\n", bundle.LicensesPath))
+			builder.WriteString(fmt.Sprintf("- Secrets scan path: %s\n", bundle.SecretsScanPath))
+		}
+		builder.WriteString("\n")
 	}
 
 	return builder.String()
 }
+
+// plural returns singular when count is 1 and plural otherwise.
+func plural(count int, singular, pluralSuffix string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralSuffix
+}
@@ -53,6 +53,46 @@ func readJSONFile(path string, value any) error {
 	return json.Unmarshal(content, value)
 }
 
+// writeJSONFileSynced stores value as indented JSON at path and fsyncs it
+// before closing, so a concurrent prepare-full-audit-batch run can treat
+// the file's mere existence as proof that the checkout it describes is
+// actually complete, not a partial write left behind by a crash.
+func writeJSONFileSynced(path string, value any) error {
+	payload, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(payload); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// mergeJSONFile merges updates into the JSON object already at path,
+// leaving any existing key updates doesn't mention untouched. It's used by
+// a later pipeline stage (e.g. verify-pins) to enrich a metadata.json an
+// earlier stage (e.g. prepare-updated-pins) already wrote, without
+// clobbering what that stage recorded.
+func mergeJSONFile(path string, updates map[string]string) error {
+	existing := map[string]string{}
+	if content, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(content, &existing); err != nil {
+			return err
+		}
+	}
+	for key, value := range updates {
+		existing[key] = value
+	}
+	return writeJSONFile(path, existing)
+}
+
 // removeIfPresent deletes the file at the path when it exists.
 func removeIfPresent(path string) {
 	if path == "" {
@@ -130,6 +170,41 @@ func gitDiff(workspace, base, head, mode string) ([]string, error) {
 	return lines, nil
 }
 
+// initGitRepository creates an empty git repository at dir (if one isn't
+// already there) and points its "origin" remote at project, without fetching
+// anything yet. Callers follow up with fetchCommit for whichever revisions
+// they actually need, so a shallow single-commit fetch never has to pull
+// the rest of the history.
+func initGitRepository(dir, project string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return nil
+	}
+
+	if _, err := runGitCommand(dir, "init", "-q"); err != nil {
+		return err
+	}
+	if _, err := runGitCommand(dir, "remote", "add", "origin", project); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fetchCommit shallow-fetches a single commit (or tag) into a repository
+// initGitRepository already created, leaving it reachable as FETCH_HEAD and
+// under refs/committed-pins/<commit> so later lookups don't depend on
+// FETCH_HEAD surviving a second fetch.
+func fetchCommit(dir, commit string) error {
+	if commit == "" {
+		return fmt.Errorf("fetchCommit: empty commit")
+	}
+
+	if _, err := runGitCommand(dir, "fetch", "--depth", "1", "origin", commit); err != nil {
+		return err
+	}
+	_, err := runGitCommand(dir, "update-ref", "refs/committed-pins/"+commit, "FETCH_HEAD")
+	return err
+}
+
 // runGitCommand executes git with the given arguments inside the directory.
 func runGitCommand(dir string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
@@ -0,0 +1,236 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-registry/internal/mcp"
+)
+
+// probeInventory loads serverName's server.yaml out of workspace and, if it
+// declares an image, launches it to capture its live tool/prompt inventory.
+// It returns a nil inventory (with no error) when the server declares no
+// image, so --probe callers don't have to special-case that themselves.
+func probeInventory(ctx context.Context, workspace, serverName string) (*mcp.Inventory, error) {
+	doc, err := loadServerYAMLFromWorkspace(workspace, filepath.Join("servers", serverName, "server.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(doc.Image) == "" {
+		return nil, nil
+	}
+
+	inventory, err := mcp.Probe(ctx, doc, true, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("launching %s: %w", doc.Image, err)
+	}
+	return inventory, nil
+}
+
+// inventoryDiff summarizes how current's tool/prompt surface differs from a
+// previous inventory: names added or removed, plus tools whose input
+// arguments changed without the tool itself being added or removed.
+type inventoryDiff struct {
+	ToolsAdded     []string
+	ToolsRemoved   []string
+	ToolsChanged   []string
+	PromptsAdded   []string
+	PromptsRemoved []string
+}
+
+// diffInventories computes the capability diff between previous and
+// current. previous may be nil (no prior pinned commit to compare against),
+// in which case every entry in current is reported as added.
+func diffInventories(previous, current *mcp.Inventory) inventoryDiff {
+	var diff inventoryDiff
+	if current == nil {
+		return diff
+	}
+
+	previousTools := map[string]mcp.Tool{}
+	if previous != nil {
+		for _, tool := range previous.Tools {
+			previousTools[tool.Name] = tool
+		}
+	}
+	seenTools := map[string]bool{}
+	for _, tool := range current.Tools {
+		seenTools[tool.Name] = true
+		previousTool, ok := previousTools[tool.Name]
+		if !ok {
+			diff.ToolsAdded = append(diff.ToolsAdded, tool.Name)
+			continue
+		}
+		if !sameArguments(previousTool.Arguments, tool.Arguments) {
+			diff.ToolsChanged = append(diff.ToolsChanged, tool.Name)
+		}
+	}
+	for name := range previousTools {
+		if !seenTools[name] {
+			diff.ToolsRemoved = append(diff.ToolsRemoved, name)
+		}
+	}
+
+	previousPrompts := map[string]bool{}
+	if previous != nil {
+		for _, prompt := range previous.Prompts {
+			previousPrompts[prompt.Name] = true
+		}
+	}
+	seenPrompts := map[string]bool{}
+	for _, prompt := range current.Prompts {
+		seenPrompts[prompt.Name] = true
+		if !previousPrompts[prompt.Name] {
+			diff.PromptsAdded = append(diff.PromptsAdded, prompt.Name)
+		}
+	}
+	for name := range previousPrompts {
+		if !seenPrompts[name] {
+			diff.PromptsRemoved = append(diff.PromptsRemoved, name)
+		}
+	}
+
+	sort.Strings(diff.ToolsAdded)
+	sort.Strings(diff.ToolsRemoved)
+	sort.Strings(diff.ToolsChanged)
+	sort.Strings(diff.PromptsAdded)
+	sort.Strings(diff.PromptsRemoved)
+	return diff
+}
+
+// sameArguments reports whether a and b declare the same set of argument
+// names, types, and optionality, ignoring order and description text.
+func sameArguments(a, b []mcp.ToolArgument) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byName := make(map[string]mcp.ToolArgument, len(a))
+	for _, arg := range a {
+		byName[arg.Name] = arg
+	}
+	for _, arg := range b {
+		previous, ok := byName[arg.Name]
+		if !ok || previous.Type != arg.Type || previous.Optional != arg.Optional {
+			return false
+		}
+		if !sameItems(previous.Items, arg.Items) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameItems reports whether two array-argument element-type descriptors
+// are equivalent, treating nil (non-array arguments) as equal to itself.
+func sameItems(a, b *mcp.Items) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	if a == nil {
+		return true
+	}
+	return a.Type == b.Type
+}
+
+// renderInventoryTables renders inventory's tools and prompts as Markdown
+// tables, for embedding in context.md/README.md. It returns "" when
+// inventory is nil, so offline (non-probed) reports are unchanged.
+func renderInventoryTables(inventory *mcp.Inventory) string {
+	if inventory == nil {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString("\n## Tool Inventory\n\n")
+	if len(inventory.Tools) == 0 {
+		builder.WriteString("_No tools advertised._\n")
+	} else {
+		builder.WriteString("| Tool | Description | Arguments |\n")
+		builder.WriteString("|---|---|---|\n")
+		for _, tool := range inventory.Tools {
+			var argumentNames []string
+			for _, argument := range tool.Arguments {
+				argumentNames = append(argumentNames, argument.Name)
+			}
+			builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tool.Name, oneLine(tool.Description), strings.Join(argumentNames, ", ")))
+		}
+	}
+
+	builder.WriteString("\n## Prompt Inventory\n\n")
+	if len(inventory.Prompts) == 0 {
+		builder.WriteString("_No prompts advertised._\n")
+	} else {
+		builder.WriteString("| Prompt | Description |\n")
+		builder.WriteString("|---|---|\n")
+		for _, prompt := range inventory.Prompts {
+			builder.WriteString(fmt.Sprintf("| %s | %s |\n", prompt.Name, oneLine(prompt.Description)))
+		}
+	}
+
+	return builder.String()
+}
+
+// renderInventoryDiff renders diff as a Markdown section. hasPrevious
+// distinguishes "nothing changed" from "there was nothing to compare
+// against", since both otherwise produce an empty diff.
+func renderInventoryDiff(diff inventoryDiff, hasPrevious bool) string {
+	builder := strings.Builder{}
+	builder.WriteString("\n## Capability Diff\n\n")
+
+	if !hasPrevious {
+		builder.WriteString("_No previous pinned commit to diff against; every tool/prompt above is newly observed._\n")
+		return builder.String()
+	}
+	if len(diff.ToolsAdded) == 0 && len(diff.ToolsRemoved) == 0 && len(diff.ToolsChanged) == 0 && len(diff.PromptsAdded) == 0 && len(diff.PromptsRemoved) == 0 {
+		builder.WriteString("_No change in tool/prompt surface since the previous pinned commit._\n")
+		return builder.String()
+	}
+
+	for _, name := range diff.ToolsAdded {
+		builder.WriteString(fmt.Sprintf("- ➕ tool `%s` added\n", name))
+	}
+	for _, name := range diff.ToolsRemoved {
+		builder.WriteString(fmt.Sprintf("- ➖ tool `%s` removed\n", name))
+	}
+	for _, name := range diff.ToolsChanged {
+		builder.WriteString(fmt.Sprintf("- ♻️ tool `%s` input arguments changed\n", name))
+	}
+	for _, name := range diff.PromptsAdded {
+		builder.WriteString(fmt.Sprintf("- ➕ prompt `%s` added\n", name))
+	}
+	for _, name := range diff.PromptsRemoved {
+		builder.WriteString(fmt.Sprintf("- ➖ prompt `%s` removed\n", name))
+	}
+	return builder.String()
+}
+
+// oneLine collapses description text to a single line so it doesn't break
+// out of a Markdown table cell.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
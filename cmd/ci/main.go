@@ -21,6 +21,8 @@ func main() {
 		err = runCollectUpdatedPins(args)
 	case "prepare-updated-pins":
 		err = runPrepareUpdatedPins(args)
+	case "verify-pins":
+		err = runVerifyPins(args)
 	case "collect-new-servers":
 		err = runCollectNewServers(args)
 	case "prepare-new-servers":
@@ -31,6 +33,8 @@ func main() {
 		err = runCollectFullAudit(args)
 	case "prepare-full-audit":
 		err = runPrepareFullAudit(args)
+	case "prepare-full-audit-batch":
+		err = runPrepareFullAuditBatch(args)
 	default:
 		fmt.Fprintf(os.Stderr, "unknown command: %s\n", cmd)
 		os.Exit(2)
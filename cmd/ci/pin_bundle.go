@@ -0,0 +1,309 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-registry/internal/licenses"
+	"github.com/docker/mcp-registry/pkg/github"
+)
+
+// pinReviewBundleTimeout bounds how long SBOM generation, license lookups,
+// and the secrets scan may take for a single pin target, so one slow
+// upstream doesn't stall the whole prepare step.
+const pinReviewBundleTimeout = 5 * time.Minute
+
+// licenseFinding records the license check outcome for a single dependency
+// module introduced between a pin target's old and new commits.
+type licenseFinding struct {
+	// Module is the dependency's module path as it appears in go.mod.
+	Module string `json:"module"`
+	// License is the license key reported by GitHub for the module's repository, when resolvable.
+	License string `json:"license,omitempty"`
+	// Valid reports whether internal/licenses.IsValid accepted the license.
+	Valid bool `json:"valid"`
+	// Reason explains why a module couldn't be checked, when applicable.
+	Reason string `json:"reason,omitempty"`
+}
+
+// secretFinding records a single suspected secret detected while scanning
+// the files changed between a pin target's old and new commits.
+type secretFinding struct {
+	// File is the path, relative to the repository root, containing the match.
+	File string `json:"file"`
+	// Description names the kind of secret the pattern matched.
+	Description string `json:"description"`
+}
+
+// buildPinReviewBundle generates the SBOM, license, and secrets-scan
+// artifacts for a single prepared pin target and writes them under
+// serverDir, returning aggregate counts so callers can surface them
+// without re-reading the artifacts.
+func buildPinReviewBundle(serverDir, repoDir string, target pinTarget) (pinReviewBundle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pinReviewBundleTimeout)
+	defer cancel()
+
+	bundle := pinReviewBundle{
+		SBOMPath:        filepath.Join(serverDir, "sbom.json"),
+		LicensesPath:    filepath.Join(serverDir, "licenses.json"),
+		SecretsScanPath: filepath.Join(serverDir, "secrets-scan.json"),
+	}
+
+	scanPath := repoDir
+	if target.Directory != "" && target.Directory != "." {
+		scanPath = filepath.Join(repoDir, target.Directory)
+	}
+	if err := generateSBOM(ctx, scanPath, bundle.SBOMPath); err != nil {
+		return bundle, fmt.Errorf("generate sbom: %w", err)
+	}
+
+	newModules, err := newDependencyModules(repoDir, target)
+	if err != nil {
+		return bundle, fmt.Errorf("diff dependency modules: %w", err)
+	}
+	bundle.NewDependencyCount = len(newModules)
+
+	findings := checkDependencyLicenses(ctx, newModules)
+	for _, finding := range findings {
+		if !finding.Valid {
+			bundle.FlaggedLicenseCount++
+		}
+	}
+	if err := writeJSONFile(bundle.LicensesPath, findings); err != nil {
+		return bundle, err
+	}
+
+	secretFindings, err := scanForSecrets(repoDir, target.OldCommit, target.NewCommit)
+	if err != nil {
+		return bundle, fmt.Errorf("scan for secrets: %w", err)
+	}
+	bundle.SuspectedSecretCount = len(secretFindings)
+	if err := writeJSONFile(bundle.SecretsScanPath, secretFindings); err != nil {
+		return bundle, err
+	}
+
+	return bundle, nil
+}
+
+// generateSBOM runs syft against scanPath and writes an SPDX JSON SBOM
+// capturing the dependencies reachable from the head commit tree. A missing
+// syft binary degrades to no SBOM rather than failing the whole bundle, the
+// same way scanForSecrets degrades when gitleaks isn't installed.
+func generateSBOM(ctx context.Context, scanPath, outputPath string) error {
+	if _, err := exec.LookPath("syft"); err != nil {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "syft", "dir:"+scanPath, "-o", "spdx-json="+outputPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("syft: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// goModRequirePattern matches a single "module version" pair inside a go.mod
+// require block or single-line require directive, with any leading
+// "require" keyword from the single-line form already stripped.
+var goModRequirePattern = regexp.MustCompile(`^\s*(?:require\s+)?([^\s()]+)\s+(v\S+)`)
+
+// newDependencyModules returns the direct dependency module paths present in
+// repoDir's go.mod at target.NewCommit but absent at target.OldCommit.
+func newDependencyModules(repoDir string, target pinTarget) ([]string, error) {
+	oldModules, err := loadGoModRequires(repoDir, target.OldCommit, target.Directory)
+	if err != nil {
+		return nil, err
+	}
+	newModules, err := loadGoModRequires(repoDir, target.NewCommit, target.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	for module := range newModules {
+		if _, existed := oldModules[module]; !existed {
+			added = append(added, module)
+		}
+	}
+	return added, nil
+}
+
+// loadGoModRequires reads go.mod at commit and returns the set of required
+// module paths. It returns an empty set, rather than an error, when the
+// target has no go.mod (the upstream isn't a Go module).
+func loadGoModRequires(repoDir, commit, directory string) (map[string]struct{}, error) {
+	path := "go.mod"
+	if directory != "" && directory != "." {
+		path = filepath.Join(directory, "go.mod")
+	}
+
+	content, err := runGitCommand(repoDir, "show", fmt.Sprintf("%s:%s", commit, path))
+	if err != nil {
+		return map[string]struct{}{}, nil
+	}
+
+	modules := map[string]struct{}{}
+	for _, line := range strings.Split(content, "\n") {
+		match := goModRequirePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		modules[match[1]] = struct{}{}
+	}
+	return modules, nil
+}
+
+// checkDependencyLicenses resolves each GitHub-hosted module to its
+// repository and runs internal/licenses.IsValid against the reported
+// license, the same checker applied to server upstreams elsewhere in this
+// repository.
+func checkDependencyLicenses(ctx context.Context, modules []string) []licenseFinding {
+	client := github.New()
+
+	findings := make([]licenseFinding, 0, len(modules))
+	for _, module := range modules {
+		project, ok := githubModuleProject(module)
+		if !ok {
+			findings = append(findings, licenseFinding{Module: module, Valid: true, Reason: "not a github.com module; license not checked"})
+			continue
+		}
+
+		repository, err := client.GetProjectRepository(ctx, project)
+		if err != nil {
+			findings = append(findings, licenseFinding{Module: module, Valid: true, Reason: fmt.Sprintf("could not resolve repository: %v", err)})
+			continue
+		}
+
+		findings = append(findings, licenseFinding{
+			Module:  module,
+			License: repository.GetLicense().GetKey(),
+			Valid:   licenses.IsValid(repository.License),
+		})
+	}
+	return findings
+}
+
+// githubModuleProject converts a github.com-hosted Go module path into the
+// "https://github.com/<org>/<repo>" URL expected by pkg/github.
+func githubModuleProject(module string) (string, bool) {
+	if !strings.HasPrefix(module, "github.com/") {
+		return "", false
+	}
+	parts := strings.Split(module, "/")
+	if len(parts) < 3 {
+		return "", false
+	}
+	return fmt.Sprintf("https://github.com/%s/%s", parts[1], parts[2]), true
+}
+
+// gitleaksSecretPatterns is the built-in fallback used when the gitleaks
+// binary isn't available on PATH. It isn't a substitute for gitleaks' rule
+// set, but it catches the common high-signal cases.
+var gitleaksSecretPatterns = map[string]*regexp.Regexp{
+	"AWS access key ID":          regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	"private key block":          regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	"generic API key assignment": regexp.MustCompile(`(?i)(api|secret)[_-]?key["'\s:=]+[A-Za-z0-9_\-]{20,}`),
+}
+
+// scanForSecrets scans the files changed between oldCommit and newCommit for
+// suspected secrets, preferring the gitleaks CLI and falling back to a
+// built-in regex pass when it isn't installed.
+func scanForSecrets(repoDir, oldCommit, newCommit string) ([]secretFinding, error) {
+	if _, err := exec.LookPath("gitleaks"); err == nil {
+		return scanForSecretsWithGitleaks(repoDir, oldCommit, newCommit)
+	}
+	return scanForSecretsWithRegex(repoDir, oldCommit, newCommit)
+}
+
+// scanForSecretsWithGitleaks runs gitleaks' detect mode over the commit
+// range and reparses its JSON report into our finding shape.
+func scanForSecretsWithGitleaks(repoDir, oldCommit, newCommit string) ([]secretFinding, error) {
+	reportPath := filepath.Join(repoDir, ".gitleaks-report.json")
+	cmd := exec.Command("gitleaks", "detect",
+		"--source", repoDir,
+		"--log-opts", fmt.Sprintf("%s..%s", oldCommit, newCommit),
+		"--report-format", "json",
+		"--report-path", reportPath,
+		"--exit-code", "0",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("gitleaks: %w\n%s", err, string(output))
+	}
+
+	if _, err := os.Stat(reportPath); err != nil {
+		// No leaks found: gitleaks omits the report file entirely.
+		return nil, nil
+	}
+
+	var reports []struct {
+		File        string `json:"File"`
+		Description string `json:"Description"`
+	}
+	if err := readJSONFile(reportPath, &reports); err != nil {
+		return nil, fmt.Errorf("read gitleaks report: %w", err)
+	}
+
+	findings := make([]secretFinding, 0, len(reports))
+	for _, report := range reports {
+		findings = append(findings, secretFinding{File: report.File, Description: report.Description})
+	}
+	return findings, nil
+}
+
+// scanForSecretsWithRegex lists the files changed between oldCommit and
+// newCommit and matches each against gitleaksSecretPatterns.
+func scanForSecretsWithRegex(repoDir, oldCommit, newCommit string) ([]secretFinding, error) {
+	changedOut, err := runGitCommand(repoDir, "diff", "--name-only", oldCommit, newCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []secretFinding
+	for _, file := range strings.Split(strings.TrimSpace(changedOut), "\n") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+
+		content, err := runGitCommand(repoDir, "show", fmt.Sprintf("%s:%s", newCommit, file))
+		if err != nil {
+			// The file may have been deleted in newCommit; nothing to scan.
+			continue
+		}
+
+		for description, pattern := range gitleaksSecretPatterns {
+			if pattern.MatchString(content) {
+				findings = append(findings, secretFinding{File: file, Description: description})
+			}
+		}
+	}
+	return findings, nil
+}
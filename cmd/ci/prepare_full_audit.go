@@ -23,12 +23,16 @@ THE SOFTWARE.
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/mcp-registry/internal/mcp"
+	"github.com/docker/mcp-registry/pkg/verify"
 )
 
 // runPrepareFullAudit clones source data for a single audit target specified by
@@ -38,6 +42,9 @@ func runPrepareFullAudit(args []string) error {
 	flags := flag.NewFlagSet("prepare-full-audit", flag.ContinueOnError)
 	targetFile := flags.String("target-file", "", "path to JSON target descriptor")
 	outputDir := flags.String("output-dir", "", "directory to receive prepared artifacts")
+	trustFile := flags.String("trust-file", defaultTrustFile, "path to the trust policy checked against the pinned commit/image")
+	workspace := flags.String("workspace", ".", "path to the registry workspace, used to load server.yaml when --probe is set")
+	probe := flags.Bool("probe", false, "launch the server's image and record its live tool/prompt inventory")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -51,12 +58,24 @@ func runPrepareFullAudit(args []string) error {
 		return err
 	}
 
-	return prepareAuditTarget(*outputDir, target)
+	policy, err := verify.LoadPolicy(*trustFile)
+	if err != nil {
+		return err
+	}
+
+	return prepareAuditTarget(context.Background(), *outputDir, target, policy, *workspace, *probe)
 }
 
 // prepareAuditTarget materializes repository state and metadata for auditing a
 // single server, storing artifacts beneath the provided output directory.
-func prepareAuditTarget(outputDir string, target auditTarget) error {
+// It checks the checked-out commit (and target.Image, if set) against
+// policy, so an unsigned or wrongly-signed pin is surfaced to reviewers as
+// unverified rather than silently audited as if it were trusted. When probe
+// is set, it also launches target.Image (looking up its server.yaml beneath
+// workspace) and records the resulting tool/prompt inventory; a probe
+// failure is recorded on the target rather than failing the checkout, since
+// the inventory is best-effort context, not something reviewers depend on.
+func prepareAuditTarget(ctx context.Context, outputDir string, target auditTarget, policy verify.Policy, workspace string, probe bool) error {
 	serverDir := filepath.Join(outputDir, target.Server)
 	repoDir := filepath.Join(serverDir, "repo")
 	if err := os.MkdirAll(repoDir, 0o755); err != nil {
@@ -73,17 +92,49 @@ func prepareAuditTarget(outputDir string, target auditTarget) error {
 		return err
 	}
 
-	context := buildAuditContext(target, repoDir)
-	if err := os.WriteFile(filepath.Join(serverDir, "context.md"), []byte(context), 0o644); err != nil {
+	projectPolicy, matched := policy.For(target.Project)
+	if !matched {
+		target.VerificationError = fmt.Sprintf("verify: %s has no trust.yaml entry", target.Project)
+	} else if _, err := verify.VerifyGit(ctx, repoDir, target.Commit, projectPolicy); err != nil {
+		target.VerificationError = err.Error()
+	} else if target.Image != "" {
+		if _, err := verify.VerifyImage(ctx, target.Image, projectPolicy); err != nil {
+			target.VerificationError = err.Error()
+		} else {
+			target.Verified = true
+		}
+	} else {
+		target.Verified = true
+	}
+
+	var inventory *mcp.Inventory
+	if probe {
+		probed, err := probeInventory(ctx, workspace, target.Server)
+		if err != nil {
+			target.ProbeError = err.Error()
+		} else {
+			inventory = probed
+		}
+		if inventory != nil {
+			if err := writeJSONFile(filepath.Join(serverDir, "inventory.json"), inventory); err != nil {
+				return err
+			}
+		}
+	}
+
+	auditContext := buildAuditContext(target, repoDir, inventory)
+	if err := os.WriteFile(filepath.Join(serverDir, "context.md"), []byte(auditContext), 0o644); err != nil {
 		return err
 	}
 
-	return writeJSONFile(filepath.Join(serverDir, "metadata.json"), target)
+	return writeJSONFileSynced(filepath.Join(serverDir, "metadata.json"), target)
 }
 
 // buildAuditContext produces Markdown describing the prepared audit checkout,
-// which is used to prime review prompts.
-func buildAuditContext(target auditTarget, repoDir string) string {
+// which is used to prime review prompts. inventory is nil unless
+// prepareAuditTarget was run with --probe, in which case its tool/prompt
+// tables are appended.
+func buildAuditContext(target auditTarget, repoDir string, inventory *mcp.Inventory) string {
 	builder := strings.Builder{}
 	builder.WriteString("# Full Audit Target\n\n")
 	builder.WriteString(fmt.Sprintf("- Server: %s\n", target.Server))
@@ -95,5 +146,14 @@ func buildAuditContext(target auditTarget, repoDir string) string {
 		builder.WriteString("- Directory: (repository root)\n")
 	}
 	builder.WriteString(fmt.Sprintf("- Checkout path: %s\n", repoDir))
+	if target.Verified {
+		builder.WriteString("- Verification: ✅ signed commit/image matched trust.yaml\n")
+	} else {
+		builder.WriteString(fmt.Sprintf("- Verification: ⚠️ **unverified** (%s)\n", target.VerificationError))
+	}
+	if target.ProbeError != "" {
+		builder.WriteString(fmt.Sprintf("- Probe: ⚠️ launch failed (%s)\n", target.ProbeError))
+	}
+	builder.WriteString(renderInventoryTables(inventory))
 	return builder.String()
 }
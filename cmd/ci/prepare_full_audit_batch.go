@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-registry/pkg/verify"
+)
+
+// prepareTargetTimeout bounds how long a single target's clone/checkout is
+// allowed to take, so one unreachable upstream repository can't stall the
+// whole batch indefinitely.
+const prepareTargetTimeout = 10 * time.Minute
+
+// batchResult is a single manifest.json entry summarizing one target's
+// prepareAuditTarget run.
+type batchResult struct {
+	Server     string `json:"server"`
+	RepoDir    string `json:"repoDir"`
+	Commit     string `json:"commit"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// runPrepareFullAuditBatch clones and checks out every target in the JSON
+// array written by collect-full-audit/collect-new-servers concurrently,
+// under a bounded worker pool, and writes a top-level manifest.json
+// summarizing every attempt - including failures, which are recorded
+// rather than aborting the rest of the batch.
+func runPrepareFullAuditBatch(args []string) error {
+	flags := flag.NewFlagSet("prepare-full-audit-batch", flag.ContinueOnError)
+	targetFile := flags.String("target-file", "", "path to a JSON array of audit targets")
+	outputDir := flags.String("output-dir", "", "directory to receive prepared artifacts")
+	trustFile := flags.String("trust-file", defaultTrustFile, "path to the trust policy checked against each pinned commit/image")
+	workspace := flags.String("workspace", ".", "path to the registry workspace, used to load server.yaml when --probe is set")
+	probe := flags.Bool("probe", false, "launch each target's image and record its live tool/prompt inventory")
+	concurrency := flags.Int("concurrency", runtime.NumCPU(), "number of targets to prepare concurrently")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *targetFile == "" || *outputDir == "" {
+		return errors.New("target-file and output-dir are required")
+	}
+	if *concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+
+	var targets []auditTarget
+	if err := readJSONFile(*targetFile, &targets); err != nil {
+		return err
+	}
+
+	policy, err := verify.LoadPolicy(*trustFile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return err
+	}
+
+	results := make([]batchResult, len(targets))
+	sem := make(chan struct{}, *concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, target auditTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = prepareBatchTarget(*outputDir, target, policy, *workspace, *probe)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return writeJSONFile(filepath.Join(*outputDir, "manifest.json"), results)
+}
+
+// prepareBatchTarget runs prepareAuditTarget for a single target and turns
+// its outcome into a manifest entry, timing the attempt and capturing any
+// error instead of letting it propagate and abort the rest of the batch.
+func prepareBatchTarget(outputDir string, target auditTarget, policy verify.Policy, workspace string, probe bool) batchResult {
+	start := time.Now()
+	result := batchResult{
+		Server:  target.Server,
+		RepoDir: filepath.Join(outputDir, target.Server, "repo"),
+		Commit:  target.Commit,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), prepareTargetTimeout)
+	defer cancel()
+
+	if err := prepareAuditTarget(ctx, outputDir, target, policy, workspace, probe); err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+	} else {
+		result.Status = "ok"
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
@@ -23,12 +23,17 @@ THE SOFTWARE.
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/mcp-registry/internal/mcp"
+	"github.com/docker/mcp-registry/pkg/reference"
+	"github.com/docker/mcp-registry/pkg/signing"
 )
 
 // runPrepareNewServers checks out repositories for newly added local servers,
@@ -38,6 +43,9 @@ func runPrepareNewServers(args []string) error {
 	flags := flag.NewFlagSet("prepare-new-servers", flag.ContinueOnError)
 	contextFile := flags.String("context-file", "", "path to JSON context file")
 	outputDir := flags.String("output-dir", "", "directory to receive prepared artifacts")
+	trustedKeys := flags.String("trusted-keys", "", "comma-separated cosign public key paths/URLs; keyless (Fulcio) verification is used if empty")
+	workspace := flags.String("workspace", ".", "path to the registry workspace, used to load server.yaml when --probe is set")
+	probe := flags.Bool("probe", false, "launch each target's image and record its live tool/prompt inventory")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -59,8 +67,13 @@ func runPrepareNewServers(args []string) error {
 		return err
 	}
 
+	var keys []string
+	if *trustedKeys != "" {
+		keys = strings.Split(*trustedKeys, ",")
+	}
+
 	for _, target := range targets {
-		if err := prepareNewServerTarget(*outputDir, target); err != nil {
+		if err := prepareNewServerTarget(*outputDir, target, keys, *workspace, *probe); err != nil {
 			return fmt.Errorf("prepare new server %s: %w", target.Server, err)
 		}
 	}
@@ -69,8 +82,15 @@ func runPrepareNewServers(args []string) error {
 }
 
 // prepareNewServerTarget clones the upstream repository at the pinned commit
-// for a new server and records metadata for downstream review.
-func prepareNewServerTarget(outputDir string, target newServerTarget) error {
+// for a new server and records metadata for downstream review. When the
+// target declares an image, its cosign signatures and attestations are
+// verified against trustedKeys (or keyless, if empty) and the outcome is
+// recorded in both metadata.json and the review README. When probe is set,
+// it also launches the image (looking up its server.yaml beneath workspace)
+// and records the resulting tool/prompt inventory; since a newly added
+// server has no previous pinned commit, its capability diff always shows
+// every entry as newly observed rather than being skipped outright.
+func prepareNewServerTarget(outputDir string, target newServerTarget, trustedKeys []string, workspace string, probe bool) error {
 	serverDir := filepath.Join(outputDir, target.Server)
 	repoDir := filepath.Join(serverDir, "repo")
 	if err := os.MkdirAll(repoDir, 0o755); err != nil {
@@ -93,17 +113,56 @@ func prepareNewServerTarget(outputDir string, target newServerTarget) error {
 		"commit":     target.Commit,
 		"directory":  target.Directory,
 	}
+
+	var supplyChain *signing.Result
+	if target.Image != "" {
+		metadata["image"] = target.Image
+		if ref, err := reference.Parse(target.Image); err == nil && ref.Digest != "" {
+			metadata["image_digest"] = ref.Digest
+		}
+
+		result, err := signing.Verify(context.Background(), target.Image, trustedKeys)
+		if err != nil {
+			metadata["signature_verified"] = "false"
+		} else {
+			supplyChain = &result
+			metadata["signature_verified"] = fmt.Sprintf("%t", result.Verified())
+		}
+	}
+
+	var inventory *mcp.Inventory
+	var probeError string
+	if probe {
+		probed, err := probeInventory(context.Background(), workspace, target.Server)
+		if err != nil {
+			probeError = err.Error()
+		} else {
+			inventory = probed
+		}
+		if inventory != nil {
+			if err := writeJSONFile(filepath.Join(serverDir, "inventory.json"), inventory); err != nil {
+				return err
+			}
+		}
+	}
+	if probeError != "" {
+		metadata["probe_error"] = probeError
+	}
+
 	if err := writeJSONFile(filepath.Join(serverDir, "metadata.json"), metadata); err != nil {
 		return err
 	}
 
-	summary := buildNewServerDetail(target)
+	summary := buildNewServerDetail(target, supplyChain, inventory, probeError)
 	return os.WriteFile(filepath.Join(serverDir, "README.md"), []byte(summary), 0o644)
 }
 
 // buildNewServerDetail returns a Markdown overview describing the cloned
-// server, suitable for inclusion in review prompts.
-func buildNewServerDetail(target newServerTarget) string {
+// server, suitable for inclusion in review prompts. inventory is nil unless
+// prepareNewServerTarget was run with --probe; its tool/prompt tables and
+// capability diff (always rendered against an empty baseline, since a new
+// server has no previous pinned commit) are appended when present.
+func buildNewServerDetail(target newServerTarget, supplyChain *signing.Result, inventory *mcp.Inventory, probeError string) string {
 	builder := strings.Builder{}
 	builder.WriteString("# New Server Security Review\n\n")
 	builder.WriteString(fmt.Sprintf("- Server: %s\n", target.Server))
@@ -114,5 +173,28 @@ func buildNewServerDetail(target newServerTarget) string {
 	} else {
 		builder.WriteString("- Directory: (repository root)\n")
 	}
+
+	if target.Image != "" {
+		builder.WriteString("\n## Supply-chain\n\n")
+		if supplyChain == nil || !supplyChain.Verified() {
+			builder.WriteString("- **Unverified**: no cosign signature could be verified for " + target.Image + "\n")
+		} else {
+			for _, signer := range supplyChain.Signers {
+				builder.WriteString(fmt.Sprintf("- Signed by %s (issuer: %s)\n", signer.Identity, signer.Issuer))
+			}
+			for _, attestation := range supplyChain.Attestations {
+				builder.WriteString(fmt.Sprintf("- Attested: %s by %s (issuer: %s)\n", attestation.PredicateType, attestation.Signer.Identity, attestation.Signer.Issuer))
+			}
+		}
+	}
+
+	if probeError != "" {
+		builder.WriteString(fmt.Sprintf("\n- Probe: ⚠️ launch failed (%s)\n", probeError))
+	}
+	builder.WriteString(renderInventoryTables(inventory))
+	if inventory != nil {
+		builder.WriteString(renderInventoryDiff(diffInventories(nil, inventory), false))
+	}
+
 	return builder.String()
 }
@@ -1,21 +1,29 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/docker/mcp-registry/pkg/ociinspect"
+	"github.com/docker/mcp-registry/pkg/reference"
+	"github.com/docker/mcp-registry/pkg/servers/edit"
 )
 
 // runPrepareUpdatedPins fetches upstream repositories and prepares diff
 // artifacts for each updated pin listed in the context file. It consumes
 // --context-file and --output-dir flags and writes diffs, logs, and metadata
-// for downstream analysis.
+// for downstream analysis. When --summary-md is set, it rewrites that file
+// with the aggregate SBOM/license/secrets counts once the bundles exist,
+// enriching the summary collect-updated-pins already wrote.
 func runPrepareUpdatedPins(args []string) error {
 	flags := flag.NewFlagSet("prepare-updated-pins", flag.ContinueOnError)
 	contextFile := flags.String("context-file", "", "path to JSON context file")
 	outputDir := flags.String("output-dir", "", "directory to receive prepared artifacts")
+	summaryMD := flags.String("summary-md", "", "path to rewrite with enriched review counts, once available")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -37,10 +45,20 @@ func runPrepareUpdatedPins(args []string) error {
 		return err
 	}
 
+	bundles := make(map[string]pinReviewBundle, len(targets))
 	for _, target := range targets {
-		if err := preparePinTarget(*outputDir, target); err != nil {
+		bundle, err := preparePinTarget(*outputDir, target)
+		if err != nil {
 			return fmt.Errorf("prepare pin target %s: %w", target.Server, err)
 		}
+		bundles[target.Server] = bundle
+	}
+
+	if *summaryMD != "" {
+		summary := buildPinSummary(targets, bundles)
+		if err := os.WriteFile(*summaryMD, []byte(summary), 0o644); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -48,21 +66,22 @@ func runPrepareUpdatedPins(args []string) error {
 
 // preparePinTarget materializes git diffs, commit logs, and metadata for a
 // single commit pin update, storing the results under the provided output
-// directory.
-func preparePinTarget(outputDir string, target pinTarget) error {
+// directory, then generates the SBOM/license/secrets-scan review bundle
+// alongside them.
+func preparePinTarget(outputDir string, target pinTarget) (pinReviewBundle, error) {
 	serverDir := filepath.Join(outputDir, target.Server)
 	repoDir := filepath.Join(serverDir, "repo")
 	if err := os.MkdirAll(repoDir, 0o755); err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 
 	if err := initGitRepository(repoDir, target.Project); err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 
 	for _, commit := range []string{target.OldCommit, target.NewCommit} {
 		if err := fetchCommit(repoDir, commit); err != nil {
-			return err
+			return pinReviewBundle{}, err
 		}
 	}
 
@@ -72,18 +91,18 @@ func preparePinTarget(outputDir string, target pinTarget) error {
 	}
 	diffOut, err := runGitCommand(repoDir, diffArgs...)
 	if err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 	if err := os.WriteFile(filepath.Join(serverDir, "diff.patch"), []byte(diffOut), 0o644); err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 
 	logOut, err := runGitCommand(repoDir, "log", "--oneline", "--stat", fmt.Sprintf("%s..%s", target.OldCommit, target.NewCommit))
 	if err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 	if err := os.WriteFile(filepath.Join(serverDir, "changes.log"), []byte(logOut), 0o644); err != nil {
-		return err
+		return pinReviewBundle{}, err
 	}
 
 	metadata := map[string]string{
@@ -93,5 +112,63 @@ func preparePinTarget(outputDir string, target pinTarget) error {
 		"new_commit": target.NewCommit,
 		"directory":  target.Directory,
 	}
-	return writeJSONFile(filepath.Join(serverDir, "metadata.json"), metadata)
+
+	if target.Image != "" {
+		digest, err := pinImageDigest(context.Background(), target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pinning image digest for %s: %v\n", target.Server, err)
+		} else {
+			metadata["image"] = target.Image
+			metadata["image_digest"] = digest
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(serverDir, "metadata.json"), metadata); err != nil {
+		return pinReviewBundle{}, err
+	}
+
+	return buildPinReviewBundle(serverDir, repoDir, target)
+}
+
+// pinImageDigest resolves target.Image's tag to its current manifest
+// digest and rewrites target.File's top-level image field to the
+// digest-qualified reference, the same way this command already advances
+// source.commit for the git side of a pin update. It returns the resolved
+// digest so the caller can record it in metadata.json even when target.File
+// isn't set (e.g. a dry run).
+func pinImageDigest(ctx context.Context, target pinTarget) (string, error) {
+	summary, err := ociinspect.InspectManifest(ctx, target.Image)
+	if err != nil {
+		return "", fmt.Errorf("inspecting manifest for %s: %w", target.Image, err)
+	}
+
+	ref, err := reference.Parse(target.Image)
+	if err != nil {
+		return "", err
+	}
+	pinned, err := ref.Normalize().WithDigest(summary.Digest)
+	if err != nil {
+		return "", err
+	}
+
+	if target.File == "" {
+		return summary.Digest, nil
+	}
+
+	doc, err := edit.Open(target.File)
+	if err != nil {
+		return "", err
+	}
+	if err := doc.SetImage(pinned.String()); err != nil {
+		return "", err
+	}
+	rendered, err := doc.Bytes()
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(target.File, rendered, 0o644); err != nil {
+		return "", err
+	}
+
+	return summary.Digest, nil
 }
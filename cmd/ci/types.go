@@ -40,6 +40,26 @@ type pinTarget struct {
 	NewCommit string `json:"new_commit"`
 }
 
+// pinReviewBundle summarizes the machine-readable security context generated
+// for a single pin target by preparePinTarget: an SBOM, a license check, and
+// a secrets scan covering the delta between OldCommit and NewCommit. Reviewer
+// prompts reference these files by path instead of re-deriving the same
+// analysis from the raw diff.
+type pinReviewBundle struct {
+	// SBOMPath is where the SPDX SBOM generated from the head commit tree was written.
+	SBOMPath string `json:"sbom_path"`
+	// LicensesPath is where the per-dependency license check results were written.
+	LicensesPath string `json:"licenses_path"`
+	// SecretsScanPath is where the secrets scan results were written.
+	SecretsScanPath string `json:"secrets_scan_path"`
+	// NewDependencyCount is the number of direct dependencies added since OldCommit.
+	NewDependencyCount int `json:"new_dependency_count"`
+	// FlaggedLicenseCount is the number of new dependencies with a disallowed license.
+	FlaggedLicenseCount int `json:"flagged_license_count"`
+	// SuspectedSecretCount is the number of suspected secrets found in the changed files.
+	SuspectedSecretCount int `json:"suspected_secret_count"`
+}
+
 // newServerTarget captures metadata for a newly added local server.
 type newServerTarget struct {
 	// Server is the registry entry name for the newly added server.
@@ -66,4 +86,16 @@ type auditTarget struct {
 	Commit string `json:"commit"`
 	// Directory is the subdirectory within the upstream repo to inspect, when applicable.
 	Directory string `json:"directory,omitempty"`
+	// Image is the Docker image identifier associated with the audited server, when present.
+	Image string `json:"image,omitempty"`
+	// Verified records whether prepareAuditTarget confirmed Commit (and
+	// Image, if set) against trust.yaml. See pkg/verify.
+	Verified bool `json:"verified"`
+	// VerificationError explains why Verified is false.
+	VerificationError string `json:"verification_error,omitempty"`
+	// ProbeError explains why a requested --probe launch of the server
+	// failed to produce an inventory. It does not fail the rest of the
+	// checkout, since the tool/prompt inventory is best-effort extra
+	// context, not something reviewers strictly depend on.
+	ProbeError string `json:"probe_error,omitempty"`
 }
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/mcp-registry/pkg/verify"
+)
+
+// defaultTrustFile is where --require-signed and prepare-full-audit look
+// for the project trust allowlist when --trust-file isn't given, mirroring
+// the .mcp-registry/agents.yaml convention the security-reviewer uses for
+// its own config.
+const defaultTrustFile = ".mcp-registry/trust.yaml"
+
+// verifyPin clones project at commit into a throwaway directory and checks
+// its signature, plus image's cosign signature when set, against policy.
+// It's how collect-new-servers/collect-full-audit check a target's
+// provenance before prepareAuditTarget ever clones it for real, since
+// --require-signed needs an answer before the target is even written to
+// the collected JSON.
+func verifyPin(ctx context.Context, project, commit, image string, policy verify.ProjectPolicy) error {
+	dir, err := os.MkdirTemp("", "mcp-registry-verify-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := initGitRepository(dir, project); err != nil {
+		return err
+	}
+	if err := fetchCommit(dir, commit); err != nil {
+		return err
+	}
+	if _, err := verify.VerifyGit(ctx, dir, commit, policy); err != nil {
+		return err
+	}
+
+	if image != "" {
+		if _, err := verify.VerifyImage(ctx, image, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unverifiedTarget pairs a dropped target's server name with why it failed
+// verification, for the Markdown summaries --require-signed affects.
+type unverifiedTarget struct {
+	Server string
+	Reason error
+}
+
+// buildUnverifiedSection renders the "dropped for verification" Markdown
+// section shared by every --require-signed summary.
+func buildUnverifiedSection(dropped []unverifiedTarget) string {
+	if len(dropped) == 0 {
+		return ""
+	}
+
+	section := "\n## Unverified (excluded)\n\n"
+	for _, d := range dropped {
+		section += fmt.Sprintf("- **%s**: %s\n", d.Server, d.Reason)
+	}
+	return section
+}
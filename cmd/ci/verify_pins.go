@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/signing"
+)
+
+// runVerifyPins checks the provenance of each updated pin listed in the
+// context file, alongside (and after) prepare-updated-pins: the new commit
+// must be reachable from a GPG/SSH-signed commit or tag git itself can
+// verify, and, when the target names an image, its manifest must carry a
+// cosign/Notary v2 signature verified against --trusted-keys (or, absent
+// any, the public Fulcio/Rekor log). Outcomes are merged into each target's
+// metadata.json so reviewer agents can gate auto-merge on trusted
+// provenance rather than diff review alone.
+func runVerifyPins(args []string) error {
+	flags := flag.NewFlagSet("verify-pins", flag.ContinueOnError)
+	contextFile := flags.String("context-file", "", "path to JSON context file")
+	outputDir := flags.String("output-dir", "", "directory to receive prepared artifacts")
+	trustedKeys := flags.String("trusted-keys", "", "comma-separated cosign public key paths/URLs; keyless (Fulcio) verification is used if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *contextFile == "" || *outputDir == "" {
+		return errors.New("context-file and output-dir are required")
+	}
+
+	var targets []pinTarget
+	if err := readJSONFile(*contextFile, &targets); err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return err
+	}
+
+	var keys []string
+	if *trustedKeys != "" {
+		keys = strings.Split(*trustedKeys, ",")
+	}
+
+	ctx := context.Background()
+	var untrusted []string
+	for _, target := range targets {
+		trusted, err := verifyPinTarget(ctx, *outputDir, target, keys)
+		if err != nil {
+			return fmt.Errorf("verify pin target %s: %w", target.Server, err)
+		}
+		if !trusted {
+			untrusted = append(untrusted, target.Server)
+		}
+	}
+
+	if len(untrusted) > 0 {
+		sort.Strings(untrusted)
+		return fmt.Errorf("unverified pin updates: %s", strings.Join(untrusted, ", "))
+	}
+
+	return nil
+}
+
+// verifyPinTarget checks a single pin target's commit and (if present)
+// image provenance, recording the outcome in the target's metadata.json
+// alongside whatever preparePinTarget already wrote there. It returns
+// whether the target's provenance was fully trusted.
+func verifyPinTarget(ctx context.Context, outputDir string, target pinTarget, trustedKeys []string) (bool, error) {
+	serverDir := filepath.Join(outputDir, target.Server)
+	repoDir := filepath.Join(serverDir, "repo")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		return false, err
+	}
+
+	if err := initGitRepository(repoDir, target.Project); err != nil {
+		return false, err
+	}
+	if err := fetchCommit(repoDir, target.NewCommit); err != nil {
+		return false, err
+	}
+
+	metadata := map[string]string{
+		"signature_status": "unsigned",
+	}
+
+	commitTrusted := false
+	if _, err := runGitCommand(repoDir, "verify-commit", target.NewCommit); err == nil {
+		commitTrusted = true
+		metadata["signature_status"] = "signed"
+		if signer, err := commitSigner(repoDir, target.NewCommit); err == nil {
+			metadata["signer_identity"] = signer
+		}
+	} else if _, err := runGitCommand(repoDir, "verify-tag", target.NewCommit); err == nil {
+		commitTrusted = true
+		metadata["signature_status"] = "signed"
+	}
+
+	trusted := commitTrusted
+	if target.Image != "" {
+		result, err := signing.Verify(ctx, target.Image, trustedKeys)
+		if err != nil || !result.Verified() {
+			metadata["image_signature_status"] = "unsigned"
+			trusted = false
+		} else {
+			metadata["image_signature_status"] = "signed"
+			metadata["signer_identity"] = result.Signers[0].Identity
+			if result.Signers[0].RekorUUID != "" {
+				metadata["rekor_uuid"] = result.Signers[0].RekorUUID
+			}
+		}
+	}
+
+	if err := mergeJSONFile(filepath.Join(serverDir, "metadata.json"), metadata); err != nil {
+		return false, err
+	}
+
+	return trusted, nil
+}
+
+// commitSigner returns the signer name git recorded for commit, via
+// `git log --format=%GS`, which is only meaningful once verify-commit (or
+// verify-tag) has already confirmed the signature is valid.
+func commitSigner(repoDir, commit string) (string, error) {
+	out, err := runGitCommand(repoDir, "log", "-1", "--format=%GS", commit)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -25,6 +25,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -39,7 +40,12 @@ import (
 
 	"github.com/docker/mcp-registry/internal/licenses"
 	"github.com/docker/mcp-registry/internal/mcp"
+	"github.com/docker/mcp-registry/pkg/credentials"
+	"github.com/docker/mcp-registry/pkg/execenv"
 	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/ociinspect"
+	"github.com/docker/mcp-registry/pkg/reference"
+	"github.com/docker/mcp-registry/pkg/scaffold"
 	"github.com/docker/mcp-registry/pkg/servers"
 )
 
@@ -49,6 +55,7 @@ func main() {
 	image := flag.String("image", "", "Image to use for the mcp server, instead of building from the repository")
 	build := flag.Bool("build", true, "Build the image")
 	listTools := flag.Bool("tools", true, "List the tools")
+	generateDockerfile := flag.String("generate-dockerfile", "", fmt.Sprintf(`Generate a Dockerfile from a template if the upstream repo has none: "auto" to detect the language, or one of: %s`, strings.Join(scaffold.Templates(), ", ")))
 
 	flag.Parse()
 	args := flag.Args()
@@ -68,12 +75,12 @@ func main() {
 		additionalArgs = args[1:]
 	}
 
-	if err := run(ctx, url, *name, *category, *image, *build, *listTools, additionalArgs); err != nil {
+	if err := run(ctx, url, *name, *category, *image, *generateDockerfile, *build, *listTools, additionalArgs); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run(ctx context.Context, buildURL, name, category, userProvidedImage string, build, listTools bool, args []string) error {
+func run(ctx context.Context, buildURL, name, category, userProvidedImage, generateDockerfile string, build, listTools bool, args []string) error {
 	projectURL := buildURL
 
 	client := github.New()
@@ -117,6 +124,9 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 	if userProvidedImage != "" {
 		tag = userProvidedImage
 	}
+	if _, err := reference.Parse(tag); err != nil {
+		return fmt.Errorf("--image %q is not a valid image reference: %w", tag, err)
+	}
 
 	title := strings.ToUpper(guessedName[0:1]) + guessedName[1:]
 	if !strings.Contains(repository.GetDescription(), title+" MCP Server") {
@@ -127,6 +137,20 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 		fmt.Println("[WARNING] Project", projectURL, "is licensed under", repository.License.GetName(), "which may be incompatible with some tools")
 	}
 
+	scaffoldedDockerfile := ""
+	if generateDockerfile != "" && userProvidedImage == "" {
+		scaffoldedDockerfile, err = maybeScaffoldDockerfile(ctx, client, projectURL, branch, directory, name, tag, generateDockerfile)
+		if err != nil {
+			return err
+		}
+		if scaffoldedDockerfile != "" {
+			// The generated Dockerfile only exists in our local servers/<name>
+			// directory so far; the upstream repo still has nothing for
+			// `docker buildx build` to find at that git context.
+			build = false
+		}
+	}
+
 	if build && userProvidedImage == "" {
 		gitURL := projectURL + ".git#"
 		if branch != "" {
@@ -138,13 +162,18 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 
 		var cmd *exec.Cmd
 		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			if creds, err := credentials.LookupGit(ctx, projectURL); err == nil {
+				token = creds.Password
+			}
+		}
 
 		if token != "" {
 			cmd = exec.CommandContext(ctx, "docker", "buildx", "build", "--secret", "id=GIT_AUTH_TOKEN", "-t", "check", "-t", tag, "--label", "org.opencontainers.image.revision="+sha, gitURL)
-			cmd.Env = []string{"GIT_AUTH_TOKEN=" + token, "PATH=" + os.Getenv("PATH")}
+			cmd.Env = execenv.Build(execenv.Config{}, "GIT_AUTH_TOKEN="+token)
 		} else {
 			cmd = exec.CommandContext(ctx, "docker", "buildx", "build", "-t", "check", "-t", tag, "--label", "org.opencontainers.image.revision="+sha, gitURL)
-			cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+			cmd.Env = execenv.Build(execenv.Config{})
 		}
 
 		cmd.Dir = os.TempDir()
@@ -156,11 +185,38 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 		}
 	}
 
-	// Find the working directory
-	cmd := exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.Config.WorkingDir}}", "check")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("getting working directory: %w\n%s", err, out)
+	pinnedImage := tag
+	if build && userProvidedImage == "" {
+		digest, err := imageDigest(ctx, tag)
+		if err != nil {
+			return fmt.Errorf("resolving digest for %s: %w", tag, err)
+		}
+		ref, err := reference.Parse(tag)
+		if err != nil {
+			return err
+		}
+		pinned, err := ref.WithDigest(digest)
+		if err != nil {
+			return err
+		}
+		pinnedImage = pinned.String()
+	}
+
+	// Find the working directory, confirming the image is usable. For a
+	// locally built image this means inspecting the "check" tag docker
+	// just produced; for --image it means reading the config straight from
+	// the upstream registry, so importing an already-published server
+	// needs neither a local build nor a `docker pull`.
+	if userProvidedImage != "" {
+		if _, err := ociinspect.Inspect(ctx, userProvidedImage); err != nil {
+			return fmt.Errorf("inspecting %s: %w", userProvidedImage, err)
+		}
+	} else {
+		cmd := exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.Config.WorkingDir}}", "check")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w\n%s", err, out)
+		}
 	}
 
 	var (
@@ -216,10 +272,11 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 			Description: "TODO (only to provide a better description than the upstream project)",
 		},
 		Source: servers.Source{
-			Project:   projectURL,
-			Upstream:  upstream,
-			Branch:    branch,
-			Directory: directory,
+			Project:    projectURL,
+			Upstream:   upstream,
+			Branch:     branch,
+			Directory:  directory,
+			Dockerfile: scaffoldedDockerfile,
 		},
 		Run: servers.Run{
 			Command: command,
@@ -247,6 +304,12 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 
 		fmt.Printf("\n-----------------------------------------\n\n")
 	}
+
+	// Pin the image reference to the digest we resolved above so the
+	// catalog entry is reproducible, while still probing tools against the
+	// locally built tag (which may not yet exist under its digest).
+	server.Image = pinnedImage
+
 	if exists, err := checkLocalServerExists(name); err != nil {
 		return err
 	} else if exists {
@@ -289,6 +352,18 @@ func run(ctx context.Context, buildURL, name, category, userProvidedImage string
 `, name)
 	}
 
+	if scaffoldedDockerfile != "" {
+		step2 = fmt.Sprintf(`
+  2. A %[2]s was generated from a template because the upstream repo has
+     none - review it, then commit it to %[1]s (or a fork) before
+     running:
+
+     task build -- %[3]s
+     task catalog -- %[3]s
+     docker mcp catalog import $PWD/catalogs/%[3]s/catalog.yaml
+`, projectURL, filepath.Join(serverDir, scaffoldedDockerfile), name)
+	}
+
 	fmt.Printf(`
 -----------------------------------------
 
@@ -308,6 +383,102 @@ What to do next?
 	return nil
 }
 
+// imageDigest resolves the locally built or pulled image tagged as ref to
+// its content digest via `docker inspect`, so catalog entries can be pinned
+// to an immutable reference rather than a mutable tag.
+func imageDigest(ctx context.Context, ref string) (string, error) {
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.Id}}", ref).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// maybeScaffoldDockerfile renders a template Dockerfile into
+// servers/<name>/Dockerfile when the upstream repo has none, returning the
+// filename to record in server.yaml's Source.Dockerfile ("" if the repo
+// already has a Dockerfile and there's nothing to do). choice is either
+// "auto" (sniff the repo for a language manifest) or an explicit
+// scaffold.Template name.
+func maybeScaffoldDockerfile(ctx context.Context, client *github.Client, projectURL, branch, directory, name, tag, choice string) (string, error) {
+	if _, err := client.GetFileContent(ctx, projectURL, branch, repoPath(directory, "Dockerfile")); err == nil {
+		return "", nil
+	} else if !errors.Is(err, github.ErrNotFound) {
+		return "", err
+	}
+
+	var tmpl scaffold.Template
+	if choice == "auto" {
+		detected, ok := scaffold.Detect(func(path string) bool {
+			_, err := client.GetFileContent(ctx, projectURL, branch, repoPath(directory, path))
+			return err == nil
+		})
+		if !ok {
+			return "", fmt.Errorf("--generate-dockerfile=auto: couldn't detect a language for %s, pass an explicit template (%s)", projectURL, strings.Join(scaffold.Templates(), ", "))
+		}
+		tmpl = detected
+	} else {
+		found := false
+		for _, known := range scaffold.Templates() {
+			if known == choice {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("--generate-dockerfile %q is not a known template (%s)", choice, strings.Join(scaffold.Templates(), ", "))
+		}
+		tmpl = scaffold.Template(choice)
+	}
+
+	rendered, err := scaffold.Render(tmpl, defaultScaffoldParams(tmpl, name, tag))
+	if err != nil {
+		return "", err
+	}
+
+	serverDir := filepath.Join("servers", name)
+	if err := os.MkdirAll(serverDir, 0755); err != nil {
+		return "", err
+	}
+
+	dockerfile := "Dockerfile"
+	if err := os.WriteFile(filepath.Join(serverDir, dockerfile), []byte(rendered), 0644); err != nil {
+		return "", fmt.Errorf("writing generated Dockerfile: %w", err)
+	}
+
+	fmt.Printf("No Dockerfile found upstream, generated one from the %q template.\n", tmpl)
+	return dockerfile, nil
+}
+
+// repoPath joins directory (the server's Source.Directory, possibly "" or
+// ".") with a path relative to it, for probing files in the upstream repo.
+func repoPath(directory, path string) string {
+	if directory == "" || directory == "." {
+		return path
+	}
+	return directory + "/" + path
+}
+
+// defaultScaffoldParams guesses placeholder substitutions for a freshly
+// scaffolded Dockerfile; like the rest of `task create`'s output, these are
+// meant to be reviewed and corrected rather than trusted as-is.
+func defaultScaffoldParams(tmpl scaffold.Template, name, tag string) scaffold.Params {
+	switch tmpl {
+	case scaffold.TemplateNode:
+		return scaffold.Params{Image: tag, Runtime: "node", Entrypoint: "index.js", InstallCmd: "npm ci --omit=dev"}
+	case scaffold.TemplatePythonUV:
+		return scaffold.Params{Image: tag, Runtime: "python3", Entrypoint: "main.py", InstallCmd: "uv sync --frozen --no-dev"}
+	case scaffold.TemplatePythonPip:
+		return scaffold.Params{Image: tag, Runtime: "python3", Entrypoint: "main.py", InstallCmd: "pip install --no-cache-dir -r requirements.txt"}
+	case scaffold.TemplateGo:
+		return scaffold.Params{Image: tag, Runtime: "/usr/local/bin/" + name, Entrypoint: name, InstallCmd: "go build -o " + name + " ."}
+	case scaffold.TemplateRust:
+		return scaffold.Params{Image: tag, Runtime: "/usr/local/bin/" + name, Entrypoint: name, InstallCmd: "cargo build --release"}
+	default:
+		return scaffold.Params{Image: tag}
+	}
+}
+
 func guessName(projectURL string) string {
 	parts := strings.Split(strings.ToLower(projectURL), "/")
 	name := parts[len(parts)-1]
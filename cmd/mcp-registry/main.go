@@ -0,0 +1,37 @@
+// Command mcp-registry exposes the generated JSON Schema for server.yaml so
+// the wizard, editor tooling, and PR review can all validate against the
+// same source of truth as pkg/servers itself, rather than each
+// reverse-engineering the shape from Go struct tags independently.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// main dispatches the CLI to a specific sub-command implementation.
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-registry <command> [options]")
+		os.Exit(2)
+	}
+
+	cmd := os.Args[1]
+	args := os.Args[2:]
+
+	var err error
+	switch cmd {
+	case "schema":
+		err = runSchema(args)
+	case "validate":
+		err = runValidate(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", cmd)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/docker/mcp-registry/pkg/servers/jsonschema"
+)
+
+// runSchema emits the Draft 2020-12 JSON Schema reflected from
+// servers.Server, to stdout or, if --out is set, to a file.
+func runSchema(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	out := flags.String("out", "", "write the schema here instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	payload, err := jsonschema.MarshalIndent()
+	if err != nil {
+		return err
+	}
+	payload = append(payload, '\n')
+
+	if *out == "" {
+		_, err := os.Stdout.Write(payload)
+		return err
+	}
+	return os.WriteFile(*out, payload, 0o644)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-registry/pkg/servers/jsonschema"
+)
+
+// runValidate schema-checks every server.yaml under dir (the sole
+// positional argument, defaulting to "servers") against the schema
+// reflected from servers.Server, reporting violations with file:line
+// locations the way a compiler would. Unlike cmd/validate-registry, which
+// enforces the hand-maintained schemas/server.schema.json plus the
+// structural rules a JSON Schema can't express, this checks only what
+// pkg/servers' Go types themselves guarantee.
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dir := "servers"
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	schema, err := compiledSchema()
+	if err != nil {
+		return err
+	}
+
+	names, err := serverNames(dir)
+	if err != nil {
+		return err
+	}
+
+	var violations []violation
+	for _, name := range names {
+		v, err := validateServerFile(schema, filepath.Join(dir, name, "server.yaml"))
+		if err != nil {
+			return fmt.Errorf("validating %s: %w", name, err)
+		}
+		violations = append(violations, v...)
+	}
+
+	for _, v := range violations {
+		fmt.Printf("%s:%d: %s\n", v.file, v.line, v.message)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d schema violation(s) under %s", len(violations), dir)
+	}
+
+	fmt.Printf("✅ every server.yaml under %s matches the reflected schema\n", dir)
+	return nil
+}
+
+// compiledSchema compiles the schema jsonschema.Generate reflects from
+// servers.Server.
+func compiledSchema() (*gojsonschema.Schema, error) {
+	payload, err := jsonschema.MarshalIndent()
+	if err != nil {
+		return nil, err
+	}
+	return gojsonschema.NewSchema(gojsonschema.NewBytesLoader(payload))
+}
+
+// serverNames returns the sorted list of server directory names under root
+// that contain a server.yaml.
+func serverNames(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, entry.Name(), "server.yaml")); err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// violation is a single schema failure, located back to the server.yaml
+// file and line it came from.
+type violation struct {
+	file    string
+	line    int
+	message string
+}
+
+// validateServerFile schema-checks the server.yaml at path and locates each
+// violation's line via its yaml.v3 node tree.
+func validateServerFile(schema *gojsonschema.Schema, path string) ([]violation, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return []violation{{file: path, line: 1, message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	var data any
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return []violation{{file: path, line: 1, message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []violation
+	for _, e := range result.Errors() {
+		line, _ := locateYAMLPath(&doc, schemaViolationPath(e))
+		violations = append(violations, violation{file: path, line: line, message: e.Description()})
+	}
+	return violations, nil
+}
+
+// schemaViolationPath normalizes a gojsonschema error to the dotted path of
+// the field that's actually wrong. For a "required" failure, Field() names
+// the containing object rather than the missing property, so the missing
+// property (from Details()["property"]) is appended.
+func schemaViolationPath(e gojsonschema.ResultError) string {
+	field := e.Field()
+	if e.Type() != "required" {
+		return field
+	}
+	prop, ok := e.Details()["property"].(string)
+	if !ok {
+		return field
+	}
+	if field == "(root)" {
+		return "(root)." + prop
+	}
+	return field + "." + prop
+}
+
+// locateYAMLPath walks doc to find the line/column of the value at a
+// schemaViolationPath, so a violation reads like a compiler error instead
+// of naming the field in the abstract.
+func locateYAMLPath(doc *yaml.Node, path string) (line, column int) {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	path = strings.TrimPrefix(path, "(root)")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return node.Line, node.Column
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		if index, err := strconv.Atoi(segment); err == nil {
+			if node.Kind != yaml.SequenceNode || index >= len(node.Content) {
+				return node.Line, node.Column
+			}
+			node = node.Content[index]
+			continue
+		}
+
+		if node.Kind != yaml.MappingNode {
+			return node.Line, node.Column
+		}
+		_, value, found := yamlField(node, segment)
+		if !found {
+			return node.Line, node.Column
+		}
+		node = value
+	}
+	return node.Line, node.Column
+}
+
+// yamlField returns the key/value node pair within mapping whose key
+// scalar is name.
+func yamlField(mapping *yaml.Node, name string) (key, value *yaml.Node, found bool) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1], true
+		}
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,378 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Command open-pin-prs turns the server.yaml changes update-pins committed
+// into pull requests, one per server by default (or a single rolled-up PR
+// with --rollup), reusing an already-open PR for a server instead of
+// filing a duplicate.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/spf13/cobra"
+)
+
+// options stores parsed CLI arguments.
+type options struct {
+	// Registry is the GitHub repository URL pull requests are opened
+	// against.
+	Registry string
+	// Remote is the git remote pin-update branches are pushed to.
+	Remote string
+	// Base is the ref update-pins' commit(s) are compared against to find
+	// what changed, e.g. "HEAD~1" for a single update-pins commit on top
+	// of the branch PRs should target.
+	Base string
+	// TargetBranch is the branch opened pull requests are based on.
+	TargetBranch string
+	// Rollup files a single pull request covering every changed server
+	// instead of one per server.
+	Rollup bool
+	// DryRun prints what would be filed without pushing branches or
+	// calling the GitHub API to open pull requests.
+	DryRun bool
+}
+
+var cliOpts = options{
+	Remote:       "origin",
+	Base:         "HEAD~1",
+	TargetBranch: "main",
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "open-pin-prs",
+	Short: "Open pull requests for the server.yaml pin updates update-pins committed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := options{
+			Registry:     strings.TrimSpace(cliOpts.Registry),
+			Remote:       strings.TrimSpace(cliOpts.Remote),
+			Base:         strings.TrimSpace(cliOpts.Base),
+			TargetBranch: strings.TrimSpace(cliOpts.TargetBranch),
+			Rollup:       cliOpts.Rollup,
+			DryRun:       cliOpts.DryRun,
+		}
+
+		if opts.Registry == "" {
+			return errors.New("--registry is required")
+		}
+
+		return run(cmd.Context(), opts)
+	},
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&cliOpts.Registry, "registry", cliOpts.Registry, "GitHub repository URL pull requests are opened against.")
+	rootCmd.Flags().StringVar(&cliOpts.Remote, "remote", cliOpts.Remote, "git remote pin-update branches are pushed to.")
+	rootCmd.Flags().StringVar(&cliOpts.Base, "base", cliOpts.Base, "Ref to diff against to find update-pins' changes.")
+	rootCmd.Flags().StringVar(&cliOpts.TargetBranch, "target-branch", cliOpts.TargetBranch, "Branch opened pull requests are based on.")
+	rootCmd.Flags().BoolVar(&cliOpts.Rollup, "rollup", cliOpts.Rollup, "File one pull request covering every changed server instead of one per server.")
+	rootCmd.Flags().BoolVar(&cliOpts.DryRun, "dry-run", cliOpts.DryRun, "Print what would be filed without pushing branches or opening pull requests.")
+
+	_ = rootCmd.MarkFlagRequired("registry")
+}
+
+// main is the entry point for the open-pin-prs CLI.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	rootCmd.SilenceUsage = true
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run finds the servers opts.Base..HEAD pinned to a new commit, groups them
+// into one or more pull-request plans, and files each as a branch and pull
+// request (or just prints it, under --dry-run).
+func run(ctx context.Context, opts options) error {
+	changed, err := changedServerPaths(opts.Base)
+	if err != nil {
+		return fmt.Errorf("listing changed servers: %w", err)
+	}
+
+	var pins []pinUpdate
+	for _, path := range changed {
+		pin, ok, err := loadPinUpdate(opts.Base, path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		if ok {
+			pins = append(pins, pin)
+		}
+	}
+
+	if len(pins) == 0 {
+		fmt.Println("No pin updates between", opts.Base, "and HEAD; nothing to do.")
+		return nil
+	}
+
+	client := github.New()
+	for _, plan := range groupIntoPlans(pins, opts.Rollup) {
+		if err := filePullRequest(ctx, client, opts, plan); err != nil {
+			return fmt.Errorf("filing pull request for %s: %w", plan.branch, err)
+		}
+	}
+
+	return nil
+}
+
+// pinUpdate is a single server's commit (and, for tracked sources, tag)
+// transition between opts.Base and HEAD.
+type pinUpdate struct {
+	name    string
+	path    string
+	project string
+	tag     string
+	old     string
+	new     string
+}
+
+// prPlan is one or more pin updates rolled into a single branch and pull
+// request.
+type prPlan struct {
+	branch string
+	title  string
+	pins   []pinUpdate
+}
+
+// changedServerPaths returns the server.yaml paths that differ between base
+// and HEAD.
+func changedServerPaths(base string) ([]string, error) {
+	out, err := runGit("diff", "--name-only", base, "HEAD", "--", "servers")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, "/server.yaml") {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// loadPinUpdate compares path's server.yaml as of base and HEAD, returning
+// ok=false if the server's pinned commit didn't actually change (e.g. only
+// a cosmetic reformat).
+func loadPinUpdate(base, path string) (pinUpdate, bool, error) {
+	before, err := serverAt(base, path)
+	if err != nil {
+		return pinUpdate{}, false, err
+	}
+	after, err := serverAt("HEAD", path)
+	if err != nil {
+		return pinUpdate{}, false, err
+	}
+
+	oldSHA := strings.ToLower(before.Source.Commit)
+	newSHA := strings.ToLower(after.Source.Commit)
+	if oldSHA == newSHA {
+		return pinUpdate{}, false, nil
+	}
+
+	return pinUpdate{
+		name:    after.Name,
+		path:    path,
+		project: after.Source.Project,
+		tag:     after.Source.Tag,
+		old:     oldSHA,
+		new:     newSHA,
+	}, true, nil
+}
+
+// serverAt reads and parses path's server.yaml as of ref.
+func serverAt(ref, path string) (servers.Server, error) {
+	raw, err := runGit("show", ref+":"+path)
+	if err != nil {
+		return servers.Server{}, err
+	}
+	return servers.Parse([]byte(raw))
+}
+
+// groupIntoPlans folds pins into pull-request plans: one per server, or a
+// single rolled-up plan covering all of them when rollup is set.
+func groupIntoPlans(pins []pinUpdate, rollup bool) []prPlan {
+	if rollup {
+		return []prPlan{{
+			branch: "pin-update/rollup",
+			title:  fmt.Sprintf("Update %d server pin(s)", len(pins)),
+			pins:   pins,
+		}}
+	}
+
+	plans := make([]prPlan, 0, len(pins))
+	for _, pin := range pins {
+		plans = append(plans, prPlan{
+			branch: "pin-update/" + pin.name,
+			title:  fmt.Sprintf("Update %s pin to %s", pin.name, shortSHA(pin.new)),
+			pins:   []pinUpdate{pin},
+		})
+	}
+	return plans
+}
+
+// filePullRequest publishes plan's branch (unless --dry-run) and opens a
+// pull request for it, reusing one that's already open on the same branch
+// instead of creating a duplicate.
+func filePullRequest(ctx context.Context, client *github.Client, opts options, plan prPlan) error {
+	body := renderBody(ctx, client, plan)
+
+	if opts.DryRun {
+		fmt.Printf("Would open %q (branch %s -> %s):\n%s\n", plan.title, plan.branch, opts.TargetBranch, body)
+		return nil
+	}
+
+	if err := publishBranch(opts, plan); err != nil {
+		return fmt.Errorf("publishing branch %s: %w", plan.branch, err)
+	}
+
+	existing, err := client.FindOpenPullRequest(ctx, opts.Registry, plan.branch)
+	if err != nil {
+		return fmt.Errorf("checking for an existing pull request: %w", err)
+	}
+	if existing != nil {
+		fmt.Printf("Reused existing pull request #%d for branch %s.\n", existing.GetNumber(), plan.branch)
+		return nil
+	}
+
+	pr, err := client.CreatePullRequest(ctx, opts.Registry, plan.title, body, plan.branch, opts.TargetBranch)
+	if err != nil {
+		return fmt.Errorf("opening pull request: %w", err)
+	}
+	fmt.Printf("Opened pull request #%d for branch %s.\n", pr.GetNumber(), plan.branch)
+	return nil
+}
+
+// renderBody builds a pull-request description covering every pin in plan:
+// the old -> new SHA (and tag, if tracked), a link to the upstream compare
+// view, and the upstream commit messages in between.
+func renderBody(ctx context.Context, client *github.Client, plan prPlan) string {
+	var b strings.Builder
+	for _, pin := range plan.pins {
+		fmt.Fprintf(&b, "## %s\n\n", pin.name)
+		if pin.tag != "" {
+			fmt.Fprintf(&b, "`%s` -> `%s` (tag %s)\n\n", shortSHA(pin.old), shortSHA(pin.new), pin.tag)
+		} else {
+			fmt.Fprintf(&b, "`%s` -> `%s`\n\n", shortSHA(pin.old), shortSHA(pin.new))
+		}
+		fmt.Fprintf(&b, "Compare: %s\n\n", compareURL(pin.project, pin.old, pin.new))
+
+		comparison, err := client.CompareCommits(ctx, pin.project, pin.old, pin.new)
+		if err != nil {
+			fmt.Fprintf(&b, "_Couldn't fetch upstream commit messages: %v_\n\n", err)
+			continue
+		}
+		for _, commit := range comparison.Commits {
+			message := strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0]
+			fmt.Fprintf(&b, "- %s %s\n", shortSHA(commit.GetSHA()), message)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// compareURL builds the upstream GitHub compare view for project's old..new
+// range.
+func compareURL(project, old, newSHA string) string {
+	return strings.TrimSuffix(project, "/") + "/compare/" + old + "..." + newSHA
+}
+
+// publishBranch materializes plan's branch from opts.Base, applying only
+// the diff for the servers plan.pins touch, then commits and pushes it.
+// The caller's original checkout is restored before returning.
+func publishBranch(opts options, plan prPlan) error {
+	origBranch, err := runGit("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+	origBranch = strings.TrimSpace(origBranch)
+	defer func() { _, _ = runGit("checkout", origBranch) }()
+
+	paths := make([]string, 0, len(plan.pins))
+	for _, pin := range plan.pins {
+		paths = append(paths, pin.path)
+	}
+
+	diff, err := runGit(append([]string{"diff", opts.Base, "HEAD", "--"}, paths...)...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGit("checkout", "-B", plan.branch, opts.Base); err != nil {
+		return err
+	}
+	if err := applyDiff(diff); err != nil {
+		return fmt.Errorf("applying pin update: %w", err)
+	}
+	if _, err := runGit("commit", "-m", plan.title); err != nil {
+		return err
+	}
+	if _, err := runGit("push", "--force-with-lease", opts.Remote, plan.branch); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applyDiff feeds diff to `git apply --index` via stdin, staging the
+// result.
+func applyDiff(diff string) error {
+	cmd := exec.Command("git", "apply", "--index", "-")
+	cmd.Stdin = strings.NewReader(diff)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git apply: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// shortSHA returns sha's conventional 7-character short form.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// runGit runs a git command in the current directory, returning its
+// combined output.
+func runGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w\n%s", strings.Join(args, " "), err, string(output))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/sarif"
+	ghapi "github.com/google/go-github/v70/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// levelNote is the lowest severity this tool will post as a PR comment.
+	levelNote = string(sarif.LevelNote)
+)
+
+// options stores parsed CLI arguments.
+type options struct {
+	// Repository is the GitHub repository URL the pull request belongs to.
+	Repository string
+	// PullRequest is the pull request number to comment on.
+	PullRequest int
+	// CommitSHA is the head commit the inline comments are anchored to.
+	CommitSHA string
+	// SarifPath is the findings.sarif file produced by the security reviewer.
+	SarifPath string
+	// MinLevel is the lowest SARIF level that is posted as a comment.
+	MinLevel string
+	// Summary is optional top-level text for the review.
+	Summary string
+}
+
+var cliOpts = options{
+	MinLevel: levelNote,
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "post-review-comments",
+	Short: "Post security-reviewer SARIF findings as inline pull request review comments",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := options{
+			Repository:  strings.TrimSpace(cliOpts.Repository),
+			PullRequest: cliOpts.PullRequest,
+			CommitSHA:   strings.TrimSpace(cliOpts.CommitSHA),
+			SarifPath:   strings.TrimSpace(cliOpts.SarifPath),
+			MinLevel:    strings.ToLower(strings.TrimSpace(cliOpts.MinLevel)),
+			Summary:     strings.TrimSpace(cliOpts.Summary),
+		}
+
+		if opts.Repository == "" {
+			return errors.New("--repo is required")
+		}
+		if opts.PullRequest <= 0 {
+			return errors.New("--pr must be a positive pull request number")
+		}
+		if opts.CommitSHA == "" {
+			return errors.New("--commit is required")
+		}
+		if opts.SarifPath == "" {
+			return errors.New("--sarif is required")
+		}
+		if !sarif.Level(opts.MinLevel).Valid() {
+			return fmt.Errorf("invalid --min-level %q (supported: error, warning, note)", opts.MinLevel)
+		}
+
+		return run(cmd.Context(), opts)
+	},
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&cliOpts.Repository, "repo", cliOpts.Repository, "GitHub repository URL the pull request belongs to.")
+	rootCmd.Flags().IntVar(&cliOpts.PullRequest, "pr", cliOpts.PullRequest, "Pull request number to comment on.")
+	rootCmd.Flags().StringVar(&cliOpts.CommitSHA, "commit", cliOpts.CommitSHA, "Head commit SHA the comments are anchored to.")
+	rootCmd.Flags().StringVar(&cliOpts.SarifPath, "sarif", cliOpts.SarifPath, "Path to the findings.sarif file produced by the security reviewer.")
+	rootCmd.Flags().StringVar(&cliOpts.MinLevel, "min-level", cliOpts.MinLevel, "Lowest SARIF level to post as a comment (error, warning, or note).")
+	rootCmd.Flags().StringVar(&cliOpts.Summary, "summary", cliOpts.Summary, "Optional top-level text for the review.")
+
+	_ = rootCmd.MarkFlagRequired("repo")
+	_ = rootCmd.MarkFlagRequired("pr")
+	_ = rootCmd.MarkFlagRequired("commit")
+	_ = rootCmd.MarkFlagRequired("sarif")
+}
+
+// main is the entry point for the post-review-comments CLI.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	rootCmd.SilenceUsage = true
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		exitWithError(err)
+	}
+}
+
+// run reads the SARIF findings and posts them as a single pull request
+// review made up of one inline comment per result at or above MinLevel.
+func run(ctx context.Context, opts options) error {
+	log, err := sarif.ReadFile(opts.SarifPath)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", opts.SarifPath, err)
+	}
+
+	comments := draftComments(log, opts.MinLevel)
+	if len(comments) == 0 {
+		fmt.Printf("No findings at or above level %q; skipping review.\n", opts.MinLevel)
+		return nil
+	}
+
+	client := github.New()
+	review, err := client.CreateReviewComments(ctx, opts.Repository, opts.PullRequest, opts.CommitSHA, opts.Summary, comments)
+	if err != nil {
+		return fmt.Errorf("post review comments: %w", err)
+	}
+
+	fmt.Printf("Posted %d finding(s) as review %d on PR #%d.\n", len(comments), review.GetID(), opts.PullRequest)
+	return nil
+}
+
+// draftComments converts every SARIF result at or above minLevel into a
+// GitHub draft review comment anchored to its reported file and line.
+func draftComments(log sarif.Log, minLevel string) []*ghapi.DraftReviewComment {
+	var comments []*ghapi.DraftReviewComment
+	for _, result := range log.Results() {
+		if !meetsMinLevel(result.Level, minLevel) {
+			continue
+		}
+		for _, loc := range result.Locations {
+			path := loc.PhysicalLocation.ArtifactLocation.URI
+			line := loc.PhysicalLocation.Region.EndLine
+			if line == 0 {
+				line = loc.PhysicalLocation.Region.StartLine
+			}
+			comments = append(comments, &ghapi.DraftReviewComment{
+				Path: ghapi.Ptr(path),
+				Line: ghapi.Ptr(line),
+				Body: ghapi.Ptr(formatComment(result)),
+			})
+		}
+	}
+	return comments
+}
+
+// formatComment renders a single result as the body of an inline comment.
+func formatComment(result sarif.Result) string {
+	return fmt.Sprintf("**[%s] %s**\n\n%s", strings.ToUpper(string(result.Level)), result.RuleID, result.Message.Text)
+}
+
+// severityRank orders SARIF levels from least to most severe so MinLevel
+// can be compared against a result's level.
+var severityRank = map[string]int{
+	string(sarif.LevelNote):    0,
+	string(sarif.LevelWarning): 1,
+	string(sarif.LevelError):   2,
+}
+
+// meetsMinLevel reports whether level is at least as severe as minLevel.
+func meetsMinLevel(level sarif.Level, minLevel string) bool {
+	return severityRank[string(level)] >= severityRank[minLevel]
+}
+
+// exitWithError prints an error and terminates the process.
+func exitWithError(err error) {
+	fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	os.Exit(1)
+}
@@ -19,6 +19,23 @@ var (
 		"sse",
 	}
 
+	// wizardModes lists the two kinds of server.yaml this wizard can
+	// produce: a "remote" entry (reached over HTTP) or a "container/stdio"
+	// one (run as a local container, driven over its process's stdio).
+	wizardModes = []string{
+		"remote",
+		"container/stdio",
+	}
+
+	// parameterTypes lists the JSON Schema scalar types the parameter
+	// collection step offers; config.parameters doesn't need anything more
+	// exotic than these for a wizard-authored server.yaml.
+	parameterTypes = []string{
+		"string",
+		"number",
+		"boolean",
+	}
+
 	titleStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#4ECDC4")).
 			Bold(true).
@@ -43,12 +60,86 @@ type RemoteWizardData struct {
 	UseOAuth      bool
 }
 
+// ContainerWizardData collects everything needed to render a
+// "container/stdio"-type server.yaml: an image and/or buildable source, the
+// run-time command line, and the config schema the server exposes.
+type ContainerWizardData struct {
+	ServerName  string
+	Category    string
+	Title       string
+	Description string
+	Icon        string
+	Image       string
+
+	Project    string
+	Upstream   string
+	Branch     string
+	Directory  string
+	Dockerfile string
+
+	Command        string
+	Volumes        []string
+	Env            map[string]string
+	AllowHosts     string
+	DisableNetwork bool
+
+	AddSecrets bool
+	Secrets    []ContainerSecretInput
+	AddEnvVars bool
+	EnvVars    []ContainerEnvInput
+	AddParams  bool
+	Parameters []ContainerParamInput
+}
+
+type ContainerSecretInput struct {
+	Name     string
+	EnvName  string
+	Example  string
+	Required bool
+}
+
+type ContainerEnvInput struct {
+	Name    string
+	Example string
+	Value   string
+}
+
+type ContainerParamInput struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
 func main() {
-	fmt.Print(titleStyle.Render("🐳 MCP Remote Server Registry Wizard"))
-	fmt.Print(headerStyle.Render("Welcome! Let's add your remote MCP server to the registry."))
+	fmt.Print(titleStyle.Render("🐳 MCP Server Registry Wizard"))
+	fmt.Print(headerStyle.Render("Welcome! Let's add your MCP server to the registry."))
 	fmt.Println()
 	fmt.Println()
 
+	mode := wizardModes[0]
+	modeForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Server Type").
+				Description("Is your server reached over the network, or run as a local container/stdio process?").
+				Options(huh.NewOptions(wizardModes...)...).
+				Value(&mode),
+		).Title("📋 Server Type"),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := modeForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	if mode == "remote" {
+		runRemoteWizard()
+		return
+	}
+	runContainerWizard()
+}
+
+func runRemoteWizard() {
 	var data RemoteWizardData
 
 	// Basic Information Form
@@ -284,6 +375,568 @@ func generateAndSave(data *RemoteWizardData) error {
 	return nil
 }
 
+// runContainerWizard drives the "container/stdio" path: a server built from
+// source and/or a pre-built image, run as a local container and probed over
+// its process's stdio rather than an HTTP endpoint.
+func runContainerWizard() {
+	var data ContainerWizardData
+
+	basicForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Server Name").
+				Description("Enter the name for your MCP server (e.g., 'my-awesome-server')").
+				Value(&data.ServerName).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("server name is required")
+					}
+					if strings.Contains(s, " ") {
+						return fmt.Errorf("server name cannot contain spaces")
+					}
+					exists, err := checkLocalServerExists(s)
+					if err != nil {
+						return err
+					}
+					if exists {
+						return fmt.Errorf("server name %s already exists", s)
+					}
+					return nil
+				}),
+
+			huh.NewSelect[string]().
+				Title("Category").
+				Description("Select the category that best describes your MCP server").
+				Options(huh.NewOptions(servers.Categories...)...).
+				Value(&data.Category),
+		).Title("📋 Basic Information"),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := basicForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	detailsForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Server Title").
+				Description("Enter a descriptive title for your MCP server").
+				Value(&data.Title).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("title is required")
+					}
+					return nil
+				}),
+
+			huh.NewText().
+				Title("Description").
+				Description("Enter a detailed description of what your MCP server does").
+				Value(&data.Description).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("description is required")
+					}
+					return nil
+				}),
+
+			huh.NewInput().
+				Title("Icon URL (optional)").
+				Description("Enter an icon URL (or leave the default)").
+				Value(&data.Icon),
+
+			huh.NewInput().
+				Title("Docker Image (optional)").
+				Description("Enter a pre-built image (e.g., mcp/NAME), or leave empty to build from Source.Dockerfile below").
+				Value(&data.Image),
+		).Title("📝 Server Details"),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := detailsForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	sourceForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Source Project (optional)").
+				Description("Git repository to build the image from (e.g., https://github.com/user/repo), required if no Docker Image was given above").
+				Value(&data.Project),
+
+			huh.NewInput().
+				Title("Upstream (optional)").
+				Description("Upstream project URL, if different from Source Project (e.g., a fork's origin)").
+				Value(&data.Upstream),
+
+			huh.NewInput().
+				Title("Branch (optional)").
+				Description("Branch to build from (leave empty for the default branch)").
+				Value(&data.Branch),
+
+			huh.NewInput().
+				Title("Directory (optional)").
+				Description("Subdirectory containing the Dockerfile, if the project isn't built from its root").
+				Value(&data.Directory),
+
+			huh.NewInput().
+				Title("Dockerfile (optional)").
+				Description("Path to the Dockerfile, relative to Directory (leave empty for 'Dockerfile')").
+				Value(&data.Dockerfile),
+		).Title("🏗️ Source").
+			WithHideFunc(func() bool { return strings.TrimSpace(data.Image) != "" }),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := sourceForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+	if err := validateContainerBuildSource(&data); err != nil {
+		log.Fatal(err)
+	}
+
+	runForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Command (optional)").
+				Description("Override the container's entrypoint/command, space-separated (leave empty to use the image's default)").
+				Value(&data.Command),
+
+			huh.NewInput().
+				Title("Allowed Hosts (optional)").
+				Description("Comma-separated hostnames the container may reach (leave empty to allow all)").
+				Value(&data.AllowHosts),
+
+			huh.NewConfirm().
+				Title("Disable Network?").
+				Description("Run the container with networking disabled entirely").
+				Value(&data.DisableNetwork),
+		).Title("🏃 Run Configuration"),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := runForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := collectContainerVolumes(&data); err != nil {
+		log.Fatal(err)
+	}
+
+	configForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Add Secrets?").
+				Description("Does your server require any secret variables (passwords, API keys, etc.)?").
+				Value(&data.AddSecrets),
+
+			huh.NewConfirm().
+				Title("Add Environment Variables?").
+				Description("Does your server require any environment variables for configuration?").
+				Value(&data.AddEnvVars),
+
+			huh.NewConfirm().
+				Title("Add Configuration Parameters?").
+				Description("Does your server accept user-configurable parameters (exposed as config.parameters)?").
+				Value(&data.AddParams),
+		).Title("⚙️ Configuration"),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := configForm.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	if data.AddSecrets {
+		if err := collectContainerSecrets(&data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if data.AddEnvVars {
+		if err := collectContainerEnvVars(&data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if data.AddParams {
+		if err := collectContainerParameters(&data); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := generateAndSaveContainer(&data); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Print(headerStyle.Render("✅ Success! Your MCP server configuration has been generated."))
+	fmt.Println()
+	fmt.Printf("📁 Generated files in servers/%s/:\n", data.ServerName)
+	fmt.Printf("   - server.yaml (server configuration)\n")
+	fmt.Printf("   - tools.json (empty list, populate once you've probed the server)\n")
+	fmt.Printf("   - readme.md\n")
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Println("1. Review the generated server.yaml file")
+	fmt.Println("2. Build your server locally with: task build -- " + data.ServerName)
+	fmt.Println("3. Generate the catalog with: task catalog -- " + data.ServerName)
+	fmt.Println("4. Test your server locally in Docker Desktop with: task import -- " + data.ServerName)
+	fmt.Println("5. Reset your catalog in Docker Desktop with: task reset")
+	fmt.Println("6. Create a pull request to add it to the registry")
+}
+
+// validateContainerBuildSource requires at least one way to get a
+// container image: either a pre-built Image, or a Project to build one
+// from via its Dockerfile.
+func validateContainerBuildSource(data *ContainerWizardData) error {
+	if strings.TrimSpace(data.Image) == "" && strings.TrimSpace(data.Project) == "" {
+		return fmt.Errorf("either a Docker Image or a Source Project (with a Dockerfile) is required")
+	}
+	return nil
+}
+
+func collectContainerVolumes(data *ContainerWizardData) error {
+	fmt.Print(headerStyle.Render("💾 Configure Volumes"))
+	fmt.Println()
+
+	var addVolumes bool
+	if err := huh.NewConfirm().
+		Title("Add Volumes?").
+		Description("Do you want to mount any volumes into your MCP server's container?").
+		Value(&addVolumes).
+		Run(); err != nil {
+		return err
+	}
+	if !addVolumes {
+		return nil
+	}
+
+	for {
+		var hostPath, containerPath string
+		var addAnother bool
+
+		volumeForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Host Path").
+					Description("Path (or {{server.name}} placeholder) on the host").
+					Value(&hostPath).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("host path is required")
+						}
+						return nil
+					}),
+
+				huh.NewInput().
+					Title("Container Path").
+					Description("Path inside the container the host path is mounted at").
+					Value(&containerPath).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("container path is required")
+						}
+						return nil
+					}),
+
+				huh.NewConfirm().
+					Title("Add Another Volume?").
+					Value(&addAnother),
+			).Title("Volume Configuration"),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := volumeForm.Run(); err != nil {
+			return err
+		}
+
+		data.Volumes = append(data.Volumes, fmt.Sprintf("%s:%s", hostPath, containerPath))
+
+		if !addAnother {
+			break
+		}
+	}
+
+	return nil
+}
+
+func collectContainerSecrets(data *ContainerWizardData) error {
+	fmt.Print(headerStyle.Render("🔐 Configure Secrets"))
+	fmt.Println()
+
+	for {
+		var secret ContainerSecretInput
+		var addAnother bool
+
+		secretForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Secret Name").
+					Description("Enter the secret name (e.g., 'api_key', 'password')").
+					Value(&secret.Name).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("secret name is required")
+						}
+						return nil
+					}),
+
+				huh.NewInput().
+					Title("Environment Variable Name").
+					Description("Enter the environment variable name (e.g., 'API_KEY', 'PASSWORD')").
+					Value(&secret.EnvName).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("environment variable name is required")
+						}
+						return nil
+					}),
+
+				huh.NewInput().
+					Title("Example Value").
+					Description("Enter an example value (for documentation)").
+					Value(&secret.Example),
+
+				huh.NewConfirm().
+					Title("Required?").
+					Description("Must this secret be set for the server to run?").
+					Value(&secret.Required),
+
+				huh.NewConfirm().
+					Title("Add Another Secret?").
+					Value(&addAnother),
+			).Title("Secret Configuration"),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := secretForm.Run(); err != nil {
+			return err
+		}
+
+		data.Secrets = append(data.Secrets, secret)
+
+		if !addAnother {
+			break
+		}
+	}
+
+	return nil
+}
+
+func collectContainerEnvVars(data *ContainerWizardData) error {
+	fmt.Print(headerStyle.Render("🌍 Configure Environment Variables"))
+	fmt.Println()
+
+	for {
+		var envVar ContainerEnvInput
+		var addAnother bool
+
+		envForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Environment Variable Name").
+					Description("Enter the environment variable name (e.g., 'HOST', 'PORT')").
+					Value(&envVar.Name).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("environment variable name is required")
+						}
+						return nil
+					}),
+
+				huh.NewInput().
+					Title("Example Value").
+					Description("Enter an example value").
+					Value(&envVar.Example),
+
+				huh.NewInput().
+					Title("Template Value").
+					Description("Enter the template value (e.g., '{{server.host}}') or leave empty to use the variable name").
+					Value(&envVar.Value),
+
+				huh.NewConfirm().
+					Title("Add Another Environment Variable?").
+					Value(&addAnother),
+			).Title("Environment Variable Configuration"),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := envForm.Run(); err != nil {
+			return err
+		}
+
+		if envVar.Value == "" {
+			envVar.Value = fmt.Sprintf("{{%s.%s}}", strings.ToLower(data.ServerName), strings.ToLower(envVar.Name))
+		}
+
+		data.EnvVars = append(data.EnvVars, envVar)
+
+		if !addAnother {
+			break
+		}
+	}
+
+	return nil
+}
+
+// collectContainerParameters iteratively prompts for one config.parameters
+// property at a time, rather than asking the user to hand-author a JSON
+// Schema fragment.
+func collectContainerParameters(data *ContainerWizardData) error {
+	fmt.Print(headerStyle.Render("🧩 Configure Parameters"))
+	fmt.Println()
+
+	for {
+		var param ContainerParamInput
+		var addAnother bool
+
+		paramForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Parameter Name").
+					Description("Enter the parameter's property name (e.g., 'timeout')").
+					Value(&param.Name).
+					Validate(func(s string) error {
+						if strings.TrimSpace(s) == "" {
+							return fmt.Errorf("parameter name is required")
+						}
+						return nil
+					}),
+
+				huh.NewSelect[string]().
+					Title("Parameter Type").
+					Options(huh.NewOptions(parameterTypes...)...).
+					Value(&param.Type),
+
+				huh.NewInput().
+					Title("Description").
+					Description("Enter a description for this parameter").
+					Value(&param.Description),
+
+				huh.NewConfirm().
+					Title("Required?").
+					Value(&param.Required),
+
+				huh.NewConfirm().
+					Title("Add Another Parameter?").
+					Value(&addAnother),
+			).Title("Parameter Configuration"),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := paramForm.Run(); err != nil {
+			return err
+		}
+
+		data.Parameters = append(data.Parameters, param)
+
+		if !addAnother {
+			break
+		}
+	}
+
+	return nil
+}
+
+func generateAndSaveContainer(data *ContainerWizardData) error {
+	config := servers.Server{
+		Name:  data.ServerName,
+		Image: data.Image,
+		Type:  "server",
+		Meta: servers.Meta{
+			Category: data.Category,
+			Tags:     []string{data.Category},
+		},
+		About: servers.About{
+			Title:       data.Title,
+			Description: data.Description,
+			Icon:        data.Icon,
+		},
+		Source: servers.Source{
+			Project:    data.Project,
+			Upstream:   data.Upstream,
+			Branch:     data.Branch,
+			Directory:  data.Directory,
+			Dockerfile: data.Dockerfile,
+		},
+	}
+
+	if strings.TrimSpace(data.Command) != "" {
+		config.Run.Command = strings.Fields(data.Command)
+	}
+	config.Run.Volumes = data.Volumes
+	if strings.TrimSpace(data.AllowHosts) != "" {
+		for _, host := range strings.Split(data.AllowHosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				config.Run.AllowHosts = append(config.Run.AllowHosts, host)
+			}
+		}
+	}
+	config.Run.DisableNetwork = data.DisableNetwork
+
+	if len(data.Secrets) > 0 || len(data.EnvVars) > 0 || len(data.Parameters) > 0 {
+		config.Config = servers.Config{
+			Description: fmt.Sprintf("Configure the connection to %s", data.Title),
+		}
+
+		for _, secret := range data.Secrets {
+			required := secret.Required
+			config.Config.Secrets = append(config.Config.Secrets, servers.Secret{
+				Name:     fmt.Sprintf("%s.%s", data.ServerName, secret.Name),
+				Env:      secret.EnvName,
+				Example:  secret.Example,
+				Required: &required,
+			})
+		}
+
+		for _, envVar := range data.EnvVars {
+			config.Config.Env = append(config.Config.Env, servers.Env{
+				Name:    envVar.Name,
+				Example: envVar.Example,
+				Value:   envVar.Value,
+			})
+		}
+
+		if len(data.Parameters) > 0 {
+			schema := servers.Schema{
+				Type:       "object",
+				Properties: make(servers.SchemaList, 0, len(data.Parameters)),
+			}
+			for _, param := range data.Parameters {
+				schema.Properties = append(schema.Properties, servers.SchemaEntry{
+					Name: param.Name,
+					Schema: servers.Schema{
+						Type:        param.Type,
+						Description: param.Description,
+					},
+				})
+				if param.Required {
+					schema.Required = append(schema.Required, param.Name)
+				}
+			}
+			config.Config.Parameters = schema
+		}
+	}
+
+	serverDir := filepath.Join("servers", data.ServerName)
+	if err := os.MkdirAll(serverDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	yamlData, err := yaml.Marshal(&config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	configPath := filepath.Join(serverDir, "server.yaml")
+	if err := os.WriteFile(configPath, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	toolsPath := filepath.Join(serverDir, "tools.json")
+	if err := os.WriteFile(toolsPath, []byte("[]"), 0644); err != nil {
+		return fmt.Errorf("failed to write tools file: %w", err)
+	}
+
+	readmePath := filepath.Join(serverDir, "readme.md")
+	readmeContent := fmt.Sprintf("# %s\n\n%s\n", data.Title, data.Description)
+	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
+		return fmt.Errorf("failed to write readme file: %w", err)
+	}
+
+	return nil
+}
+
 func checkLocalServerExists(name string) (bool, error) {
 	entries, err := os.ReadDir("servers")
 	if err != nil {
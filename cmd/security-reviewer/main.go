@@ -10,10 +10,15 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/docker/mcp-registry/pkg/sandbox"
+	"github.com/docker/mcp-registry/pkg/sarif"
+	"github.com/docker/mcp-registry/pkg/sourcehost"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +29,8 @@ const (
 	reportFileName = "report.md"
 	// labelsFileName is the name of the label output emitted by the agent.
 	labelsFileName = "labels.txt"
+	// sarifFileName is the name of the structured findings output emitted by the agent.
+	sarifFileName = "report.sarif"
 	// repositoryDirName is the working directory used to stage repository clones.
 	repositoryDirName = "repository"
 	// dockerExecutable identifies the docker CLI binary invoked by the tool.
@@ -48,12 +55,43 @@ const (
 	agentNameClaude = "claude"
 	// agentNameCodex identifies the Codex-based reviewer.
 	agentNameCodex = "codex"
+
+	// cloneModeFull performs an ordinary full clone via the sourcehost
+	// resolver, fetching every blob and tree across the repository's
+	// history.
+	cloneModeFull = "full"
+	// cloneModeTreeless clones with --filter=tree:0, deferring tree (and
+	// blob) downloads until something actually needs them.
+	cloneModeTreeless = "treeless"
+	// cloneModeBlobless clones with --filter=blob:none, fetching the full
+	// commit and tree history but only the blobs the checkout touches.
+	cloneModeBlobless = "blobless"
+	// cloneModeShallowRange fetches only the commits reachable from head
+	// but not from base, for a differential review of a large monorepo
+	// where history outside the diff is irrelevant.
+	cloneModeShallowRange = "shallow-range"
+
+	// findingLineWindow is how close two findings' start lines must be (in
+	// either direction) to be treated as the same underlying issue when
+	// reconcileFindings clusters per-agent SARIF results.
+	findingLineWindow = 3
+	// confidenceProperty is the SARIF result property name reconcileFindings
+	// records its agreement confidence under.
+	confidenceProperty = "confidence"
+	// confidenceHigh, confidenceMedium, and confidenceLow are the values
+	// reconcileFindings assigns to confidenceProperty, based on how many
+	// distinct agents reported a finding cluster.
+	confidenceHigh   = "high"
+	confidenceMedium = "medium"
+	confidenceLow    = "low"
 )
 
 // options stores parsed CLI arguments.
 type options struct {
-	// Agent selects the underlying reviewer agent implementation.
-	Agent string
+	// Agents selects the underlying reviewer agent implementations to run.
+	// Each runs concurrently in its own compose project against the same
+	// repository clone; their reports, labels, and findings are merged.
+	Agents []string
 	// Repository is the Git repository URL or filesystem path.
 	Repository string
 	// HeadSHA is the commit under audit.
@@ -66,6 +104,8 @@ type options struct {
 	OutputPath string
 	// LabelsOutput is the destination for the label list produced by the reviewer.
 	LabelsOutput string
+	// SarifOutput is the destination for the structured findings produced by the reviewer.
+	SarifOutput string
 	// Model optionally overrides the reviewer model selection.
 	Model string
 	// ExtraArgs optionally appends raw arguments to the agent CLI.
@@ -74,47 +114,71 @@ type options struct {
 	KeepWorkdir bool
 	// TimeoutSeconds bounds the reviewer runtime; zero uses the default.
 	TimeoutSeconds int
+	// CloneMode selects how prepareRepository fetches the repository: full,
+	// treeless, blobless, or shallow-range. See the cloneMode* constants.
+	CloneMode string
+	// Sandbox selects the REVIEW_SANDBOX mode the reviewer container runs
+	// the agent under: off, strict, or permissive.
+	Sandbox string
 }
 
+// agentFlag is the raw --agent value, a comma-separated list of agent
+// names (e.g. "claude,codex"); it's parsed and validated into
+// options.Agents in RunE.
+var agentFlag = agentNameClaude
+
 var cliOpts = options{
-	Agent:          agentNameClaude,
 	OutputPath:     "security-review.md",
 	TimeoutSeconds: defaultTimeoutSeconds,
+	CloneMode:      cloneModeFull,
+	Sandbox:        string(sandbox.DefaultMode),
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "security-reviewer",
 	Short: "Run the security reviewer compose workflow",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		agent := strings.ToLower(strings.TrimSpace(cliOpts.Agent))
-		if agent == "" {
-			agent = agentNameClaude
-		}
-		if agent != agentNameClaude && agent != agentNameCodex {
-			return fmt.Errorf("invalid agent %q (supported: %s, %s)", cliOpts.Agent, agentNameClaude, agentNameCodex)
+		agents, err := parseAgents(agentFlag)
+		if err != nil {
+			return err
 		}
 
 		labelsOutput := strings.TrimSpace(cliOpts.LabelsOutput)
 		if labelsOutput == "" {
 			labelsOutput = deriveDefaultLabelsPath(cliOpts.OutputPath)
 		}
+		sarifOutput := strings.TrimSpace(cliOpts.SarifOutput)
+		if sarifOutput == "" {
+			sarifOutput = deriveDefaultSarifPath(cliOpts.OutputPath)
+		}
 		timeoutSecs := cliOpts.TimeoutSeconds
 		if timeoutSecs <= 0 {
 			timeoutSecs = defaultTimeoutSeconds
 		}
+		cloneMode, err := parseCloneMode(cliOpts.CloneMode)
+		if err != nil {
+			return err
+		}
+		sandboxMode, err := sandbox.ParseMode(cliOpts.Sandbox)
+		if err != nil {
+			return err
+		}
 
 		opts := options{
-			Agent:          agent,
+			Agents:         agents,
 			Repository:     strings.TrimSpace(cliOpts.Repository),
 			HeadSHA:        strings.TrimSpace(cliOpts.HeadSHA),
 			BaseSHA:        strings.TrimSpace(cliOpts.BaseSHA),
 			TargetLabel:    strings.TrimSpace(cliOpts.TargetLabel),
 			OutputPath:     strings.TrimSpace(cliOpts.OutputPath),
 			LabelsOutput:   labelsOutput,
+			SarifOutput:    sarifOutput,
 			Model:          strings.TrimSpace(cliOpts.Model),
 			ExtraArgs:      strings.TrimSpace(cliOpts.ExtraArgs),
 			KeepWorkdir:    cliOpts.KeepWorkdir,
 			TimeoutSeconds: timeoutSecs,
+			CloneMode:      cloneMode,
+			Sandbox:        string(sandboxMode),
 		}
 
 		if opts.Repository == "" {
@@ -129,17 +193,20 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.Flags().StringVar(&cliOpts.Agent, "agent", cliOpts.Agent, "Reviewer agent to use (claude or codex).")
-	rootCmd.Flags().StringVar(&cliOpts.Repository, "repo", cliOpts.Repository, "Git repository URL or local path to review.")
+	rootCmd.Flags().StringVar(&agentFlag, "agent", agentFlag, "Reviewer agent(s) to use, comma-separated (claude, codex, or both).")
+	rootCmd.Flags().StringVar(&cliOpts.Repository, "repo", cliOpts.Repository, "Git repository URL or local path to review, or a git bundle (\"-\" for stdin, or a path to a named pipe or .bundle file).")
 	rootCmd.Flags().StringVar(&cliOpts.HeadSHA, "head", cliOpts.HeadSHA, "Head commit SHA to review.")
 	rootCmd.Flags().StringVar(&cliOpts.BaseSHA, "base", cliOpts.BaseSHA, "Base commit SHA for differential reviews.")
 	rootCmd.Flags().StringVar(&cliOpts.TargetLabel, "target-label", cliOpts.TargetLabel, "Human readable identifier for the target.")
 	rootCmd.Flags().StringVar(&cliOpts.OutputPath, "output", cliOpts.OutputPath, "Destination for the rendered report.")
 	rootCmd.Flags().StringVar(&cliOpts.LabelsOutput, "labels-output", cliOpts.LabelsOutput, "Destination for the labels file (defaults alongside the report).")
+	rootCmd.Flags().StringVar(&cliOpts.SarifOutput, "sarif-output", cliOpts.SarifOutput, "Destination for the SARIF findings file (defaults alongside the report).")
 	rootCmd.Flags().IntVar(&cliOpts.TimeoutSeconds, "timeout", cliOpts.TimeoutSeconds, "Maximum runtime for the review in seconds (defaults to 3600 seconds).")
 	rootCmd.Flags().StringVar(&cliOpts.Model, "model", cliOpts.Model, "Override the reviewer model for the selected agent.")
 	rootCmd.Flags().StringVar(&cliOpts.ExtraArgs, "extra-args", cliOpts.ExtraArgs, "Additional arguments passed to the reviewer agent.")
 	rootCmd.Flags().BoolVar(&cliOpts.KeepWorkdir, "keep-workdir", cliOpts.KeepWorkdir, "Keep the temporary workspace after completion.")
+	rootCmd.Flags().StringVar(&cliOpts.CloneMode, "clone-mode", cliOpts.CloneMode, "How to fetch the repository: full, treeless, blobless, or shallow-range.")
+	rootCmd.Flags().StringVar(&cliOpts.Sandbox, "sandbox", cliOpts.Sandbox, "Agent sandboxing inside the reviewer container: off, strict, or permissive.")
 
 	_ = rootCmd.MarkFlagRequired("repo")
 	_ = rootCmd.MarkFlagRequired("head")
@@ -155,39 +222,95 @@ func main() {
 	}
 }
 
-// run coordinates workspace preparation, compose execution, and cleanup.
+// parseAgents splits raw (a comma-separated --agent value) into a
+// de-duplicated, order-preserving list of supported agent names.
+func parseAgents(raw string) ([]string, error) {
+	var agents []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		agent := strings.ToLower(strings.TrimSpace(part))
+		if agent == "" {
+			continue
+		}
+		if agent != agentNameClaude && agent != agentNameCodex {
+			return nil, fmt.Errorf("invalid agent %q (supported: %s, %s)", agent, agentNameClaude, agentNameCodex)
+		}
+		if seen[agent] {
+			continue
+		}
+		seen[agent] = true
+		agents = append(agents, agent)
+	}
+	if len(agents) == 0 {
+		agents = []string{agentNameClaude}
+	}
+	return agents, nil
+}
+
+// parseCloneMode validates raw against the supported clone modes, defaulting
+// to cloneModeFull when raw is blank.
+func parseCloneMode(raw string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(raw))
+	if mode == "" {
+		return cloneModeFull, nil
+	}
+	switch mode {
+	case cloneModeFull, cloneModeTreeless, cloneModeBlobless, cloneModeShallowRange:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid clone mode %q (supported: %s, %s, %s, %s)", mode, cloneModeFull, cloneModeTreeless, cloneModeBlobless, cloneModeShallowRange)
+	}
+}
+
+// agentOutcome is one agent's contribution to the consolidated review:
+// either the report/labels/findings it produced, or the error that kept it
+// from producing them.
+type agentOutcome struct {
+	agent  string
+	report string
+	labels []string
+	sarif  sarif.Log
+	err    error
+}
+
+// run coordinates workspace preparation, running every requested agent
+// concurrently against a single shared repository clone, and merging their
+// outputs into the consolidated report, labels, and findings.
 func run(ctx context.Context, opts options) error {
 	if opts.BaseSHA != "" {
 		fmt.Printf(
-			"Starting differential security review (agent=%s head=%s base=%s target=%s)\n",
-			opts.Agent,
+			"Starting differential security review (agents=%s head=%s base=%s target=%s)\n",
+			strings.Join(opts.Agents, ","),
 			opts.HeadSHA,
 			opts.BaseSHA,
 			opts.TargetLabel,
 		)
 	} else {
 		fmt.Printf(
-			"Starting full security review (agent=%s head=%s target=%s)\n",
-			opts.Agent,
+			"Starting full security review (agents=%s head=%s target=%s)\n",
+			strings.Join(opts.Agents, ","),
 			opts.HeadSHA,
 			opts.TargetLabel,
 		)
 	}
 
-	// Ensure the credential proxy has the API keys it needs before staging work.
-	switch opts.Agent {
-	case "claude":
-		if _, ok := os.LookupEnv(envAnthropicAPIKey); !ok {
-			return errors.New("ANTHROPIC_API_KEY environment variable is required for the Claude agent")
-		}
-	case "codex":
-		if _, ok := os.LookupEnv(envOpenAIAPIKey); !ok {
-			return errors.New("OPENAI_API_KEY environment variable is required for the Codex agent")
+	// Ensure the credential proxy has the API keys every requested agent
+	// needs before staging any work.
+	for _, agent := range opts.Agents {
+		switch agent {
+		case agentNameClaude:
+			if _, ok := os.LookupEnv(envAnthropicAPIKey); !ok {
+				return errors.New("ANTHROPIC_API_KEY environment variable is required for the Claude agent")
+			}
+		case agentNameCodex:
+			if _, ok := os.LookupEnv(envOpenAIAPIKey); !ok {
+				return errors.New("OPENAI_API_KEY environment variable is required for the Codex agent")
+			}
 		}
 	}
 
 	// Prepare a temporary workspace to stage inputs and outputs.
-	workdir, err := os.MkdirTemp("", fmt.Sprintf("security-reviewer-%s-", opts.Agent))
+	workdir, err := os.MkdirTemp("", fmt.Sprintf("security-reviewer-%s-", strings.Join(opts.Agents, "-")))
 	if err != nil {
 		return fmt.Errorf("create temporary directory: %w", err)
 	}
@@ -198,44 +321,359 @@ func run(ctx context.Context, opts options) error {
 		fmt.Printf("Temporary workspace preserved at %s\n", workdir)
 	}
 
-	// Materialize the repository commits required for the review.
+	// Materialize the repository commits required for the review once; every
+	// agent's compose project mounts this same clone read-only.
 	repositoryDir := filepath.Join(workdir, repositoryDirName)
 	if err = prepareRepository(ctx, opts, repositoryDir); err != nil {
 		return err
 	}
 
-	outputDir := filepath.Join(workdir, "output")
-	if err = os.MkdirAll(outputDir, 0o755); err != nil {
-		return fmt.Errorf("create output directory: %w", err)
+	outcomes := make([]agentOutcome, len(opts.Agents))
+	var wg sync.WaitGroup
+	for i, agent := range opts.Agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			defer wg.Done()
+			outcomes[i] = runAgent(ctx, opts, agent, workdir, repositoryDir)
+		}(i, agent)
 	}
+	wg.Wait()
 
-	// Launch the compose project and wait for the reviewer to finish.
-	if err = runCompose(ctx, opts, workdir, repositoryDir, outputDir); err != nil {
+	successful, err := collectOutcomes(outcomes)
+	if err != nil {
 		return err
 	}
 
-	// Copy the generated artifacts back to the requested destinations.
+	if err = writeConsolidatedReport(opts.OutputPath, successful, len(opts.Agents)); err != nil {
+		return err
+	}
+	if err = writeConsolidatedLabels(opts.LabelsOutput, successful); err != nil {
+		return err
+	}
+	if err = writeConsolidatedSarif(opts.SarifOutput, successful, len(opts.Agents)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Security review report written to %s\n", opts.OutputPath)
+	fmt.Printf("Security review labels written to %s\n", opts.LabelsOutput)
+	fmt.Printf("Security review findings written to %s\n", opts.SarifOutput)
+	return nil
+}
+
+// runAgent runs a single agent's compose project to completion in its own
+// workspace under workdir, reading back the report/labels/findings it
+// produced.
+func runAgent(ctx context.Context, opts options, agent, workdir, repositoryDir string) agentOutcome {
+	outcome := agentOutcome{agent: agent}
+
+	outputDir := filepath.Join(workdir, "output-"+agent)
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		outcome.err = fmt.Errorf("create output directory: %w", err)
+		return outcome
+	}
+
+	composeDir := filepath.Join(workdir, "compose-"+agent)
+	env := buildComposeEnv(opts, agent, repositoryDir, outputDir)
+	if err := runCompose(ctx, composeDir, env); err != nil {
+		outcome.err = err
+		return outcome
+	}
+
 	reportPath := filepath.Join(outputDir, reportFileName)
 	labelsPath := filepath.Join(outputDir, labelsFileName)
-	if _, err = os.Stat(reportPath); err != nil {
-		return fmt.Errorf("review report not produced: %w", err)
+	sarifPath := filepath.Join(outputDir, sarifFileName)
+
+	report, err := os.ReadFile(reportPath)
+	if err != nil {
+		outcome.err = fmt.Errorf("review report not produced: %w", err)
+		return outcome
 	}
-	if _, err = os.Stat(labelsPath); err != nil {
-		return fmt.Errorf("labels file not produced: %w", err)
+	outcome.report = string(report)
+
+	labels, err := readLabels(labelsPath)
+	if err != nil {
+		outcome.err = fmt.Errorf("labels file not produced: %w", err)
+		return outcome
 	}
+	outcome.labels = labels
 
-	if err = copyFile(reportPath, opts.OutputPath); err != nil {
-		return err
+	log, err := sarif.ReadFile(sarifPath)
+	if err != nil {
+		outcome.err = fmt.Errorf("findings file not produced: %w", err)
+		return outcome
 	}
-	if err = copyFile(labelsPath, opts.LabelsOutput); err != nil {
-		return err
+	outcome.sarif = log
+
+	return outcome
+}
+
+// collectOutcomes separates the agents that produced a usable result from
+// those that errored, warning about (rather than aborting the run for) an
+// agent's failure as long as at least one other agent still succeeded. It
+// only returns an error when every agent failed, since there is nothing left
+// to consolidate.
+func collectOutcomes(outcomes []agentOutcome) ([]agentOutcome, error) {
+	var successful []agentOutcome
+	var failures []error
+	for _, outcome := range outcomes {
+		if outcome.err != nil {
+			failures = append(failures, fmt.Errorf("agent %s: %w", outcome.agent, outcome.err))
+			continue
+		}
+		successful = append(successful, outcome)
+	}
+
+	if len(successful) == 0 {
+		return nil, errors.Join(failures...)
+	}
+	for _, failure := range failures {
+		fmt.Printf("warning: %v\n", failure)
+	}
+	return successful, nil
+}
+
+// readLabels reads a labels.txt file into its non-blank, trimmed lines.
+func readLabels(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Security review report copied to %s\n", opts.OutputPath)
-	fmt.Printf("Security review labels copied to %s\n", opts.LabelsOutput)
+	var labels []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			labels = append(labels, line)
+		}
+	}
+	return labels, nil
+}
+
+// agentTitle renders an agent name (e.g. "claude") for use in a report
+// section heading (e.g. "Claude").
+func agentTitle(agent string) string {
+	if agent == "" {
+		return agent
+	}
+	return strings.ToUpper(agent[:1]) + agent[1:]
+}
+
+// writeConsolidatedReport merges every agent's report.md into one document:
+// a reconciled findings table ranking each finding by how many of
+// agentCount agents agree on it, followed by a per-agent section
+// preserving opts.Agents order.
+func writeConsolidatedReport(path string, outcomes []agentOutcome, agentCount int) error {
+	var sb strings.Builder
+	sb.WriteString("# Security Review\n\n")
+	sb.WriteString(renderReconciledFindings(reconcileFindings(outcomes, agentCount)))
+	for _, outcome := range outcomes {
+		fmt.Fprintf(&sb, "## %s\n\n", agentTitle(outcome.agent))
+		sb.WriteString(strings.TrimRight(outcome.report, "\n"))
+		sb.WriteString("\n\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", path, err)
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
+
+// findingCluster groups one or more agents' SARIF results that describe the
+// same underlying issue: equal ruleId and artifact URI, with start lines
+// within findingLineWindow lines of each other.
+type findingCluster struct {
+	result     sarif.Result
+	agents     []string
+	confidence string
+}
+
+// reconcileFindings groups every outcome's SARIF results into
+// findingClusters and assigns each a confidence level based on how many of
+// agentCount requested agents agree: high at or above a majority
+// (ceil(agentCount/2)), medium for exactly two agents, low for a finding
+// only one agent reported.
+func reconcileFindings(outcomes []agentOutcome, agentCount int) []findingCluster {
+	var clusters []findingCluster
+	for _, outcome := range outcomes {
+		for _, run := range outcome.sarif.Runs {
+			for _, result := range run.Results {
+				cluster := findClusterFor(clusters, result)
+				if cluster == nil {
+					clusters = append(clusters, findingCluster{result: result})
+					cluster = &clusters[len(clusters)-1]
+				}
+				if !containsString(cluster.agents, outcome.agent) {
+					cluster.agents = append(cluster.agents, outcome.agent)
+				}
+			}
+		}
+	}
+
+	majority := (agentCount + 1) / 2
+	for i := range clusters {
+		switch agreeing := len(clusters[i].agents); {
+		case agreeing >= majority:
+			clusters[i].confidence = confidenceHigh
+		case agreeing == 2:
+			clusters[i].confidence = confidenceMedium
+		default:
+			clusters[i].confidence = confidenceLow
+		}
+	}
+	return clusters
+}
+
+// findClusterFor returns the cluster result belongs in - same ruleId and
+// artifact URI, with a start line within findingLineWindow of the cluster's
+// representative finding - or nil if none matches yet.
+func findClusterFor(clusters []findingCluster, result sarif.Result) *findingCluster {
+	location := resultLocation(result)
+	for i := range clusters {
+		existing := resultLocation(clusters[i].result)
+		if existing.ruleID != location.ruleID || existing.uri != location.uri {
+			continue
+		}
+		if absInt(existing.startLine-location.startLine) <= findingLineWindow {
+			return &clusters[i]
+		}
+	}
 	return nil
 }
 
+// resultKey is the subset of a SARIF result that reconciliation matches on.
+type resultKey struct {
+	ruleID    string
+	uri       string
+	startLine int
+}
+
+// resultLocation extracts result's reconciliation key.
+func resultLocation(result sarif.Result) resultKey {
+	key := resultKey{ruleID: result.RuleID}
+	if len(result.Locations) > 0 {
+		key.uri = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+		key.startLine = result.Locations[0].PhysicalLocation.Region.StartLine
+	}
+	return key
+}
+
+// containsString reports whether value is present in values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// absInt returns the absolute value of n.
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// findingConfidenceRank orders confidence tiers from strongest to weakest,
+// for renderReconciledFindings's sort.
+var findingConfidenceRank = map[string]int{
+	confidenceHigh:   0,
+	confidenceMedium: 1,
+	confidenceLow:    2,
+}
+
+// renderReconciledFindings renders clusters as a markdown table, sorted by
+// confidence (high first), so the findings multiple agents agree on surface
+// above the ones only a single agent reported.
+func renderReconciledFindings(clusters []findingCluster) string {
+	var sb strings.Builder
+	sb.WriteString("## Reconciled Findings\n\n")
+	if len(clusters) == 0 {
+		sb.WriteString("No findings reported.\n\n")
+		return sb.String()
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return findingConfidenceRank[clusters[i].confidence] < findingConfidenceRank[clusters[j].confidence]
+	})
+
+	sb.WriteString("| Confidence | Rule | Location | Agents | Message |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, cluster := range clusters {
+		location := resultLocation(cluster.result)
+		fmt.Fprintf(&sb, "| %s | %s | %s:%d | %s | %s |\n",
+			cluster.confidence,
+			cluster.result.RuleID,
+			location.uri,
+			location.startLine,
+			strings.Join(cluster.agents, ", "),
+			strings.ReplaceAll(cluster.result.Message.Text, "|", "\\|"),
+		)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// writeConsolidatedLabels merges every agent's labels into a de-duplicated
+// union, preserving first-seen order.
+func writeConsolidatedLabels(path string, outcomes []agentOutcome) error {
+	var labels []string
+	seen := map[string]bool{}
+	for _, outcome := range outcomes {
+		for _, label := range outcome.labels {
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", path, err)
+	}
+	var content string
+	if len(labels) > 0 {
+		content = strings.Join(labels, "\n") + "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// writeConsolidatedSarif merges every agent's findings into a single log,
+// one run per agent so each finding stays attributed to the agent that
+// reported it, with every result's confidenceProperty set to its
+// reconciliation confidence across all agentCount agents.
+func writeConsolidatedSarif(path string, outcomes []agentOutcome, agentCount int) error {
+	clusters := reconcileFindings(outcomes, agentCount)
+
+	merged := sarif.Log{Schema: outcomes[0].sarif.Schema, Version: outcomes[0].sarif.Version}
+	for _, outcome := range outcomes {
+		for _, run := range outcome.sarif.Runs {
+			run.Tool.Driver.Name = outcome.agent
+			run.Results = annotateConfidence(run.Results, clusters)
+			merged.Runs = append(merged.Runs, run)
+		}
+	}
+	return sarif.WriteFile(path, merged)
+}
+
+// annotateConfidence returns results with each one's confidenceProperty set
+// to the reconciliation confidence of the cluster it belongs to.
+func annotateConfidence(results []sarif.Result, clusters []findingCluster) []sarif.Result {
+	annotated := make([]sarif.Result, len(results))
+	for i, result := range results {
+		annotated[i] = result
+		cluster := findClusterFor(clusters, result)
+		if cluster == nil {
+			continue
+		}
+		if annotated[i].Properties == nil {
+			annotated[i].Properties = map[string]string{}
+		}
+		annotated[i].Properties[confidenceProperty] = cluster.confidence
+	}
+	return annotated
+}
+
 // deriveDefaultLabelsPath produces a labels output path near the report path.
 func deriveDefaultLabelsPath(reportPath string) string {
 	reportPath = strings.TrimSpace(reportPath)
@@ -258,6 +696,28 @@ func deriveDefaultLabelsPath(reportPath string) string {
 	return filepath.Join(dir, base+"-labels.txt")
 }
 
+// deriveDefaultSarifPath produces a findings output path near the report path.
+func deriveDefaultSarifPath(reportPath string) string {
+	reportPath = strings.TrimSpace(reportPath)
+	if reportPath == "" {
+		// Without an explicit report, fall back to a stable default name.
+		return "security-review-findings.sarif"
+	}
+	// Place the findings file alongside the report for easier discovery.
+	dir := filepath.Dir(reportPath)
+	base := filepath.Base(reportPath)
+	idx := strings.LastIndex(base, ".")
+	if idx > 0 {
+		// Drop the extension so the generated findings file mirrors the report name.
+		base = base[:idx]
+	}
+	if strings.TrimSpace(base) == "" {
+		base = "security-review"
+	}
+	// Append a suffix to distinguish the findings artifact from the report.
+	return filepath.Join(dir, base+"-findings.sarif")
+}
+
 // sanitizeName converts arbitrary text into a slug.
 func sanitizeName(text string) string {
 	lower := strings.ToLower(text)
@@ -270,7 +730,17 @@ func sanitizeName(text string) string {
 	return trimmed
 }
 
-// prepareRepository clones the repository and materializes commits for review.
+// prepareRepository clones the repository and materializes commits for
+// review. In cloneModeFull (the default) it selects a source-host backend
+// by opts.Repository's URL so GitLab, Bitbucket, and arbitrary git remotes
+// can be audited the same way as GitHub, with that backend's own
+// credentials. The other clone modes trade that per-host authentication for
+// a much smaller fetch on large monorepos, so they talk to opts.Repository
+// directly with the git CLI; see cloneTreeless, cloneBlobless, and
+// cloneShallowRange. opts.Repository may also name a git bundle: "-" or a
+// named pipe/character device is first spooled to a temp file (git can't
+// clone from a live stream), and a regular ".bundle" file is cloned from
+// directly; see bundleSource.
 func prepareRepository(ctx context.Context, opts options, repositoryDir string) error {
 	parentDir := filepath.Dir(repositoryDir)
 	if err := os.MkdirAll(parentDir, 0o755); err != nil {
@@ -280,15 +750,40 @@ func prepareRepository(ctx context.Context, opts options, repositoryDir string)
 		return fmt.Errorf("reset repository directory: %w", err)
 	}
 
-	if err := runCommand(ctx, "", gitExecutable, "clone", opts.Repository, repositoryDir); err != nil {
-		return fmt.Errorf("clone repository: %w", err)
+	bundle, err := bundleSource(opts.Repository, parentDir)
+	if err != nil {
+		return fmt.Errorf("materialize repository bundle: %w", err)
 	}
-
-	if err := ensureCommit(ctx, repositoryDir, opts.HeadSHA); err != nil {
-		return err
+	if bundle != "" {
+		if err := runCommand(ctx, "", gitExecutable, "clone", bundle, repositoryDir); err != nil {
+			return fmt.Errorf("clone repository bundle: %w", err)
+		}
+		if opts.BaseSHA != "" {
+			if err := ensureCommit(ctx, repositoryDir, opts.BaseSHA); err != nil {
+				return err
+			}
+		}
+		return runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA)
 	}
-	if err := runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA); err != nil {
-		return fmt.Errorf("checkout head commit: %w", err)
+
+	switch opts.CloneMode {
+	case cloneModeTreeless:
+		if err := cloneTreeless(ctx, opts, repositoryDir); err != nil {
+			return err
+		}
+	case cloneModeBlobless:
+		if err := cloneBlobless(ctx, opts, repositoryDir); err != nil {
+			return err
+		}
+	case cloneModeShallowRange:
+		if err := cloneShallowRange(ctx, opts, repositoryDir); err != nil {
+			return err
+		}
+	default:
+		resolver := sourcehost.For(opts.Repository)
+		if err := resolver.FetchTree(ctx, opts.Repository, opts.HeadSHA, repositoryDir); err != nil {
+			return fmt.Errorf("clone repository: %w", err)
+		}
 	}
 
 	if opts.BaseSHA != "" {
@@ -300,6 +795,76 @@ func prepareRepository(ctx context.Context, opts options, repositoryDir string)
 	return nil
 }
 
+// cloneTreeless clones opts.Repository with --filter=tree:0 --no-checkout,
+// deferring every tree and blob download until something actually needs
+// it, then fetches opts.HeadSHA (and opts.BaseSHA, if set) so they're
+// available for checkout and diffing.
+func cloneTreeless(ctx context.Context, opts options, repositoryDir string) error {
+	if err := runCommand(ctx, "", gitExecutable, "clone", "--filter=tree:0", "--no-checkout", opts.Repository, repositoryDir); err != nil {
+		return fmt.Errorf("treeless clone: %w", err)
+	}
+	if err := fetchRefs(ctx, repositoryDir, opts.HeadSHA, opts.BaseSHA); err != nil {
+		return err
+	}
+	return runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA)
+}
+
+// cloneBlobless clones opts.Repository with --filter=blob:none, fetching
+// the full commit and tree history but only the blobs the checkout
+// actually touches.
+func cloneBlobless(ctx context.Context, opts options, repositoryDir string) error {
+	if err := runCommand(ctx, "", gitExecutable, "clone", "--filter=blob:none", "--no-checkout", opts.Repository, repositoryDir); err != nil {
+		return fmt.Errorf("blobless clone: %w", err)
+	}
+	if err := fetchRefs(ctx, repositoryDir, opts.HeadSHA, opts.BaseSHA); err != nil {
+		return err
+	}
+	return runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA)
+}
+
+// cloneShallowRange initializes an empty repository and fetches only the
+// commits reachable from opts.HeadSHA but not from opts.BaseSHA, so a
+// differential review of a large monorepo doesn't pay for history outside
+// the diff. It falls back to a plain shallow --depth=1 fetch of just head
+// when opts.BaseSHA is empty, since there's no base to exclude against.
+func cloneShallowRange(ctx context.Context, opts options, repositoryDir string) error {
+	if err := runCommand(ctx, "", gitExecutable, "init", repositoryDir); err != nil {
+		return fmt.Errorf("init repository: %w", err)
+	}
+	if err := runCommand(ctx, repositoryDir, gitExecutable, "remote", "add", "origin", opts.Repository); err != nil {
+		return fmt.Errorf("add origin: %w", err)
+	}
+
+	if opts.BaseSHA == "" {
+		if err := runCommand(ctx, repositoryDir, gitExecutable, "fetch", "--depth=1", "origin", opts.HeadSHA); err != nil {
+			return fmt.Errorf("shallow fetch %s: %w", opts.HeadSHA, err)
+		}
+		return runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA)
+	}
+
+	if err := runCommand(ctx, repositoryDir, gitExecutable, "fetch", "--depth=1", "origin", opts.HeadSHA, opts.BaseSHA); err != nil {
+		return fmt.Errorf("shallow fetch %s %s: %w", opts.HeadSHA, opts.BaseSHA, err)
+	}
+	if err := runCommand(ctx, repositoryDir, gitExecutable, "fetch", fmt.Sprintf("--shallow-exclude=%s", opts.BaseSHA), "origin", opts.HeadSHA); err != nil {
+		return fmt.Errorf("shallow-exclude fetch %s: %w", opts.HeadSHA, err)
+	}
+	return runCommand(ctx, repositoryDir, gitExecutable, "checkout", "--detach", opts.HeadSHA)
+}
+
+// fetchRefs fetches head (and base, if set) from origin into repositoryDir,
+// for clone modes that start from --no-checkout so nothing is fetched by
+// the initial clone.
+func fetchRefs(ctx context.Context, repositoryDir, head, base string) error {
+	args := []string{"fetch", "origin", head}
+	if base != "" {
+		args = append(args, base)
+	}
+	if err := runCommand(ctx, repositoryDir, gitExecutable, args...); err != nil {
+		return fmt.Errorf("fetch %s: %w", head, err)
+	}
+	return nil
+}
+
 // ensureCommit verifies that a commit exists locally, fetching if needed.
 func ensureCommit(ctx context.Context, repoDir, sha string) error {
 	if sha == "" {
@@ -317,36 +882,66 @@ func ensureCommit(ctx context.Context, repoDir, sha string) error {
 	return nil
 }
 
-// copyFile copies a file from src to dst, creating parent directories.
-func copyFile(src, dst string) error {
-	in, err := os.Open(src)
+// bundleSource returns the path git should clone from when repository names
+// a git bundle rather than a remote URL or a plain checkout path, or "" if
+// repository should be handled the normal way. "-" and any named
+// pipe/character device (e.g. "/dev/stdin") are spooled into a temp file
+// under workDir first, since git can't clone from a live stream; a regular
+// ".bundle" file is returned unchanged, since git can clone from it
+// directly.
+func bundleSource(repository, workDir string) (string, error) {
+	if repository == "-" {
+		return spoolToTempFile(os.Stdin, workDir)
+	}
+
+	info, err := os.Stat(repository)
 	if err != nil {
-		return fmt.Errorf("open file %s: %w", src, err)
+		// Not a local path at all (e.g. a remote URL) - handled normally.
+		return "", nil
 	}
-	defer in.Close()
-	if err = os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-		return fmt.Errorf("create directory for %s: %w", dst, err)
+
+	if info.Mode()&(os.ModeNamedPipe|os.ModeCharDevice) != 0 {
+		f, err := os.Open(repository)
+		if err != nil {
+			return "", fmt.Errorf("open %s: %w", repository, err)
+		}
+		defer f.Close()
+		return spoolToTempFile(f, workDir)
 	}
-	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+
+	if !info.IsDir() && strings.HasSuffix(repository, ".bundle") {
+		return repository, nil
+	}
+
+	return "", nil
+}
+
+// spoolToTempFile buffers r to a temp file under workDir, since a git
+// bundle must be a seekable file on disk before `git clone` can read it.
+func spoolToTempFile(r io.Reader, workDir string) (string, error) {
+	f, err := os.CreateTemp(workDir, "repository-*.bundle")
 	if err != nil {
-		return fmt.Errorf("open destination %s: %w", dst, err)
+		return "", fmt.Errorf("create temp bundle file: %w", err)
 	}
-	defer out.Close()
-	if _, err = io.Copy(out, in); err != nil {
-		return fmt.Errorf("copy %s to %s: %w", src, dst, err)
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("spool repository bundle: %w", err)
 	}
-	return nil
+	return f.Name(), nil
 }
 
-// runCompose executes the docker compose workflow for the review.
-func runCompose(ctx context.Context, opts options, workdir, repositoryDir, outputDir string) error {
-	// Compose assumes relative paths, so stage a copy inside the temp workspace.
-	composeDir := filepath.Join(workdir, composeRelativePath)
+// runCompose executes the docker compose workflow for one agent's review,
+// staging its own copy of the compose project at composeDir so concurrent
+// agents don't collide.
+func runCompose(ctx context.Context, composeDir string, env []string) error {
 	if err := copyDir(composeRelativePath, composeDir); err != nil {
 		return err
 	}
 
-	env := buildComposeEnv(opts, repositoryDir, outputDir)
+	projectName := envValue(env, "COMPOSE_PROJECT_NAME")
+	image := fmt.Sprintf("%s-%s", projectName, agentService)
+
 	up := exec.CommandContext(ctx, dockerExecutable, "compose", "-f", composeFileName, "up", "--build", "--abort-on-container-exit", "--exit-code-from", agentService)
 	up.Dir = composeDir
 	up.Env = env
@@ -357,18 +952,43 @@ func runCompose(ctx context.Context, opts options, workdir, repositoryDir, outpu
 	down.Dir = composeDir
 	down.Env = env
 
+	// Always tear down, even when up failed, so a failed run doesn't leak
+	// the project's containers; join both errors rather than discarding
+	// the teardown failure, so operators can tell a leaked container or
+	// dangling image apart from the primary failure.
+	var upErr, downErr error
 	if err := up.Run(); err != nil {
-		_ = down.Run()
-		return fmt.Errorf("docker compose up: %w", err)
+		upErr = fmt.Errorf("docker compose up (project %s, image %s): %w", projectName, image, err)
 	}
 	if err := down.Run(); err != nil {
-		return fmt.Errorf("docker compose down: %w", err)
+		downErr = fmt.Errorf("docker compose down (project %s, image %s): %w", projectName, image, err)
 	}
-	return nil
+	return errors.Join(upErr, downErr)
+}
+
+// envValue returns the value of key within env (a KEY=VALUE slice, as
+// produced by os.Environ()), or "" if key isn't present.
+func envValue(env []string, key string) string {
+	prefix := key + "="
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, prefix); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// getCurrentUIDGID returns the host process's UID/GID, so containers started
+// via docker compose can bind-mount reviewer output as the invoking user
+// instead of root.
+func getCurrentUIDGID() (int, int) {
+	return os.Getuid(), os.Getgid()
 }
 
-// buildComposeEnv prepares environment variables for docker compose.
-func buildComposeEnv(opts options, repositoryDir, outputDir string) []string {
+// buildComposeEnv prepares environment variables for docker compose for a
+// single agent's run. The compose project name always includes agent so
+// two agents reviewing the same target concurrently never collide.
+func buildComposeEnv(opts options, agent, repositoryDir, outputDir string) []string {
 	env := os.Environ()
 	// Generate a stable slug to keep compose project names readable.
 	slug := sanitizeName(opts.TargetLabel)
@@ -379,7 +999,7 @@ func buildComposeEnv(opts options, repositoryDir, outputDir string) []string {
 	if slug == "" {
 		slug = "target"
 	}
-	projectName := fmt.Sprintf("%s-%s-%d", projectPrefix, slug, time.Now().Unix())
+	projectName := fmt.Sprintf("%s-%s-%s-%d", projectPrefix, slug, agent, time.Now().Unix())
 
 	// Get current UID/GID to match container user with host user.
 	// This avoids permission issues with bind mounts.
@@ -387,19 +1007,20 @@ func buildComposeEnv(opts options, repositoryDir, outputDir string) []string {
 
 	env = append(env,
 		fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", projectName),
-		fmt.Sprintf("REVIEW_AGENT=%s", opts.Agent),
+		fmt.Sprintf("REVIEW_AGENT=%s", agent),
 		fmt.Sprintf("REVIEW_HEAD_SHA=%s", opts.HeadSHA),
 		fmt.Sprintf("REVIEW_BASE_SHA=%s", opts.BaseSHA),
 		fmt.Sprintf("REVIEW_TARGET_LABEL=%s", opts.TargetLabel),
 		fmt.Sprintf("REVIEW_REPOSITORY_PATH=%s", repositoryDir),
 		fmt.Sprintf("REVIEW_OUTPUT_PATH=%s", outputDir),
 		fmt.Sprintf("REVIEW_TIMEOUT_SECS=%d", opts.TimeoutSeconds),
+		fmt.Sprintf("REVIEW_SANDBOX=%s", opts.Sandbox),
 		fmt.Sprintf("AGENT_UID=%d", uid),
 		fmt.Sprintf("AGENT_GID=%d", gid),
 	)
 	if opts.Model != "" {
 		// Route custom models to the right environment variable per agent.
-		switch strings.ToLower(opts.Agent) {
+		switch agent {
 		case agentNameClaude:
 			env = append(env, fmt.Sprintf("CLAUDE_REVIEW_MODEL=%s", opts.Model))
 		case agentNameCodex:
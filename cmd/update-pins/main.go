@@ -24,20 +24,54 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/mcp-registry/pkg/github"
 	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/docker/mcp-registry/pkg/servers/edit"
+	"github.com/docker/mcp-registry/pkg/sourcehost"
+	"github.com/docker/mcp-registry/pkg/trust"
 )
 
+// pin records the commit transition a single server.yaml edit represents.
+// tag is set alongside latest when the server tracks a release/tag
+// strategy rather than a branch head.
+type pin struct {
+	name     string
+	existing string
+	latest   string
+	tag      string
+}
+
+// result is what a single server's refresh produces for main to fold into
+// the shared transaction and summary, once serialized off the worker pool.
+type result struct {
+	serverPath string
+	pin        pin
+	rejected   string
+	failed     string
+	skip       bool
+}
+
 // main orchestrates the pin refresh process, updating server definitions when
 // upstream branches advance.
 func main() {
+	jobs := flag.Int("jobs", 1, "Number of servers to refresh concurrently.")
+	resume := flag.Bool("resume", false, "Resume an interrupted refresh, skipping servers the cache already confirmed this run.")
+	flag.Parse()
+
+	if *jobs < 1 {
+		fmt.Fprintln(os.Stderr, "-jobs must be at least 1")
+		os.Exit(1)
+	}
+
 	ctx := context.Background()
 
 	// Enumerate the server directories that contain YAML definitions.
@@ -47,141 +81,304 @@ func main() {
 		os.Exit(1)
 	}
 
-	var updated []string
+	cache, err := github.LoadRefreshCache(github.RefreshCacheFileName, *resume)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading refresh cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	tx := edit.NewTransaction()
+	pins := make(map[string]pin)
+	var rejected, failed []string
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, *jobs)
+	var wg sync.WaitGroup
 	for _, entry := range entries {
 		// Ignore any files that are not server directories.
 		if !entry.IsDir() {
 			continue
 		}
 
-		serverPath := filepath.Join("servers", entry.Name(), "server.yaml")
-		server, err := servers.Read(serverPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "reading %s: %v\n", serverPath, err)
-			continue
-		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(entry os.DirEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r := refreshServer(ctx, entry, cache)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.skip:
+				return
+			case r.failed != "":
+				failed = append(failed, r.failed)
+			case r.rejected != "":
+				rejected = append(rejected, r.rejected)
+			case r.pin.name != "":
+				if err := tx.Edit(r.serverPath, func(doc *edit.Document) error {
+					if err := doc.SetCommit(r.pin.latest); err != nil {
+						return err
+					}
+					if r.pin.tag != "" {
+						return doc.SetTag(r.pin.tag)
+					}
+					return nil
+				}); err != nil {
+					fmt.Fprintf(os.Stderr, "updating %s: %v\n", r.serverPath, err)
+					failed = append(failed, r.pin.name)
+					return
+				}
+				pins[r.serverPath] = r.pin
+			}
+		}(entry)
+	}
+	wg.Wait()
 
-		if server.Type != "server" {
-			continue
-		}
+	if err := cache.Finish(); err != nil {
+		fmt.Fprintf(os.Stderr, "saving refresh cache: %v\n", err)
+	}
 
-		if !strings.HasPrefix(server.Image, "mcp/") {
-			continue
-		}
+	changedPaths, err := tx.Commit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "writing server definitions: %v\n", err)
+		os.Exit(1)
+	}
 
-		if server.Source.Project == "" {
-			continue
+	var updated []string
+	var moved, reformatted int
+	for _, path := range changedPaths {
+		p := pins[path]
+		if p.existing != p.latest {
+			moved++
+			if p.tag != "" {
+				fmt.Printf("Updated %s: %s -> %s (%s)\n", p.name, p.existing, p.latest, p.tag)
+			} else {
+				fmt.Printf("Updated %s: %s -> %s\n", p.name, p.existing, p.latest)
+			}
+		} else {
+			reformatted++
+			fmt.Printf("Reformatted pinned commit for %s at %s\n", p.name, p.latest)
 		}
+		updated = append(updated, p.name)
+	}
 
-		// Only GitHub repositories are supported by the current workflow.
-		if !strings.Contains(server.Source.Project, "github.com/") {
-			fmt.Printf("Skipping %s: project is not hosted on GitHub.\n", server.Name)
-			continue
-		}
+	if len(updated) == 0 {
+		fmt.Println("No commit updates required.")
+	} else {
+		sort.Strings(updated)
+		fmt.Println("Servers with updated pins:", strings.Join(updated, ", "))
+	}
 
-		// Unpinned servers have to undergo a separate security audit first.
-		existing := strings.ToLower(server.Source.Commit)
-		if existing == "" {
-			fmt.Printf("Skipping %s: no pinned commit present.\n", server.Name)
-			continue
+	printSummary(ctx, cache, moved, reformatted, len(failed))
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		fmt.Fprintln(os.Stderr, "Servers that failed to refresh:", strings.Join(failed, ", "))
+	}
+
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		fmt.Fprintln(os.Stderr, "Servers with an untrusted pin update:", strings.Join(rejected, ", "))
+		os.Exit(1)
+	}
+
+	if len(failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// refreshServer resolves and, if required, verifies a single server's pin,
+// returning its outcome for main to apply under its own lock. It performs
+// no shared-state mutation itself, so it's safe to run from any number of
+// concurrent workers.
+func refreshServer(ctx context.Context, entry os.DirEntry, cache *github.RefreshCache) result {
+	serverPath := filepath.Join("servers", entry.Name(), "server.yaml")
+	server, err := servers.Read(serverPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reading %s: %v\n", serverPath, err)
+		return result{skip: true}
+	}
+
+	if server.Type != "server" {
+		return result{skip: true}
+	}
+
+	if !strings.HasPrefix(server.Image, "mcp/") {
+		return result{skip: true}
+	}
+
+	if server.Source.Project == "" {
+		return result{skip: true}
+	}
+
+	// Unpinned servers have to undergo a separate security audit first.
+	existing := strings.ToLower(server.Source.Commit)
+	if existing == "" {
+		fmt.Printf("Skipping %s: no pinned commit present.\n", server.Name)
+		return result{skip: true}
+	}
+
+	var ref, latest, tag string
+	if server.Source.Track != "" {
+		// Release/tag tracking relies on the GitHub releases/tags API (see
+		// pkg/github.ResolveTrackedCommit), so it's only available for
+		// GitHub-hosted sources for now.
+		if !strings.Contains(server.Source.Project, "github.com/") {
+			fmt.Fprintf(os.Stderr, "Skipping %s: track is only supported for GitHub-hosted sources\n", server.Name)
+			return result{skip: true}
 		}
 
-		// Resolve the current branch head for comparison.
-		branch := server.GetBranch()
 		client := github.NewFromServer(server)
+		var err error
+		tag, latest, err = client.ResolveTrackedCommit(ctx, server.Source.Project, server.Source.Track)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "resolving tracked commit for %s: %v\n", server.Name, err)
+			return result{failed: server.Name}
+		}
+		ref = tag
+	} else {
+		ref = server.GetBranch()
 
-		latest, err := client.GetCommitSHA1(ctx, server.Source.Project, branch)
+		var err error
+		latest, err = resolveBranchHead(ctx, cache, server, ref)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "fetching commit for %s: %v\n", server.Name, err)
-			continue
+			return result{failed: server.Name}
 		}
+	}
 
-		latest = strings.ToLower(latest)
+	latest = strings.ToLower(latest)
 
-		changed, err := writeCommit(serverPath, latest)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "updating %s: %v\n", server.Name, err)
-			continue
+	if server.Source.Trust != nil {
+		if latest == existing {
+			return result{skip: true}
 		}
 
-		if existing != latest {
-			fmt.Printf("Updated %s: %s -> %s\n", server.Name, existing, latest)
-		} else if changed {
-			fmt.Printf("Reformatted pinned commit for %s at %s\n", server.Name, latest)
+		// Signature/TUF verification relies on GitHub's own commit/tag
+		// signature checking (see pkg/trust), so it's only available
+		// for GitHub-hosted sources for now.
+		if !strings.Contains(server.Source.Project, "github.com/") {
+			fmt.Fprintf(os.Stderr, "Rejecting %s: trust verification is only supported for GitHub-hosted sources\n", server.Name)
+			return result{rejected: server.Name}
 		}
 
-		if changed {
-			updated = append(updated, server.Name)
+		client := github.NewFromServer(server)
+		verification, err := verifyTrust(ctx, client, serverPath, server, ref, existing, latest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Rejecting %s -> %s for %s: %v\n", existing, latest, server.Name, err)
+			return result{rejected: server.Name}
 		}
-		if existing == latest && !changed {
-			continue
+
+		if err := trust.WriteLock(serverPath, latest, verification); err != nil {
+			fmt.Fprintf(os.Stderr, "recording server.lock for %s: %v\n", server.Name, err)
+			return result{rejected: server.Name}
 		}
 	}
 
-	if len(updated) == 0 {
-		fmt.Println("No commit updates required.")
-		return
+	return result{serverPath: serverPath, pin: pin{name: server.Name, existing: existing, latest: latest, tag: tag}}
+}
+
+// resolveBranchHead resolves branch's current commit for server. GitHub
+// sources go through the refresh cache's conditional-request path, so a
+// -resume run can skip a branch the cache already confirmed this run, and
+// any other run still costs zero rate-limit points when nothing moved.
+// Other hosts fall back to the plain, uncached sourcehost resolver.
+func resolveBranchHead(ctx context.Context, cache *github.RefreshCache, server servers.Server, branch string) (string, error) {
+	if !strings.Contains(server.Source.Project, "github.com/") {
+		resolver := sourcehost.ForServer(server)
+		return resolver.ResolveRef(ctx, server.Source.Project, branch)
+	}
+
+	if sha, ok := cache.AlreadyCheckedThisRun(server.Source.Project, branch); ok {
+		return sha, nil
 	}
 
-	sort.Strings(updated)
-	fmt.Println("Servers with updated pins:", strings.Join(updated, ", "))
+	client := github.NewFromServer(server)
+	return client.GetCommitSHA1Cached(ctx, cache, server.Source.Project, branch)
 }
 
-// writeCommit inserts or updates the commit field inside the source block of
-// a server definition while preserving the surrounding formatting. The bool
-// return value indicates whether the file contents were modified.
-func writeCommit(path string, updated string) (bool, error) {
-	content, err := os.ReadFile(path)
+// printSummary reports the refresh's outcome as updated/unchanged/cached/
+// failed server counts, followed by the core rate-limit budget left
+// afterward. moved and reformatted are the pins committed this run with and
+// without a commit change, respectively; failed is the number of servers
+// whose refresh errored out.
+func printSummary(ctx context.Context, cache *github.RefreshCache, moved, reformatted, failed int) {
+	calls, cached, changed := cache.Summary()
+	fmt.Printf("Summary: %d updated, %d unchanged, %d cached (conditional requests), %d failed\n", moved, reformatted, cached, failed)
+	fmt.Printf("GitHub API calls: %d (%d unchanged via conditional requests, %d branches moved)\n", calls, cached, changed)
+
+	remaining, err := github.New().RemainingCoreRateLimit(ctx)
 	if err != nil {
-		return false, err
+		fmt.Printf("Remaining rate-limit budget: unknown (%v)\n", err)
+		return
 	}
+	fmt.Printf("Remaining rate-limit budget: %d\n", remaining)
+}
 
-	lines := strings.Split(string(content), "\n")
-	sourceIndex := -1
-	for i, line := range lines {
-		if strings.HasPrefix(line, "source:") {
-			sourceIndex = i
-			break
+// verifyTrust checks that advancing server's pin from existing to latest on
+// ref (a branch name, or the tag resolveTrackedCommit chose when
+// server.Source.Track is set) is allowed under server.Source.Trust: the
+// commit (or, with RequireSignedTag, the tag named by ref) must carry a
+// GPG/SSH signature GitHub verified from one of trust.Signers, and, when
+// trust.TargetsFile is set, latest must also be countersigned there
+// without rolling back past existing.
+func verifyTrust(ctx context.Context, client *github.Client, serverPath string, server servers.Server, ref, existing, latest string) (trust.Verification, error) {
+	cfg := server.Source.Trust
+
+	var verification trust.Verification
+	if cfg.RequireSignedTag {
+		tag, err := client.GetSignedTag(ctx, server.Source.Project, ref)
+		if err != nil {
+			return trust.Verification{}, fmt.Errorf("resolving signed tag %s: %w", ref, err)
+		}
+		if tag.GetObject().GetSHA() != latest {
+			return trust.Verification{}, fmt.Errorf("tag %s points at %s, not the resolved commit %s", ref, tag.GetObject().GetSHA(), latest)
 		}
-	}
-	if sourceIndex == -1 {
-		return false, fmt.Errorf("no source block found")
-	}
 
-	commitIndex := -1
-	indent := ""
-	commitPattern := regexp.MustCompile(`^([ \t]+)commit:\s*[a-fA-F0-9]{40}\s*$`)
-	for i := sourceIndex + 1; i < len(lines); i++ {
-		line := lines[i]
-		if !strings.HasPrefix(line, "  ") {
-			break
+		verification, err = trust.VerifySignature(ctx, tag.GetVerification(), cfg.Signers)
+		if err != nil {
+			return trust.Verification{}, err
+		}
+	} else {
+		commit, err := client.GetCommitObject(ctx, server.Source.Project, latest)
+		if err != nil {
+			return trust.Verification{}, fmt.Errorf("fetching commit %s: %w", latest, err)
 		}
 
-		if match := commitPattern.FindStringSubmatch(line); match != nil {
-			commitIndex = i
-			indent = match[1]
-			break
+		verification, err = trust.VerifySignature(ctx, commit.GetVerification(), cfg.Signers)
+		if err != nil {
+			return trust.Verification{}, err
 		}
 	}
 
-	if commitIndex < 0 {
-		return false, fmt.Errorf("no commit line found in source block")
-	}
+	if cfg.TargetsFile != "" {
+		previousDate, err := commitDate(ctx, client, server.Source.Project, existing)
+		if err != nil {
+			return trust.Verification{}, fmt.Errorf("fetching committer date for pinned commit %s: %w", existing, err)
+		}
 
-	newLine := indent + "commit: " + updated
-	lines[commitIndex] = newLine
+		newDate, err := commitDate(ctx, client, server.Source.Project, latest)
+		if err != nil {
+			return trust.Verification{}, fmt.Errorf("fetching committer date for %s: %w", latest, err)
+		}
 
-	output := strings.Join(lines, "\n")
-	if !strings.HasSuffix(output, "\n") {
-		output += "\n"
+		path := filepath.Join(filepath.Dir(serverPath), cfg.TargetsFile)
+		verification, err = trust.CheckTargets(path, ref, latest, previousDate, newDate)
+		if err != nil {
+			return trust.Verification{}, err
+		}
 	}
 
-	if output == string(content) {
-		return false, nil
-	}
+	return verification, nil
+}
 
-	if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
-		return false, err
+func commitDate(ctx context.Context, client *github.Client, project, sha string) (time.Time, error) {
+	commit, err := client.GetCommitObject(ctx, project, sha)
+	if err != nil {
+		return time.Time{}, err
 	}
-	return true, nil
+	return commit.GetCommitter().GetDate().Time, nil
 }
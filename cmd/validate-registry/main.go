@@ -0,0 +1,199 @@
+// Command validate-registry schema-checks every server.yaml under a
+// registry directory in one pass, so CI can gate a PR on the whole tree
+// instead of only the server it touched (that's cmd/validate's job).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	dir := flag.String("dir", "servers", "Root directory containing one subdirectory per server.yaml")
+	flag.Parse()
+
+	violations, err := run(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, v := range violations {
+		fmt.Printf("%s:%d: %s\n", v.file, v.line, v.message)
+	}
+
+	if len(violations) > 0 {
+		fmt.Printf("\n🛑 %d violation(s) across the registry\n", len(violations))
+		os.Exit(1)
+	}
+
+	fmt.Println("✅ Every server.yaml is valid")
+}
+
+// violation is a single structural failure, located back to the
+// server.yaml file and line it came from so it reads like a compiler error
+// instead of naming the field in the abstract.
+type violation struct {
+	file    string
+	line    int
+	message string
+}
+
+// run walks root, one subdirectory per server, and schema- and
+// structure-checks every server.yaml it finds.
+func run(root string) ([]violation, error) {
+	names, err := serverNames(root)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loadServerSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []violation
+	for _, name := range names {
+		v, err := validateServer(schema, root, name)
+		if err != nil {
+			return nil, fmt.Errorf("validating %s: %w", name, err)
+		}
+		violations = append(violations, v...)
+	}
+	return violations, nil
+}
+
+// serverNames returns the sorted list of server directory names under root.
+func serverNames(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, entry.Name(), "server.yaml")); err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// validateServer schema-checks dirName's server.yaml and then applies the
+// structural rules the schema can't express (cross-field and
+// cross-directory invariants).
+func validateServer(schema *gojsonschema.Schema, root, dirName string) ([]violation, error) {
+	path := filepath.Join(root, dirName, "server.yaml")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return []violation{{file: path, line: 1, message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	var data any
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return []violation{{file: path, line: 1, message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	var server servers.Server
+	if err := yaml.Unmarshal(raw, &server); err != nil {
+		return []violation{{file: path, line: 1, message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	var violations []violation
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range result.Errors() {
+		jsonPath := schemaViolationPath(e)
+		line, _ := locateYAMLPath(&doc, jsonPath)
+		violations = append(violations, violation{file: path, line: line, message: e.Description()})
+	}
+
+	violations = append(violations, structuralViolations(&doc, path, dirName, server)...)
+
+	return violations, nil
+}
+
+// commitPattern matches a full, lowercase git commit SHA.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// structuralViolations enforces the rules that span multiple fields (or the
+// directory name), which a JSON Schema alone can't express.
+func structuralViolations(doc *yaml.Node, path, dirName string, server servers.Server) []violation {
+	var violations []violation
+	add := func(jsonPath, message string) {
+		line, _ := locateYAMLPath(doc, jsonPath)
+		violations = append(violations, violation{file: path, line: line, message: message})
+	}
+
+	if server.Name != dirName {
+		add("(root).name", fmt.Sprintf("server directory is %q but name is %q", dirName, server.Name))
+	}
+
+	if server.Meta.Category != "" && !slices.Contains(servers.Categories, server.Meta.Category) {
+		add("(root).meta.category", fmt.Sprintf("category %q is not one of %v", server.Meta.Category, servers.Categories))
+	}
+
+	if server.Image != "" {
+		if want := "mcp/" + server.Name; server.Image != want {
+			add("(root).image", fmt.Sprintf("image %q must be %q", server.Image, want))
+		}
+	}
+
+	if server.Source.Commit != "" && !commitPattern.MatchString(server.Source.Commit) {
+		add("(root).source.commit", fmt.Sprintf("commit %q is not a 40-character hex SHA", server.Source.Commit))
+	}
+
+	declared := map[string]bool{}
+	for _, prop := range server.Config.Parameters.Properties {
+		declared[prop.Name] = true
+	}
+	for _, secret := range server.Config.Secrets {
+		if _, key, ok := strings.Cut(secret.Name, "."); ok {
+			declared[key] = true
+		} else {
+			declared[secret.Name] = true
+		}
+	}
+	for i, env := range server.Config.Env {
+		if !strings.HasPrefix(env.Value, "{{") || !strings.HasSuffix(env.Value, "}}") {
+			continue
+		}
+		ref := strings.TrimSuffix(strings.TrimPrefix(env.Value, "{{"), "}}")
+		_, key, ok := strings.Cut(ref, ".")
+		if !ok || !declared[key] {
+			add(fmt.Sprintf("(root).config.env.%d.value", i), fmt.Sprintf("env %s templates %q, which doesn't resolve to a declared parameter or secret", env.Name, env.Value))
+		}
+	}
+
+	for i, vol := range server.Run.Volumes {
+		host, container, ok := strings.Cut(vol, ":")
+		if !ok || host == "" || container == "" {
+			add(fmt.Sprintf("(root).run.volumes.%d", i), fmt.Sprintf("volume %q is not a valid host:container spec", vol))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// serverSchemaPath is the canonical JSON Schema for server.yaml; see
+// cmd/validate, which enforces the same schema against a single server.
+const serverSchemaPath = "schemas/server.schema.json"
+
+var (
+	schemaOnce sync.Once
+	schema     *gojsonschema.Schema
+	schemaErr  error
+)
+
+// loadServerSchema reads and compiles serverSchemaPath once per process.
+func loadServerSchema() (*gojsonschema.Schema, error) {
+	schemaOnce.Do(func() {
+		raw, err := os.ReadFile(serverSchemaPath)
+		if err != nil {
+			schemaErr = fmt.Errorf("reading %s: %w", serverSchemaPath, err)
+			return
+		}
+		schema, schemaErr = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	})
+	return schema, schemaErr
+}
+
+// schemaViolationPath normalizes a gojsonschema error to the dotted path of
+// the field that's actually wrong. For a "required" failure, Field() names
+// the containing object rather than the missing property, so the missing
+// property (from Details()["property"]) is appended.
+func schemaViolationPath(e gojsonschema.ResultError) string {
+	field := e.Field()
+	if e.Type() != "required" {
+		return field
+	}
+	prop, ok := e.Details()["property"].(string)
+	if !ok {
+		return field
+	}
+	if field == "(root)" {
+		return "(root)." + prop
+	}
+	return field + "." + prop
+}
+
+// locateYAMLPath walks doc to find the line/column of the value at a
+// schemaViolationPath (or a structuralViolations path of the same shape),
+// so a violation reads like a compiler error instead of naming the field
+// in the abstract.
+func locateYAMLPath(doc *yaml.Node, path string) (line, column int) {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	path = strings.TrimPrefix(path, "(root)")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return node.Line, node.Column
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		if index, err := strconv.Atoi(segment); err == nil {
+			if node.Kind != yaml.SequenceNode || index >= len(node.Content) {
+				return node.Line, node.Column
+			}
+			node = node.Content[index]
+			continue
+		}
+
+		if node.Kind != yaml.MappingNode {
+			return node.Line, node.Column
+		}
+		_, value, found := yamlField(node, segment)
+		if !found {
+			return node.Line, node.Column
+		}
+		node = value
+	}
+	return node.Line, node.Column
+}
+
+// yamlField returns the key/value node pair within mapping whose key
+// scalar is name.
+func yamlField(mapping *yaml.Node, name string) (key, value *yaml.Node, found bool) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1], true
+		}
+	}
+	return nil, nil, false
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -8,11 +9,14 @@ import (
 	"image"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	_ "image/jpeg"
@@ -21,20 +25,26 @@ import (
 	"github.com/docker/mcp-registry/internal/licenses"
 	"github.com/docker/mcp-registry/internal/mcp"
 	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/ociinspect"
+	"github.com/docker/mcp-registry/pkg/reference"
 	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/docker/mcp-registry/pkg/servers/edit"
+	"github.com/docker/mcp-registry/pkg/signing"
+	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 )
 
 func main() {
 	name := flag.String("name", "", "Name of the mcp server, name is guessed if not provided")
+	probeOAuth := flag.Bool("probe-oauth", false, "Additionally dry-run an RFC 7591 dynamic client registration request against each OAuth provider's registration endpoint")
 	flag.Parse()
 
-	if err := run(*name); err != nil {
+	if err := run(*name, *probeOAuth); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run(name string) error {
+func run(name string, probeOAuth bool) error {
 	if err := isNameValid(name); err != nil {
 		return err
 	}
@@ -73,7 +83,7 @@ func run(name string) error {
 		return err
 	}
 
-	if err := isOAuthDynamicValid(name); err != nil {
+	if err := isOAuthDynamicValid(name, probeOAuth); err != nil {
 		return err
 	}
 
@@ -81,6 +91,10 @@ func run(name string) error {
 		return err
 	}
 
+	if err := isImageReachable(name); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -91,21 +105,48 @@ var legacyNameExceptions = map[string]bool{
 	"youtube_transcript":  true,
 }
 
-// check if the name is a valid
+// check if the name is valid against schemas/server.schema.json's "name"
+// pattern, grandfathering the handful of entries that predate it. It
+// validates the candidate name in isolation (not a real server.yaml), so
+// it can run before a directory for name even exists.
 func isNameValid(name string) error {
-	// check if name has only letters, numbers, and hyphens
-	if !regexp.MustCompile(`^[a-z0-9-]+$`).MatchString(name) {
+	v, err := nameSchemaViolation(name)
+	if err != nil {
+		return err
+	}
+	if v != nil {
 		if legacyNameExceptions[name] {
 			fmt.Printf("⚠️ Name %s is grandfathered and bypasses naming rules.\n", name)
 			return nil
 		}
-		return fmt.Errorf("name is not valid. It must be a lowercase string with only letters, numbers, and hyphens")
+		return fmt.Errorf("name is not valid: %s", v.description)
 	}
 
 	fmt.Println("✅ Name is valid")
 	return nil
 }
 
+// nameSchemaViolation validates name alone against the "name" property of
+// schemas/server.schema.json, ignoring every other required field.
+func nameSchemaViolation(name string) (*schemaViolation, error) {
+	s, err := loadServerSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.Validate(gojsonschema.NewGoLoader(map[string]any{"name": name}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range result.Errors() {
+		if schemaViolationPath(e) == "name" {
+			return &schemaViolation{path: "name", description: e.Description()}, nil
+		}
+	}
+	return nil, nil
+}
+
 // check if the directory is valid
 // servers/<NAME>/server.yaml exists
 func isDirectoryValid(name string) error {
@@ -128,24 +169,20 @@ func isDirectoryValid(name string) error {
 }
 
 // check if the title is valid
-// titles should not contain "MCP" or "Server" and every word should be capitalized
+// the schema covers length and the "MCP"/"Server" substring ban; word
+// capitalization isn't expressible as a single schema pattern, so it's
+// still hand-checked here.
 func isTitleValid(name string) error {
 	server, err := readServerYaml(name)
 	if err != nil {
 		return err
 	}
 
-	title := server.About.Title
-
-	// Check for "MCP" or "Server" in the title
-	if strings.Contains(title, "MCP") {
-		return fmt.Errorf("title should not contain 'MCP': %s", title)
-	}
-	if strings.Contains(title, "Server") {
-		return fmt.Errorf("title should not contain 'Server': %s", title)
+	if v := fieldViolation(name, "about.title"); v != nil {
+		return fmt.Errorf("title is not valid (line %d): %s", v.line, v.description)
 	}
 
-	// Check that every word is capitalized
+	title := server.About.Title
 	words := strings.Fields(title)
 	for _, word := range words {
 		if len(word) == 0 {
@@ -178,8 +215,6 @@ func isYamlIndentationValid(name string) error {
 	return nil
 }
 
-var commitSHA1Pattern = regexp.MustCompile(`^[a-f0-9]{40}$`)
-
 // isCommitPinnedIfNecessary ensures that every local server is pinned to a specific commit.
 func isCommitPinnedIfNecessary(name string) error {
 	server, err := readServerYaml(name)
@@ -192,22 +227,14 @@ func isCommitPinnedIfNecessary(name string) error {
 		return nil
 	}
 
-	if server.Source.Commit == "" {
-		return fmt.Errorf("local server must specify source.commit to pin the audited revision")
-	}
-
-	if !commitSHA1Pattern.MatchString(strings.ToLower(server.Source.Commit)) {
-		return fmt.Errorf("source.commit must be a 40-character lowercase SHA1 (got %q)", server.Source.Commit)
+	if v := fieldViolation(name, "source.commit"); v != nil {
+		return fmt.Errorf("commit pin is not valid (line %d): %s", v.line, v.description)
 	}
 
 	fmt.Println("✅ Commit is pinned")
 	return nil
 }
 
-// secretNamePattern validates that secret names match the expected prefix.name
-// format requirement.
-var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9._-]+$`)
-
 // legacySecretNameExceptions enumerates secrets defined before the current
 // naming rules were introduced.
 var legacySecretNameExceptions = map[string]map[string]bool{
@@ -228,13 +255,14 @@ func areSecretsValid(name string) error {
 
 	// Ensure that all secrets match the expected format. We no longer require
 	// that the prefix matches the server name.
-	for _, secret := range server.Config.Secrets {
-		if !secretNamePattern.MatchString(secret.Name) {
+	for i, secret := range server.Config.Secrets {
+		path := fmt.Sprintf("config.secrets.%d.name", i)
+		if v := fieldViolation(name, path); v != nil {
 			if legacySecretNameExceptions[name][secret.Name] {
 				fmt.Printf("⚠️ Secret %s for %s is grandfathered and bypasses naming rules.\n", secret.Name, name)
 				continue
 			}
-			return fmt.Errorf("secret %s is not valid. It must use prefix.name format with alphanumeric characters, hyphen, period, or underscore", secret.Name)
+			return fmt.Errorf("secret %s is not valid (line %d): %s", secret.Name, v.line, v.description)
 		}
 	}
 
@@ -363,22 +391,8 @@ func isRemoteValid(name string) error {
 		return nil
 	}
 
-	// Check that transport_type is not empty for remote servers
-	if server.Remote.TransportType == "" {
-		return fmt.Errorf("remote server must have a transport_type specified")
-	}
-
-	// Validate transport_type is one of the allowed values
-	validTransports := []string{"stdio", "sse", "streamable-http"}
-	isValid := false
-	for _, valid := range validTransports {
-		if server.Remote.TransportType == valid {
-			isValid = true
-			break
-		}
-	}
-	if !isValid {
-		return fmt.Errorf("remote server transport_type must be one of: stdio, sse, streamable-http (got: %s)", server.Remote.TransportType)
+	if v := fieldViolation(name, "remote.transport_type"); v != nil {
+		return fmt.Errorf("remote server transport_type is not valid (line %d): %s", v.line, v.description)
 	}
 
 	if err := hasValidTools(server); err != nil {
@@ -433,27 +447,246 @@ var oauthDynamicToolExceptions = map[string]bool{
 }
 
 // check if servers with OAuth have dynamic tools enabled
-func isOAuthDynamicValid(name string) error {
+func isOAuthDynamicValid(name string, probeOAuth bool) error {
 	server, err := readServerYaml(name)
 	if err != nil {
 		return err
 	}
 
-	// If server has OAuth configuration, it must have dynamic tools enabled
+	// If server has OAuth configuration, it must have dynamic tools enabled.
 	if len(server.OAuth) > 0 {
-		if server.Dynamic == nil || !server.Dynamic.Tools {
+		if v := fieldViolation(name, "dynamic.tools"); v != nil {
 			if oauthDynamicToolExceptions[name] {
 				fmt.Printf("⚠️ OAuth dynamic rule bypassed for %s (special configuration).\n", name)
 			} else {
-				return fmt.Errorf("server with OAuth must have 'dynamic: tools: true' configuration")
+				return fmt.Errorf("server with OAuth must have 'dynamic: tools: true' configuration (line %d): %s", v.line, v.description)
 			}
 		}
+
+		if err := probeOAuthDiscovery(name, server, probeOAuth); err != nil {
+			return err
+		}
 	}
 
 	fmt.Println("✅ OAuth dynamic configuration is valid")
 	return nil
 }
 
+// oauthDiscoveryTimeout bounds how long metadata discovery, and the optional
+// dry-run registration request, may take per OAuth provider.
+const oauthDiscoveryTimeout = 15 * time.Second
+
+// oauthAuthorizationServerMetadata is the subset of an RFC 8414 (or OpenID
+// Connect Discovery) metadata document this validator cares about.
+type oauthAuthorizationServerMetadata struct {
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	RegistrationEndpoint  string   `json:"registration_endpoint"`
+	ScopesSupported       []string `json:"scopes_supported"`
+	GrantTypesSupported   []string `json:"grant_types_supported"`
+}
+
+// oauthDiscoveredProvider is one entry of the oauth.discovered.json written
+// for a server, recording the endpoints discovered for a single provider so
+// the registry UI and the MCP client can configure OAuth flows without a
+// human transcribing them.
+type oauthDiscoveredProvider struct {
+	Provider              string   `json:"provider,omitempty"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	RegistrationEndpoint  string   `json:"registration_endpoint"`
+	ScopesSupported       []string `json:"scopes_supported,omitempty"`
+	GrantTypesSupported   []string `json:"grant_types_supported,omitempty"`
+}
+
+// oauthWellKnownPaths are tried, in order, to discover a remote's
+// authorization server metadata.
+var oauthWellKnownPaths = []string{
+	"/.well-known/oauth-authorization-server",
+	"/.well-known/openid-configuration",
+}
+
+// oauthTransportRequiredFields lists the metadata fields that must be
+// non-empty for a server's declared remote.transport_type, beyond the
+// registration_endpoint every transport requires.
+var oauthTransportRequiredFields = map[string][]string{
+	"sse":             {"authorization_endpoint", "token_endpoint"},
+	"streamable-http": {"authorization_endpoint", "token_endpoint"},
+}
+
+// probeOAuthDiscovery fetches each OAuth provider's authorization server
+// metadata, validates it against the fields server.Remote.TransportType
+// requires, and records the discovered endpoints to
+// servers/<name>/oauth.discovered.json. When probeOAuth is set, it
+// additionally dry-runs an RFC 7591 dynamic client registration request
+// against the discovered registration_endpoint.
+func probeOAuthDiscovery(name string, server servers.Server, probeOAuth bool) error {
+	if server.Remote.URL == "" {
+		fmt.Println("✅ OAuth discovery skipped (not a remote server)")
+		return nil
+	}
+
+	origin, err := oauthIssuerOrigin(server.Remote.URL)
+	if err != nil {
+		return fmt.Errorf("could not determine OAuth issuer from remote.url %q: %w", server.Remote.URL, err)
+	}
+
+	// All of server.OAuth's entries authenticate against the same remote.url,
+	// so the metadata document (and, with --probe-oauth, the dry-run
+	// registration) is only ever fetched once and then applied to every entry.
+	ctx, cancel := context.WithTimeout(context.Background(), oauthDiscoveryTimeout)
+	defer cancel()
+
+	metadata, err := fetchOAuthServerMetadata(ctx, origin)
+	if err != nil {
+		return fmt.Errorf("OAuth metadata discovery failed for %s: %w", origin, err)
+	}
+
+	if err := requireOAuthFields(metadata, server.Remote.TransportType); err != nil {
+		return fmt.Errorf("OAuth metadata for %s is missing fields required by transport_type %q: %w", origin, server.Remote.TransportType, err)
+	}
+
+	if probeOAuth {
+		if err := dryRunClientRegistration(ctx, metadata.RegistrationEndpoint); err != nil {
+			return fmt.Errorf("dry-run dynamic client registration against %s failed: %w", metadata.RegistrationEndpoint, err)
+		}
+		fmt.Printf("✅ Dry-run dynamic client registration against %s succeeded.\n", metadata.RegistrationEndpoint)
+	}
+
+	discovered := make([]oauthDiscoveredProvider, 0, len(server.OAuth))
+	for _, provider := range server.OAuth {
+		discovered = append(discovered, oauthDiscoveredProvider{
+			Provider:              provider.Provider,
+			AuthorizationEndpoint: metadata.AuthorizationEndpoint,
+			TokenEndpoint:         metadata.TokenEndpoint,
+			RegistrationEndpoint:  metadata.RegistrationEndpoint,
+			ScopesSupported:       metadata.ScopesSupported,
+			GrantTypesSupported:   metadata.GrantTypesSupported,
+		})
+	}
+
+	payload, err := json.MarshalIndent(discovered, "", "  ")
+	if err != nil {
+		return err
+	}
+	discoveredPath := filepath.Join("servers", name, "oauth.discovered.json")
+	if err := os.WriteFile(discoveredPath, payload, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ OAuth discovery recorded to %s\n", discoveredPath)
+	return nil
+}
+
+// oauthIssuerOrigin returns the scheme+host portion of a remote server's URL,
+// which is where its OAuth authorization server metadata is expected to be
+// published per RFC 8414.
+func oauthIssuerOrigin(remoteURL string) (string, error) {
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("not an absolute URL")
+	}
+	return fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host), nil
+}
+
+// fetchOAuthServerMetadata tries each of oauthWellKnownPaths in turn and
+// decodes the first one that responds successfully.
+func fetchOAuthServerMetadata(ctx context.Context, origin string) (oauthAuthorizationServerMetadata, error) {
+	client := &http.Client{Timeout: oauthDiscoveryTimeout}
+
+	var lastErr error
+	for _, path := range oauthWellKnownPaths {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+path, nil)
+		if err != nil {
+			return oauthAuthorizationServerMetadata{}, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("%s responded with status %d", path, resp.StatusCode)
+			continue
+		}
+
+		var metadata oauthAuthorizationServerMetadata
+		err = json.NewDecoder(resp.Body).Decode(&metadata)
+		resp.Body.Close()
+		if err != nil {
+			return oauthAuthorizationServerMetadata{}, fmt.Errorf("decode %s: %w", path, err)
+		}
+		return metadata, nil
+	}
+
+	return oauthAuthorizationServerMetadata{}, fmt.Errorf("no well-known metadata document was reachable: %w", lastErr)
+}
+
+// requireOAuthFields validates that metadata carries a registration_endpoint
+// (required for every transport, since that's what makes dynamic client
+// registration possible) plus whatever additional fields
+// oauthTransportRequiredFields declares for transportType.
+func requireOAuthFields(metadata oauthAuthorizationServerMetadata, transportType string) error {
+	if metadata.RegistrationEndpoint == "" {
+		return fmt.Errorf("missing registration_endpoint")
+	}
+
+	for _, field := range oauthTransportRequiredFields[transportType] {
+		var value string
+		switch field {
+		case "authorization_endpoint":
+			value = metadata.AuthorizationEndpoint
+		case "token_endpoint":
+			value = metadata.TokenEndpoint
+		}
+		if value == "" {
+			return fmt.Errorf("missing %s", field)
+		}
+	}
+
+	return nil
+}
+
+// dryRunClientRegistration POSTs a throwaway RFC 7591 dynamic client
+// registration request and reports an error unless the server accepts it.
+// It never persists the returned client credentials; this is a reachability
+// and contract check only, run when --probe-oauth is set.
+func dryRunClientRegistration(ctx context.Context, registrationEndpoint string) error {
+	body, err := json.Marshal(map[string]any{
+		"client_name":                "mcp-registry validate --probe-oauth (dry run)",
+		"redirect_uris":              []string{"https://localhost/callback"},
+		"grant_types":                []string{"authorization_code"},
+		"token_endpoint_auth_method": "none",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, registrationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: oauthDiscoveryTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("registration endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func readServerYaml(name string) (servers.Server, error) {
 	serverYaml, err := os.ReadFile(filepath.Join("servers", name, "server.yaml"))
 	if err != nil {
@@ -467,6 +700,176 @@ func readServerYaml(name string) (servers.Server, error) {
 	return server, nil
 }
 
+// serverSchemaPath is the canonical JSON Schema for server.yaml, published
+// so editors and CI in forks can validate against the same rules this
+// command enforces.
+const serverSchemaPath = "schemas/server.schema.json"
+
+var (
+	schemaOnce sync.Once
+	schema     *gojsonschema.Schema
+	schemaErr  error
+)
+
+// loadServerSchema reads and compiles serverSchemaPath once per process.
+func loadServerSchema() (*gojsonschema.Schema, error) {
+	schemaOnce.Do(func() {
+		raw, err := os.ReadFile(serverSchemaPath)
+		if err != nil {
+			schemaErr = fmt.Errorf("reading %s: %w", serverSchemaPath, err)
+			return
+		}
+		schema, schemaErr = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	})
+	return schema, schemaErr
+}
+
+// schemaViolation is one field-level failure reported by the JSON Schema,
+// located back to the line/column it came from in server.yaml.
+type schemaViolation struct {
+	path         string // e.g. "about.title"
+	description  string
+	line, column int
+}
+
+var (
+	schemaResultsMu sync.Mutex
+	schemaResults   = map[string][]schemaViolation{}
+)
+
+// schemaViolationsFor validates name's server.yaml against serverSchemaPath,
+// caching the result since every isXValid check below consults it.
+func schemaViolationsFor(name string) ([]schemaViolation, error) {
+	schemaResultsMu.Lock()
+	defer schemaResultsMu.Unlock()
+
+	if v, ok := schemaResults[name]; ok {
+		return v, nil
+	}
+
+	raw, err := os.ReadFile(filepath.Join("servers", name, "server.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	s, err := loadServerSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.Validate(gojsonschema.NewGoLoader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]schemaViolation, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		path := schemaViolationPath(e)
+		line, column := locateYAMLPath(&doc, path)
+		violations = append(violations, schemaViolation{path: path, description: e.Description(), line: line, column: column})
+	}
+
+	schemaResults[name] = violations
+	return violations, nil
+}
+
+// schemaViolationPath normalizes a gojsonschema error to the dotted path of
+// the field that's actually wrong, e.g. "about.title" or "remote.transport_type".
+// For a "required" failure, Field() names the containing object rather than
+// the missing property, so the missing property (from Details()["property"])
+// is appended instead - dropping the synthetic "(root)" container name when
+// the missing property is itself top-level, so a required-but-missing field
+// and a present-but-invalid field report under the same path.
+func schemaViolationPath(e gojsonschema.ResultError) string {
+	field := e.Field()
+	if e.Type() != "required" {
+		return field
+	}
+	prop, ok := e.Details()["property"].(string)
+	if !ok {
+		return field
+	}
+	if field == "(root)" {
+		return prop
+	}
+	return field + "." + prop
+}
+
+// locateYAMLPath walks doc to find the line/column of the value at a
+// schemaViolationPath, so a validation error reads like a compiler error
+// instead of naming the field in the abstract.
+func locateYAMLPath(doc *yaml.Node, path string) (line, column int) {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	path = strings.TrimPrefix(path, "(root)")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return node.Line, node.Column
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		if index, err := strconv.Atoi(segment); err == nil {
+			if node.Kind != yaml.SequenceNode || index >= len(node.Content) {
+				return node.Line, node.Column
+			}
+			node = node.Content[index]
+			continue
+		}
+
+		if node.Kind != yaml.MappingNode {
+			return node.Line, node.Column
+		}
+		_, value, found := yamlField(node, segment)
+		if !found {
+			return node.Line, node.Column
+		}
+		node = value
+	}
+	return node.Line, node.Column
+}
+
+// yamlField returns the key/value node pair within mapping whose key
+// scalar is name.
+func yamlField(mapping *yaml.Node, name string) (key, value *yaml.Node, found bool) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1], true
+		}
+	}
+	return nil, nil, false
+}
+
+// fieldViolation returns the schema violation affecting jsonPath (e.g.
+// "about.title"), or nil if that field passed schema validation. A
+// violation on a containing object (e.g. "dynamic" missing entirely)
+// also matches a more specific path under it (e.g. "dynamic.tools"),
+// since there's nothing more specific to report.
+func fieldViolation(name, jsonPath string) *schemaViolation {
+	violations, err := schemaViolationsFor(name)
+	if err != nil {
+		return nil
+	}
+	for i := range violations {
+		if violations[i].path == jsonPath || strings.HasPrefix(jsonPath, violations[i].path+".") {
+			return &violations[i]
+		}
+	}
+	return nil
+}
+
 func readToolsJson(name string) ([]mcp.Tool, error) {
 	path := filepath.Join("servers", name, "tools.json")
 	buf, err := os.ReadFile(path)
@@ -482,6 +885,20 @@ func readToolsJson(name string) ([]mcp.Tool, error) {
 	return tools, nil
 }
 
+// maxPociImageBytes bounds a poci tool image's total layer size (as
+// reported by its manifest, not the uncompressed size on disk), catching
+// a bloated or malicious image before it ever reaches a sandboxed run.
+const maxPociImageBytes = 2 << 30 // 2 GiB
+
+// isPociValid resolves every poci tool's container image directly against
+// its registry - no local Docker daemon required - asserting it's
+// reachable for every platform in ociinspect.RequiredPociPlatforms and
+// within maxPociImageBytes, then verifies its cosign signature against the
+// tool's configured trust.signers (or any keyless Fulcio/Rekor identity,
+// if unset), rejecting the entry when a tool image carries no verifiable
+// signature. A verified image has its digest pinned back into
+// server.yaml, so a later re-push of the same tag can't silently swap out
+// what was reviewed.
 func isPociValid(name string) error {
 	server, err := readServerYaml(name)
 	if err != nil {
@@ -492,22 +909,143 @@ func isPociValid(name string) error {
 		return nil
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	path := filepath.Join("servers", name, "server.yaml")
+	doc, err := edit.Open(path)
+	if err != nil {
+		return err
+	}
+
 	for _, tool := range server.Tools {
-		if tool.Container.Image != "" {
-			if err := pullPociImage(tool.Container.Image); err != nil {
-				fmt.Printf("🛑 Could not pull poci image %s: %v\n", tool.Container.Image, err)
-				return err
+		if tool.Container.Image == "" {
+			continue
+		}
+
+		summary, err := ociinspect.InspectManifest(ctx, tool.Container.Image)
+		if err != nil {
+			fmt.Printf("🛑 Could not resolve poci image %s: %v\n", tool.Container.Image, err)
+			return err
+		}
+		for _, platform := range ociinspect.RequiredPociPlatforms {
+			if !summary.HasPlatform(platform) {
+				return fmt.Errorf("poci tool %s image %s has no %s manifest", tool.Name, tool.Container.Image, platform)
 			}
 		}
+		if summary.LayerBytes > maxPociImageBytes {
+			return fmt.Errorf("poci tool %s image %s is %d bytes, over the %d byte budget", tool.Name, tool.Container.Image, summary.LayerBytes, int64(maxPociImageBytes))
+		}
+
+		pinned, err := verifyPociImage(ctx, tool)
+		if err != nil {
+			fmt.Printf("🛑 Could not verify poci image %s: %v\n", tool.Container.Image, err)
+			return err
+		}
+
+		if err := doc.SetToolImageDigest(tool.Name, pinned); err != nil {
+			return err
+		}
+	}
+
+	if changed, err := doc.Changed(); err != nil {
+		return err
+	} else if changed {
+		rendered, err := doc.Bytes()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, rendered, 0o644); err != nil {
+			return err
+		}
 	}
 
 	fmt.Println("✅ Poci image is valid")
 	return nil
 }
 
-func pullPociImage(image string) error {
-	cmd := exec.Command("docker", "pull", image)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+// verifyPociImage verifies tool's container image against its configured
+// trust.signers and returns the image reference pinned to the verified
+// digest. container.trust.signers must list at least one identity - a
+// tool that hasn't opted in fails closed, rather than accepting a
+// signature from any keyless Fulcio identity whatsoever, which anyone
+// able to push to the registry could produce for themselves.
+func verifyPociImage(ctx context.Context, tool servers.Tool) (string, error) {
+	var signers []string
+	if tool.Container.Trust != nil {
+		signers = tool.Container.Trust.Signers
+	}
+	if len(signers) == 0 {
+		return "", fmt.Errorf("container.trust.signers must list at least one trusted identity")
+	}
+
+	result, err := signing.Verify(ctx, tool.Container.Image, nil)
+	if err != nil {
+		return "", fmt.Errorf("verifying signature: %w", err)
+	}
+	if !result.Verified() {
+		return "", fmt.Errorf("no verified cosign signature found")
+	}
+	if !anySignerTrusted(result.Signers, signers) {
+		return "", fmt.Errorf("signed by an identity not listed in container.trust.signers")
+	}
+
+	ref, err := reference.Parse(tool.Container.Image)
+	if err != nil {
+		return "", fmt.Errorf("parsing image reference: %w", err)
+	}
+	pinned, err := ref.WithDigest(result.Digest)
+	if err != nil {
+		return "", err
+	}
+	return pinned.String(), nil
+}
+
+// anySignerTrusted reports whether at least one verified signer's identity
+// is in trusted.
+func anySignerTrusted(verified []signing.Signer, trusted []string) bool {
+	for _, signer := range verified {
+		if slices.Contains(trusted, signer.Identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// isImageReachable checks that server.yaml's pinned image can be resolved
+// on its registry - catching a typo'd tag or a private image nobody can
+// pull - without requiring docker to pull it locally. When the image
+// carries an org.opencontainers.image.revision label, it's also checked
+// against source.commit/source.branch so a catalog entry can't silently
+// point its image at a different revision than the one it claims to audit.
+func isImageReachable(name string) error {
+	server, err := readServerYaml(name)
+	if err != nil {
+		return err
+	}
+
+	if server.Type != "server" || server.Image == "" {
+		fmt.Println("✅ Image reachability check skipped (no image to inspect)")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg, err := ociinspect.Inspect(ctx, server.Image)
+	if err != nil {
+		return fmt.Errorf("image %s is not reachable: %w", server.Image, err)
+	}
+
+	revision := cfg.Labels["org.opencontainers.image.revision"]
+	expected := server.Source.Commit
+	if expected == "" {
+		expected = server.Source.Branch
+	}
+	if revision != "" && expected != "" && revision != expected {
+		return fmt.Errorf("image %s has org.opencontainers.image.revision=%s, which does not match source (%s)", server.Image, revision, expected)
+	}
+
+	fmt.Println("✅ Image is reachable")
+	return nil
 }
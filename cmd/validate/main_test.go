@@ -151,3 +151,97 @@ func Test_areSecretsValid(t *testing.T) {
 		})
 	}
 }
+
+func Test_requireOAuthFields(t *testing.T) {
+	type args struct {
+		metadata      oauthAuthorizationServerMetadata
+		transportType string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantError bool
+	}{
+		{
+			name: "stdio transport only needs registration_endpoint",
+			args: args{
+				metadata:      oauthAuthorizationServerMetadata{RegistrationEndpoint: "https://example.com/register"},
+				transportType: "stdio",
+			},
+			wantError: false,
+		},
+		{
+			name: "missing registration_endpoint",
+			args: args{
+				metadata:      oauthAuthorizationServerMetadata{},
+				transportType: "stdio",
+			},
+			wantError: true,
+		},
+		{
+			name: "sse transport requires authorization and token endpoints",
+			args: args{
+				metadata: oauthAuthorizationServerMetadata{
+					RegistrationEndpoint:  "https://example.com/register",
+					AuthorizationEndpoint: "https://example.com/authorize",
+					TokenEndpoint:         "https://example.com/token",
+				},
+				transportType: "sse",
+			},
+			wantError: false,
+		},
+		{
+			name: "sse transport missing token_endpoint",
+			args: args{
+				metadata: oauthAuthorizationServerMetadata{
+					RegistrationEndpoint:  "https://example.com/register",
+					AuthorizationEndpoint: "https://example.com/authorize",
+				},
+				transportType: "sse",
+			},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requireOAuthFields(tt.args.metadata, tt.args.transportType); (got != nil) != tt.wantError {
+				t.Errorf("requireOAuthFields() = %v, want %v", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_oauthIssuerOrigin(t *testing.T) {
+	type args struct {
+		remoteURL string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		wantError bool
+	}{
+		{
+			name:      "url with path",
+			args:      args{remoteURL: "https://mcp.example.com/sse"},
+			want:      "https://mcp.example.com",
+			wantError: false,
+		},
+		{
+			name:      "relative path",
+			args:      args{remoteURL: "/sse"},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := oauthIssuerOrigin(tt.args.remoteURL)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("oauthIssuerOrigin() error = %v, want error %v", err, tt.wantError)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("oauthIssuerOrigin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
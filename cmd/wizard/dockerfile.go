@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/docker/mcp-registry/pkg/github"
+)
+
+// deniedBaseImages lists FROM images the wizard refuses to let through,
+// e.g. base images the registry has decided not to support. Empty by
+// default; extend it here as policy dictates.
+var deniedBaseImages []string
+
+// DockerfileInfo summarizes the instructions the wizard cares about from a
+// scanned Dockerfile: the path it was found at, its base image(s), exposed
+// ports, entrypoint/command, the user it runs as, and any ENV declarations
+// that are candidates for config.env.
+type DockerfileInfo struct {
+	Path       string
+	From       []string
+	Expose     []string
+	Entrypoint string
+	Cmd        string
+	User       string
+	Env        []EnvInput
+}
+
+// parseDockerfile does a line-oriented scan of a Dockerfile's content,
+// collecting the instructions the validation step surfaces. It doesn't
+// resolve build ARGs or follow multi-stage references; it's a best-effort
+// summary, not a build.
+func parseDockerfile(content string) DockerfileInfo {
+	var info DockerfileInfo
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		instruction := strings.ToUpper(fields[0])
+		rest := strings.TrimSpace(line[len(fields[0]):])
+
+		switch instruction {
+		case "FROM":
+			if image := strings.Fields(rest); len(image) > 0 {
+				info.From = append(info.From, image[0])
+			}
+		case "EXPOSE":
+			info.Expose = append(info.Expose, strings.Fields(rest)...)
+		case "ENTRYPOINT":
+			info.Entrypoint = rest
+		case "CMD":
+			info.Cmd = rest
+		case "USER":
+			info.User = rest
+		case "ENV":
+			info.Env = append(info.Env, parseEnvInstruction(rest)...)
+		}
+	}
+
+	return info
+}
+
+// parseEnvInstruction handles both the legacy "ENV KEY value" form and the
+// modern "ENV KEY=value [KEY=value ...]" form.
+func parseEnvInstruction(rest string) []EnvInput {
+	if rest == "" {
+		return nil
+	}
+
+	if !strings.Contains(rest, "=") {
+		parts := strings.SplitN(rest, " ", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		return []EnvInput{{Name: parts[0], Example: strings.Trim(parts[1], `"`)}}
+	}
+
+	var vars []EnvInput
+	for _, assignment := range splitEnvAssignments(rest) {
+		kv := strings.SplitN(assignment, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		vars = append(vars, EnvInput{Name: kv[0], Example: strings.Trim(kv[1], `"`)})
+	}
+	return vars
+}
+
+// splitEnvAssignments splits an ENV instruction's remainder on whitespace,
+// without breaking apart a quoted value that itself contains a space.
+func splitEnvAssignments(s string) []string {
+	var assignments []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case r == ' ' && !inQuotes:
+			if current.Len() > 0 {
+				assignments = append(assignments, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		assignments = append(assignments, current.String())
+	}
+
+	return assignments
+}
+
+// isDeniedBaseImage reports whether image (as it appears in a FROM
+// instruction, tag or digest included) matches an entry in
+// deniedBaseImages by repository name.
+func isDeniedBaseImage(image string) bool {
+	repo := image
+	if idx := strings.LastIndex(repo, "@"); idx >= 0 {
+		repo = repo[:idx]
+	}
+	if idx := strings.LastIndex(repo, ":"); idx >= 0 && idx > strings.LastIndex(repo, "/") {
+		repo = repo[:idx]
+	}
+
+	for _, denied := range deniedBaseImages {
+		if repo == denied {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchDockerfile locates and retrieves the repository's Dockerfile, trying
+// the detected directory first and, if it isn't there, offering the user a
+// guided search of the repository's top-level subdirectories.
+func fetchDockerfile(ctx context.Context, client *github.Client, data *WizardData, directory string) (DockerfileInfo, error) {
+	path := "Dockerfile"
+	if directory != "" {
+		path = directory + "/Dockerfile"
+	}
+
+	content, err := client.GetFileContent(ctx, data.GitHubRepo, data.Branch, path)
+	if err == nil {
+		info := parseDockerfile(content)
+		info.Path = path
+		return info, nil
+	}
+	if !errors.Is(err, github.ErrNotFound) {
+		return DockerfileInfo{}, err
+	}
+
+	var search bool
+	if err := huh.NewConfirm().
+		Title("No Dockerfile found").
+		Description(fmt.Sprintf("Couldn't find a Dockerfile at %s. Search the repository's top-level directories for one?", path)).
+		Value(&search).
+		Run(); err != nil {
+		return DockerfileInfo{}, err
+	}
+	if !search {
+		return DockerfileInfo{}, nil
+	}
+
+	entries, err := client.ListDirectory(ctx, data.GitHubRepo, data.Branch, "")
+	if err != nil {
+		return DockerfileInfo{}, err
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		if entry.GetType() == "dir" {
+			candidates = append(candidates, entry.GetPath())
+		}
+	}
+	sort.Strings(candidates)
+	if len(candidates) == 0 {
+		fmt.Println("[WARNING] No subdirectories to search")
+		return DockerfileInfo{}, nil
+	}
+
+	var chosen string
+	if err := huh.NewSelect[string]().
+		Title("Select a directory to search for a Dockerfile").
+		Options(huh.NewOptions(candidates...)...).
+		Value(&chosen).
+		Run(); err != nil {
+		return DockerfileInfo{}, err
+	}
+
+	path = chosen + "/Dockerfile"
+	content, err = client.GetFileContent(ctx, data.GitHubRepo, data.Branch, path)
+	if err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			fmt.Printf("[WARNING] No Dockerfile found at %s either\n", path)
+			return DockerfileInfo{}, nil
+		}
+		return DockerfileInfo{}, err
+	}
+
+	info := parseDockerfile(content)
+	info.Path = path
+	return info, nil
+}
+
+// validateDockerfile fetches the repository's Dockerfile, summarizes what
+// it finds, and lets the user accept or reject any ENV declarations as
+// candidates for data.EnvVars. It returns an error if the base image is on
+// deniedBaseImages, or if the user declines to continue past a missing
+// Dockerfile.
+func validateDockerfile(data *WizardData) error {
+	ctx := context.Background()
+	client := github.New()
+
+	repository, err := client.GetProjectRepository(ctx, data.GitHubRepo)
+	if err != nil {
+		return err
+	}
+	detected, err := github.DetectBranchAndDirectory(data.GitHubRepo, repository)
+	if err != nil {
+		return err
+	}
+
+	info, err := fetchDockerfile(ctx, client, data, detected.Directory)
+	if err != nil {
+		return err
+	}
+	if info.Path == "" {
+		var proceed bool
+		if err := huh.NewConfirm().
+			Title("Continue without a Dockerfile?").
+			Description("No Dockerfile was found. You can still continue, but the registry requires one before your server can be built.").
+			Value(&proceed).
+			Run(); err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("aborted: no Dockerfile found")
+		}
+		return nil
+	}
+
+	for _, image := range info.From {
+		if isDeniedBaseImage(image) {
+			return fmt.Errorf("base image %q (in %s) is not allowed; see deniedBaseImages", image, info.Path)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("🔎 Found %s\n", info.Path)
+	fmt.Printf("   FROM: %s\n", strings.Join(info.From, ", "))
+	if len(info.Expose) > 0 {
+		fmt.Printf("   EXPOSE: %s\n", strings.Join(info.Expose, ", "))
+	}
+	if info.User != "" {
+		fmt.Printf("   USER: %s\n", info.User)
+	}
+	if info.Entrypoint != "" {
+		fmt.Printf("   ENTRYPOINT: %s\n", info.Entrypoint)
+	}
+	if info.Cmd != "" {
+		fmt.Printf("   CMD: %s\n", info.Cmd)
+	}
+	fmt.Println()
+
+	if len(info.Env) == 0 {
+		return nil
+	}
+
+	options := make([]huh.Option[int], len(info.Env))
+	for i, env := range info.Env {
+		options[i] = huh.NewOption(fmt.Sprintf("%s=%s", env.Name, env.Example), i).Selected(true)
+	}
+
+	var selected []int
+	if err := huh.NewMultiSelect[int]().
+		Title("Environment variables found in the Dockerfile").
+		Description("Uncheck any you don't want pre-populated into config.env").
+		Options(options...).
+		Value(&selected).
+		Run(); err != nil {
+		return err
+	}
+
+	chosen := make(map[int]bool, len(selected))
+	for _, i := range selected {
+		chosen[i] = true
+	}
+	for i, env := range info.Env {
+		if chosen[i] {
+			data.EnvVars = append(data.EnvVars, env)
+		}
+	}
+	if len(data.EnvVars) > 0 {
+		data.AddEnvVars = true
+	}
+
+	return nil
+}
@@ -17,20 +17,6 @@ import (
 )
 
 var (
-	categories = []string{
-		"ai",
-		"data-visualization",
-		"database",
-		"devops",
-		"ecommerce",
-		"finance",
-		"games",
-		"communication",
-		"monitoring",
-		"productivity",
-		"search",
-	}
-
 	titleStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#4ECDC4")).
 			Bold(true).
@@ -146,6 +132,10 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if err := validateDockerfile(&data); err != nil {
+		log.Fatal(err)
+	}
+
 	// Basic Information Form
 	basicForm := huh.NewForm(
 		huh.NewGroup(
@@ -179,7 +169,7 @@ func main() {
 			huh.NewSelect[string]().
 				Title("Category").
 				Description("Select the category that best describes your MCP server").
-				Options(huh.NewOptions(categories...)...).
+				Options(huh.NewOptions(servers.Categories...)...).
 				Value(&data.Category),
 		).Title("📋 Basic Information"),
 	).WithTheme(huh.ThemeCharm())
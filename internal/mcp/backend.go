@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// backend launches the container (or pod) that hosts an MCP server under
+// test and speaks stdio with it, so that `mcp.Tools` can probe a server
+// without depending on any one container runtime.
+type backend interface {
+	// Name identifies the backend, e.g. for error messages.
+	Name() string
+	// PullImage fetches image ahead of running it, if the backend
+	// supports/needs an explicit pull step.
+	PullImage(ctx context.Context, image string) error
+	// Command returns the executable and arguments that start image as a
+	// foreground, stdio-attached process, with extraArgs (e.g. network
+	// flags) inserted before the image name and command appended after it.
+	// sandbox constrains the container's own network, filesystem, user,
+	// and resource limits; see servers.Sandbox.
+	Command(image string, env []servers.Env, secrets []servers.Secret, extraArgs []string, command []string, sandbox servers.Sandbox) (string, []string)
+	// RemoveImage deletes the local copy of image, if the backend supports
+	// that notion.
+	RemoveImage(image string) error
+}
+
+// backendEnv selects which backend `mcp.Tools` uses. Defaults to docker;
+// set to "kubernetes" (or "k8s") to probe servers as Kubernetes pods
+// instead, e.g. when the registry's CI runs against a cluster rather than
+// a local docker daemon.
+const backendEnv = "MCP_REGISTRY_BACKEND"
+
+// selectBackend picks a backend implementation based on backendEnv.
+func selectBackend() backend {
+	switch os.Getenv(backendEnv) {
+	case "kubernetes", "k8s":
+		return newKubernetesBackend()
+	default:
+		return dockerBackend{}
+	}
+}
+
+// dockerBackend runs servers as local docker containers. It is the
+// original and default behavior of `mcp.Tools`.
+type dockerBackend struct{}
+
+func (dockerBackend) Name() string { return "docker" }
+
+func (dockerBackend) PullImage(ctx context.Context, image string) error {
+	output, err := exec.CommandContext(ctx, "docker", "pull", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w (%s)", image, err, string(output))
+	}
+	return nil
+}
+
+func (dockerBackend) Command(image string, env []servers.Env, secrets []servers.Secret, extraArgs []string, command []string, sandbox servers.Sandbox) (string, []string) {
+	args := []string{"run", "--rm", "-i", "--init", "--cap-drop=ALL"}
+	args = append(args, sandboxArgs(sandbox)...)
+	args = append(args, extraArgs...)
+	for _, e := range env {
+		args = append(args, "-e", e.Name)
+	}
+	for _, secret := range secrets {
+		args = append(args, "-e", secret.Env)
+	}
+	args = append(args, image)
+	args = append(args, command...)
+	return "docker", args
+}
+
+// defaultSandbox hardens a container against exfiltrating data or
+// exhausting the host when a server.yaml doesn't declare its own sandbox
+// profile: no network, a read-only root filesystem, an unprivileged user,
+// and a modest memory and pids ceiling.
+var defaultSandbox = servers.Sandbox{
+	Network:        "none",
+	ReadOnlyRootFS: true,
+	User:           "65534:65534",
+	Memory:         "512m",
+	PidsLimit:      256,
+}
+
+// sandboxArgs translates sandbox into `docker run` flags. Fields left at
+// their zero value are omitted, which for docker means "use docker's own
+// default" (e.g. no --network leaves the bridge network in place).
+func sandboxArgs(sandbox servers.Sandbox) []string {
+	var args []string
+	if sandbox.Network != "" {
+		args = append(args, "--network="+sandbox.Network)
+	}
+	if sandbox.ReadOnlyRootFS {
+		args = append(args, "--read-only")
+	}
+	if sandbox.User != "" {
+		args = append(args, "--user="+sandbox.User)
+	}
+	if sandbox.Memory != "" {
+		args = append(args, "--memory="+sandbox.Memory)
+	}
+	if sandbox.CPUs != "" {
+		args = append(args, "--cpus="+sandbox.CPUs)
+	}
+	if sandbox.PidsLimit > 0 {
+		args = append(args, fmt.Sprintf("--pids-limit=%d", sandbox.PidsLimit))
+	}
+	for _, mount := range sandbox.Tmpfs {
+		args = append(args, "--tmpfs", mount)
+	}
+	if sandbox.SeccompProfile != "" {
+		args = append(args, "--security-opt", "seccomp="+sandbox.SeccompProfile)
+	}
+	return args
+}
+
+func (dockerBackend) RemoveImage(image string) error {
+	output, err := exec.Command("docker", "rmi", "-f", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed removing image %s: %w (%s)", image, err, string(output))
+	}
+	return nil
+}
+
+// kubernetesBackend runs servers as short-lived, attached Kubernetes pods
+// via `kubectl run`, so tool discovery can be driven against a cluster
+// instead of a local docker daemon. It shells out to kubectl the same way
+// the rest of this package shells out to docker, rather than taking a
+// dependency on client-go.
+type kubernetesBackend struct {
+	namespace string
+}
+
+// kubernetesNamespaceEnv optionally overrides the namespace pods are run
+// in; it defaults to kubectl's current context namespace.
+const kubernetesNamespaceEnv = "MCP_REGISTRY_KUBERNETES_NAMESPACE"
+
+func newKubernetesBackend() kubernetesBackend {
+	return kubernetesBackend{namespace: os.Getenv(kubernetesNamespaceEnv)}
+}
+
+func (kubernetesBackend) Name() string { return "kubernetes" }
+
+// PullImage is a no-op: the kubelet pulls images as part of scheduling the
+// pod, there is no separate client-side pull step.
+func (kubernetesBackend) PullImage(ctx context.Context, image string) error { return nil }
+
+// Command ignores sandbox: pod-level sandboxing is a cluster admission
+// policy's job (e.g. a PodSecurityContext/PSP), not something per-server
+// registry metadata should drive for this backend.
+func (b kubernetesBackend) Command(image string, env []servers.Env, secrets []servers.Secret, extraArgs []string, command []string, sandbox servers.Sandbox) (string, []string) {
+	podName := fmt.Sprintf("mcp-tools-%s", randString(8))
+
+	args := []string{"run", podName, "--rm", "-i", "--restart=Never", "--image", image}
+	if b.namespace != "" {
+		args = append(args, "--namespace", b.namespace)
+	}
+	// Unlike `docker run -e NAME` (which inherits the value from the
+	// docker CLI's own environment), `kubectl run --env` takes no
+	// passthrough form, so the values are embedded directly.
+	for _, e := range env {
+		args = append(args, "--env", fmt.Sprintf("%s=%v", e.Name, e.Example))
+	}
+	for _, secret := range secrets {
+		args = append(args, "--env", secret.Env+"="+secret.Example)
+	}
+	args = append(args, extraArgs...)
+	if len(command) > 0 {
+		args = append(args, "--command", "--", command[0])
+		args = append(args, command[1:]...)
+	}
+	return "kubectl", args
+}
+
+// RemoveImage is a no-op: kubectl has no notion of deleting a node-local
+// image, that is managed by the kubelet/cluster's image garbage collector.
+func (kubernetesBackend) RemoveImage(image string) error { return nil }
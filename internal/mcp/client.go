@@ -24,10 +24,13 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/docker/mcp-registry/pkg/execenv"
 	"github.com/docker/mcp-registry/pkg/servers"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -39,11 +42,21 @@ type client struct {
 	secrets []servers.Secret
 	args    []string
 	command []string
+	sandbox servers.Sandbox
+	backend backend
 
-	c *stdioMCPClient
+	c            *stdioMCPClient
+	capabilities mcp.ServerCapabilities
 }
 
-func newClient(image string, pull bool, env []servers.Env, secrets []servers.Secret, args []string, command []string) *client {
+// newClient builds a client for image. sandbox may be nil, in which case
+// defaultSandbox is used, so a server.yaml that hasn't opted into its own
+// profile still runs hardened rather than wide open.
+func newClient(image string, pull bool, env []servers.Env, secrets []servers.Secret, args []string, command []string, sandbox *servers.Sandbox) *client {
+	resolved := defaultSandbox
+	if sandbox != nil {
+		resolved = *sandbox
+	}
 	return &client{
 		image:   image,
 		pull:    pull,
@@ -51,6 +64,8 @@ func newClient(image string, pull bool, env []servers.Env, secrets []servers.Sec
 		secrets: secrets,
 		args:    args,
 		command: command,
+		sandbox: resolved,
+		backend: selectBackend(),
 	}
 }
 
@@ -60,25 +75,21 @@ func (cl *client) Start(ctx context.Context, debug bool) error {
 	}
 
 	if cl.pull {
-		output, err := exec.CommandContext(ctx, "docker", "pull", cl.image).CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("pulling image %s: %w (%s)", cl.image, err, string(output))
+		if err := cl.backend.PullImage(ctx, cl.image); err != nil {
+			return err
 		}
 	}
 
-	args := []string{"run", "--rm", "-i", "--init", "--cap-drop=ALL"}
-	args = append(args, cl.args...)
-	for _, env := range cl.env {
-		args = append(args, "-e", env.Name)
-	}
-	for _, secret := range cl.secrets {
-		args = append(args, "-e", secret.Env)
-	}
-	args = append(args, cl.image)
+	var resolvedCommand []string
 	for _, arg := range cl.command {
-		args = append(args, replacePlaceholders(arg, cl.env, cl.secrets))
+		resolvedCommand = append(resolvedCommand, replacePlaceholders(arg, cl.env, cl.secrets))
+	}
+
+	commandName, args := cl.backend.Command(cl.image, cl.env, cl.secrets, cl.args, resolvedCommand, cl.sandbox)
+	if debug {
+		fmt.Fprintf(os.Stderr, "mcp: starting %s: %s %s\n", cl.image, commandName, strings.Join(args, " "))
 	}
-	c := newMCPClient("docker", toEnviron(cl.env, cl.secrets), args...)
+	c := newMCPClient(commandName, execenv.Build(execenv.Config{}, toEnviron(cl.env, cl.secrets)...), ClientOptions{}, args...)
 	cl.c = c
 
 	initRequest := mcp.InitializeRequest{}
@@ -91,12 +102,20 @@ func (cl *client) Start(ctx context.Context, debug bool) error {
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	if _, err := c.Initialize(ctx, initRequest, debug); err != nil {
+	result, err := c.Initialize(ctx, initRequest, debug)
+	if err != nil {
 		return fmt.Errorf("initializing %s: %w", cl.image, err)
 	}
+	cl.capabilities = result.Capabilities
 	return nil
 }
 
+// Capabilities returns the capabilities the server advertised in its
+// initialize response. It's only meaningful after Start succeeds.
+func (cl *client) Capabilities() mcp.ServerCapabilities {
+	return cl.capabilities
+}
+
 func (cl *client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
 	if cl.c == nil {
 		return nil, fmt.Errorf("listing tools %s: not started", cl.image)
@@ -152,18 +171,22 @@ func (cl *client) Close(deleteImage bool) error {
 	if cl.c == nil {
 		return fmt.Errorf("closing %s: not started", cl.image)
 	}
+
+	// Attempt the image removal even when closing the connection failed,
+	// and join both errors rather than discarding the cleanup failure, so
+	// a leaked container or dangling image is attributed back to cl.image.
+	var closeErr, removeErr error
 	if err := cl.c.Close(); err != nil {
-		return err
+		closeErr = fmt.Errorf("closing %s: %w", cl.image, err)
 	}
 
 	if deleteImage {
-		output, err := exec.Command("docker", "rmi", "-f", cl.image).CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed removing image %s: %w (%s)", cl.image, err, string(output))
+		if err := cl.backend.RemoveImage(cl.image); err != nil {
+			removeErr = fmt.Errorf("removing image %s: %w", cl.image, err)
 		}
 	}
 
-	return nil
+	return errors.Join(closeErr, removeErr)
 }
 
 func replacePlaceholders(arg string, env []servers.Env, secrets []servers.Secret) string {
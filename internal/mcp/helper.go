@@ -35,27 +35,95 @@ import (
 )
 
 func Tools(ctx context.Context, server servers.Server, pull, cleanup, debug bool) ([]Tool, error) {
+	var tools []Tool
+	err := withClient(ctx, server, pull, cleanup, debug, func(c *client) error {
+		rawTools, err := c.ListTools(ctx)
+		if err != nil {
+			return err
+		}
+		tools = convertTools(rawTools)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+// Probe launches server the same way Tools does, but captures its full
+// advertised surface from that single session - the initialize
+// capabilities plus both ListTools and ListPrompts - so a caller that
+// wants more than the tool listing doesn't have to launch the server a
+// second time just to also ask for prompts.
+func Probe(ctx context.Context, server servers.Server, pull, cleanup, debug bool) (*Inventory, error) {
+	var inventory Inventory
+	err := withClient(ctx, server, pull, cleanup, debug, func(c *client) error {
+		capabilities := c.Capabilities()
+		inventory.Capabilities = Capabilities{
+			Logging:   capabilities.Logging != nil,
+			Prompts:   capabilities.Prompts != nil,
+			Resources: capabilities.Resources != nil,
+			Tools:     capabilities.Tools != nil,
+		}
+
+		tools, err := c.ListTools(ctx)
+		if err != nil {
+			return err
+		}
+		inventory.Tools = convertTools(tools)
+
+		if capabilities.Prompts == nil {
+			return nil
+		}
+		prompts, err := c.ListPrompts(ctx)
+		if err != nil {
+			return err
+		}
+		inventory.Prompts = convertPrompts(prompts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// withClient builds and starts a client for server, runs fn against it,
+// and closes it on return. Requirement containers and the network they
+// share - when server.Requirements is non-empty - stay up for the
+// duration of fn and are torn down alongside the client itself, so Tools
+// and Probe don't have to repeat this setup/teardown to get at the
+// underlying *client differently.
+func withClient(ctx context.Context, server servers.Server, pull, cleanup, debug bool, fn func(*client) error) (err error) {
 	var (
 		args     []string
 		extraEnv []servers.Env
 	)
-	if len(server.Requirement) > 0 {
-		cancel, sidecarID, env, err := runRequirement(ctx, server.Requirement)
+	if len(server.Requirements) > 0 {
+		network, removeNetwork, err := createRequirementsNetwork(ctx)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		defer cancel()
+		defer removeNetwork()
 
-		for _, e := range env {
-			parts := strings.SplitN(e, "=", 2)
+		for _, requirement := range server.Requirements {
+			cancel, _, env, err := runRequirement(ctx, network, requirement)
+			if err != nil {
+				return err
+			}
+			defer cancel()
 
-			extraEnv = append(extraEnv, servers.Env{
-				Name:    parts[0],
-				Example: parts[1],
-			})
+			for _, e := range env {
+				parts := strings.SplitN(e, "=", 2)
+
+				extraEnv = append(extraEnv, servers.Env{
+					Name:    parts[0],
+					Example: parts[1],
+				})
+			}
 		}
 
-		args = append(args, "--network", "container:"+sidecarID)
+		args = append(args, "--network", network)
 	}
 
 	env := append(server.Config.Env, extraEnv...)
@@ -66,22 +134,23 @@ func Tools(ctx context.Context, server servers.Server, pull, cleanup, debug bool
 		})
 	}
 
-	c := newClient(server.Image, pull, env, server.Config.Secrets, args, server.Run.Command)
+	c := newClient(server.Image, pull, env, server.Config.Secrets, args, server.Run.Command, server.Sandbox)
 	if err := c.Start(ctx, debug); err != nil {
-		return nil, err
-	}
-
-	tools, err := c.ListTools(ctx)
-	if err != nil {
-		c.Close(cleanup)
-		return nil, err
+		return err
 	}
+	defer func() {
+		if closeErr := c.Close(cleanup); err == nil {
+			err = closeErr
+		}
+	}()
 
-	err = c.Close(cleanup)
-	if err != nil {
-		return nil, err
-	}
+	return fn(c)
+}
 
+// convertTools adapts mcp-go's wire-format tools (as returned by both the
+// stdio and HTTP clients' ListTools) to this package's Tool, which is what
+// gets written to tools.json.
+func convertTools(tools []mcp.Tool) []Tool {
 	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
 
 	var list []Tool
@@ -164,7 +233,33 @@ func Tools(ctx context.Context, server servers.Server, pull, cleanup, debug bool
 		})
 	}
 
-	return list, nil
+	return list
+}
+
+// convertPrompts adapts mcp-go's wire-format prompts (as returned by
+// ListPrompts) to this package's Prompt, mirroring convertTools.
+func convertPrompts(prompts []mcp.Prompt) []Prompt {
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	var list []Prompt
+	for _, prompt := range prompts {
+		var arguments []PromptArgument
+		for _, arg := range prompt.Arguments {
+			arguments = append(arguments, PromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
+		}
+
+		list = append(list, Prompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   arguments,
+		})
+	}
+
+	return list
 }
 
 func removeArgs(input string) string {
@@ -0,0 +1,347 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var _ Client = (*httpMCPClient)(nil)
+
+// httpMCPClient speaks MCP's Streamable HTTP transport: requests are POSTed
+// one at a time to a single endpoint, and responses arrive either inline
+// (Content-Type: application/json) or as a one-shot text/event-stream, the
+// same as stdioMCPClient's readResponses does for the child process' stdout.
+// A long-lived GET against the same endpoint carries server-initiated
+// messages that aren't replies to any particular POST.
+type httpMCPClient struct {
+	baseURL string
+	headers map[string]string
+
+	httpClient *http.Client
+	requestID  atomic.Int64
+	responses  sync.Map // int64 -> chan RPCResponse
+
+	sessionID atomic.Pointer[string]
+
+	stdin       sync.Mutex // serializes POSTs, mirroring stdioMCPClient's c.stdin writer
+	cancel      context.CancelFunc
+	initialized atomic.Bool
+}
+
+// newHTTPMCPClient builds a client that speaks to a remote MCP server at
+// baseURL, attaching headers (e.g. Authorization) to every request.
+func newHTTPMCPClient(baseURL string, headers map[string]string) *httpMCPClient {
+	return &httpMCPClient{
+		baseURL:    baseURL,
+		headers:    headers,
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (c *httpMCPClient) Initialize(ctx context.Context, request mcp.InitializeRequest, debug bool) (*mcp.InitializeResult, error) {
+	if c.initialized.Load() {
+		return nil, fmt.Errorf("client already initialized")
+	}
+
+	params := struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		ClientInfo      mcp.Implementation     `json:"clientInfo"`
+		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+	}{
+		ProtocolVersion: request.Params.ProtocolVersion,
+		ClientInfo:      request.Params.ClientInfo,
+		Capabilities:    request.Params.Capabilities,
+	}
+
+	response, err := c.sendRequest(ctx, "initialize", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(*response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	go c.listen(streamCtx, debug)
+
+	c.initialized.Store(true)
+	return &result, nil
+}
+
+func (c *httpMCPClient) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
+
+// listen opens the long-lived GET stream for server-initiated messages and
+// dispatches anything carrying a request id to whichever sendRequest call
+// is waiting on it, the same way stdioMCPClient.readResponses does for the
+// child process' stdout.
+func (c *httpMCPClient) listen(ctx context.Context, debug bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return
+	}
+	c.setHeaders(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	// Servers that don't support a server-initiated stream are free to
+	// reject the GET outright; there's simply nothing more to listen for.
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	scanSSE(resp.Body, func(data []byte) {
+		c.dispatch(data, debug)
+	})
+}
+
+func (c *httpMCPClient) sendRequest(ctx context.Context, method string, params any) (*json.RawMessage, error) {
+	id := c.requestID.Add(1)
+	responseChan := make(chan RPCResponse, 1)
+	c.responses.Store(id, responseChan)
+	defer c.responses.Delete(id)
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Request: mcp.Request{
+			Method: method,
+		},
+		Params: params,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	if err := c.post(ctx, &body, func(data []byte) {
+		c.dispatch(data, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case response := <-responseChan:
+		if response.Error != nil {
+			return nil, errors.New(*response.Error)
+		}
+		return response.Response, nil
+	}
+}
+
+// notify POSTs a one-way JSON-RPC notification, which the server never
+// replies to.
+func (c *httpMCPClient) notify(ctx context.Context, method string, params any) error {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return c.post(ctx, &body, func(data []byte) {
+		c.dispatch(data, false)
+	})
+}
+
+// post sends a single JSON-RPC message and feeds whatever comes back - a
+// single JSON object, or a short-lived event stream - to onMessage, one
+// message at a time.
+func (c *httpMCPClient) post(ctx context.Context, body io.Reader, onMessage func([]byte)) error {
+	c.stdin.Lock()
+	defer c.stdin.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, body)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		c.sessionID.Store(&sessionID)
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		// A notification: no body to read.
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("mcp: %s returned %s: %s", c.baseURL, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		scanSSE(resp.Body, onMessage)
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	onMessage(data)
+	return nil
+}
+
+func (c *httpMCPClient) setHeaders(req *http.Request) {
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	if sessionID := c.sessionID.Load(); sessionID != nil {
+		req.Header.Set("Mcp-Session-Id", *sessionID)
+	}
+}
+
+// dispatch decodes a single JSON-RPC message and, if it's a reply to a
+// pending sendRequest, delivers it to that call's response channel.
+func (c *httpMCPClient) dispatch(data []byte, debug bool) {
+	var baseMessage BaseMessage
+	if err := json.Unmarshal(data, &baseMessage); err != nil {
+		return
+	}
+
+	if baseMessage.ID == nil {
+		return
+	}
+
+	ch, ok := c.responses.Load(*baseMessage.ID)
+	if !ok {
+		return
+	}
+	responseChan := ch.(chan RPCResponse)
+
+	if baseMessage.Error != nil {
+		responseChan <- RPCResponse{Error: &baseMessage.Error.Message}
+	} else {
+		responseChan <- RPCResponse{Response: &baseMessage.Result}
+	}
+}
+
+func (c *httpMCPClient) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	response, err := c.sendRequest(ctx, "tools/list", request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListToolsResult
+	if err := json.Unmarshal(*response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *httpMCPClient) ListPrompts(ctx context.Context, request mcp.ListPromptsRequest) (*mcp.ListPromptsResult, error) {
+	response, err := c.sendRequest(ctx, "prompts/list", request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListPromptsResult
+	if err := json.Unmarshal(*response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *httpMCPClient) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	response, err := c.sendRequest(ctx, "tools/call", request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.ParseCallToolResult(response)
+}
+
+// scanSSE reads a text/event-stream body and invokes onData with each
+// event's accumulated "data:" payload, per the SSE framing the Streamable
+// HTTP transport uses to carry JSON-RPC messages.
+func scanSSE(body io.Reader, onData func([]byte)) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var data bytes.Buffer
+	flush := func() {
+		if data.Len() == 0 {
+			return
+		}
+		onData(bytes.TrimRight(data.Bytes(), "\n"))
+		data.Reset()
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			data.WriteByte('\n')
+		default:
+			// Ignore "event:", "id:", and comment lines; this transport
+			// only ever needs the JSON-RPC payload carried in "data:".
+		}
+	}
+	flush()
+}
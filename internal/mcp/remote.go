@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// RemoteTools probes a "remote"-type server.yaml entry over its configured
+// network transport (server.Remote), the HTTP/SSE equivalent of what
+// `Tools` does for a container run over stdio, so server.yaml's own
+// declared transport decides how it's audited rather than forcing every
+// server through a subprocess.
+func RemoteTools(ctx context.Context, server servers.Server) ([]Tool, error) {
+	if server.Remote.URL == "" {
+		return nil, fmt.Errorf("remote tools requires remote.url")
+	}
+
+	c := newHTTPMCPClient(server.Remote.URL, nil)
+	defer c.Close()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "docker",
+		Version: "1.0.0",
+	}
+
+	if _, err := c.Initialize(ctx, initRequest, false); err != nil {
+		return nil, fmt.Errorf("initializing %s: %w", server.Remote.URL, err)
+	}
+
+	result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing tools %s: %w", server.Remote.URL, err)
+	}
+
+	return convertTools(result.Tools), nil
+}
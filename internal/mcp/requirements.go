@@ -27,31 +27,169 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net"
+	"net/http"
 	"os/exec"
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/docker/mcp-registry/pkg/servers"
 )
 
-func runRequirement(ctx context.Context, requirement string) (func(), string, []string, error) {
-	if requirement != "neo4j" {
-		return nil, "", nil, fmt.Errorf("unsupported requirement: %s", requirement)
+// readiness describes how runRequirement decides that a sidecar is ready to
+// serve traffic. Exactly one of the probe kinds below is used.
+type readiness struct {
+	// logContains, when set, waits for the substring to appear in the
+	// sidecar's combined stdout/stderr.
+	logContains string
+	// tcpPort, when non-zero, waits for a TCP connection to succeed on
+	// that port inside the sidecar's network.
+	tcpPort int
+	// httpPath, when set (together with httpPort), waits for a 2xx
+	// response from that path.
+	httpPort int
+	httpPath string
+}
+
+// requirementSpec is the declarative description of a sidecar: which image
+// to run, what environment it needs, how to tell it is ready, and what
+// environment variables to export to the MCP server under test once it is.
+type requirementSpec struct {
+	image     string
+	env       map[string]string
+	readiness readiness
+	timeout   time.Duration
+	exportEnv func(containerName string) []string
+}
+
+// requirementRegistry holds the built-in sidecar specs. server.yaml selects
+// one by name via `requirements[].type`; `image` and `env` there override
+// the defaults below.
+var requirementRegistry = map[string]requirementSpec{
+	"neo4j": {
+		image:     "neo4j",
+		env:       map[string]string{"NEO4J_AUTH": "none"},
+		readiness: readiness{logContains: "Started."},
+		timeout:   30 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"NEO4J_URL=bolt://" + containerName + ":7687"}
+		},
+	},
+	"postgres": {
+		image:     "postgres",
+		env:       map[string]string{"POSTGRES_PASSWORD": "postgres"},
+		readiness: readiness{logContains: "database system is ready to accept connections"},
+		timeout:   30 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"POSTGRES_URL=postgres://postgres:postgres@" + containerName + ":5432/postgres?sslmode=disable"}
+		},
+	},
+	"mysql": {
+		image:     "mysql",
+		env:       map[string]string{"MYSQL_ALLOW_EMPTY_PASSWORD": "yes"},
+		readiness: readiness{logContains: "ready for connections"},
+		timeout:   60 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"MYSQL_URL=mysql://root@" + containerName + ":3306/mysql"}
+		},
+	},
+	"redis": {
+		image:     "redis",
+		readiness: readiness{tcpPort: 6379},
+		timeout:   15 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"REDIS_URL=redis://" + containerName + ":6379"}
+		},
+	},
+	"mongodb": {
+		image:     "mongo",
+		readiness: readiness{tcpPort: 27017},
+		timeout:   30 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"MONGODB_URL=mongodb://" + containerName + ":27017"}
+		},
+	},
+	"elasticsearch": {
+		image:     "elasticsearch:8.15.0",
+		env:       map[string]string{"discovery.type": "single-node", "xpack.security.enabled": "false"},
+		readiness: readiness{httpPort: 9200, httpPath: "/"},
+		timeout:   60 * time.Second,
+		exportEnv: func(containerName string) []string {
+			return []string{"ELASTICSEARCH_URL=http://" + containerName + ":9200"}
+		},
+	},
+}
+
+// createRequirementsNetwork creates a dedicated docker network so the MCP
+// server under test and its sidecars can reach each other by container
+// name, regardless of how many requirements a server declares. The
+// returned func removes the network.
+func createRequirementsNetwork(ctx context.Context) (string, func(), error) {
+	name := fmt.Sprintf("mcp-requirements-%s", randString(8))
+
+	if out, err := exec.CommandContext(ctx, "docker", "network", "create", name).CombinedOutput(); err != nil {
+		return "", nil, fmt.Errorf("failed to create requirements network: %w (%s)", err, out)
+	}
+
+	remove := func() {
+		_ = exec.Command("docker", "network", "rm", name).Run()
+	}
+
+	return name, remove, nil
+}
+
+// runRequirement pulls and starts the sidecar described by requirement,
+// attaches it to network so the MCP server under test can reach it by
+// container name, and blocks until the sidecar's readiness probe passes (or
+// its timeout expires). It returns a cancel func that tears the sidecar
+// down, the container name, and the environment variables to export to the
+// server under test.
+func runRequirement(ctx context.Context, network string, requirement servers.Requirement) (func(), string, []string, error) {
+	spec, ok := requirementRegistry[requirement.Type]
+	if !ok {
+		return nil, "", nil, fmt.Errorf("unsupported requirement: %s", requirement.Type)
+	}
+
+	image := spec.image
+	if requirement.Image != "" {
+		image = requirement.Image
+	}
+
+	env := map[string]string{}
+	for k, v := range spec.env {
+		env[k] = v
+	}
+	for k, v := range requirement.Env {
+		env[k] = v
 	}
 
 	// Pull first to not count the pull duration in the timeout.
-	cmdPull := exec.CommandContext(ctx, "docker", "pull", "neo4j")
+	cmdPull := exec.CommandContext(ctx, "docker", "pull", image)
 	if err := cmdPull.Run(); err != nil {
-		return nil, "", nil, fmt.Errorf("failed to pull Neo4j: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to pull %s: %w", image, err)
 	}
 
-	// Run neo4j as a sidecar.
 	ctxRequirement, cancel := context.WithCancel(ctx)
 
 	var stdout bytes.Buffer
 
-	containerName := fmt.Sprintf("neo4j-%s", randString(8))
-	cmd := exec.CommandContext(ctxRequirement, "docker", "run", "--name", containerName, "--rm", "--init", "-e", "NEO4J_AUTH=none", "neo4j")
+	containerName := fmt.Sprintf("%s-%s", requirement.Type, randString(8))
+	args := []string{"run", "--name", containerName, "--rm", "--init", "--network", network}
+	for k, v := range env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	if port := spec.readiness.tcpPort; port != 0 {
+		args = append(args, "-p", fmt.Sprintf("0:%d", port))
+	}
+	if port := spec.readiness.httpPort; port != 0 {
+		args = append(args, "-p", fmt.Sprintf("0:%d", port))
+	}
+	args = append(args, image)
+
+	cmd := exec.CommandContext(ctxRequirement, "docker", args...)
 	cmd.Stdout = &stdout
+	cmd.Stderr = &stdout
 	cmd.Cancel = func() error {
 		return cmd.Process.Signal(syscall.SIGTERM)
 	}
@@ -61,31 +199,94 @@ func runRequirement(ctx context.Context, requirement string) (func(), string, []
 		return nil, "", nil, err
 	}
 
-	start := time.Now()
-	started := false
-waitStarted:
+	timeout := spec.timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	if err := waitReady(ctx, spec.readiness, containerName, &stdout, timeout); err != nil {
+		cancel()
+		return nil, "", nil, fmt.Errorf("failed to start %s: %w [%s]", requirement.Type, err, stdout.String())
+	}
+
+	return cancel, containerName, spec.exportEnv(containerName), nil
+}
+
+func waitReady(ctx context.Context, probe readiness, containerName string, stdout *bytes.Buffer, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
 	for {
 		select {
 		case <-ctx.Done():
-			cancel()
-			return nil, "", nil, ctx.Err()
+			return ctx.Err()
 		case <-time.After(100 * time.Millisecond):
-			if strings.Contains(stdout.String(), "Started.") {
-				started = true
-				break waitStarted
-			}
-			if time.Since(start) > 30*time.Second {
-				break waitStarted
-			}
+		}
+
+		ready, err := checkReady(ctx, probe, containerName, stdout)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for readiness")
 		}
 	}
+}
 
-	if !started {
-		cancel()
-		return nil, "", nil, fmt.Errorf("failed to start Neo4j: [%s]", stdout.String())
+func checkReady(ctx context.Context, probe readiness, containerName string, stdout *bytes.Buffer) (bool, error) {
+	switch {
+	case probe.logContains != "":
+		return strings.Contains(stdout.String(), probe.logContains), nil
+	case probe.tcpPort != 0:
+		hostPort, err := publishedPort(ctx, containerName, probe.tcpPort)
+		if err != nil || hostPort == "" {
+			return false, nil
+		}
+		conn, err := net.DialTimeout("tcp", "127.0.0.1:"+hostPort, time.Second)
+		if err != nil {
+			return false, nil
+		}
+		_ = conn.Close()
+		return true, nil
+	case probe.httpPort != 0:
+		hostPort, err := publishedPort(ctx, containerName, probe.httpPort)
+		if err != nil || hostPort == "" {
+			return false, nil
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://127.0.0.1:%s%s", hostPort, probe.httpPath), nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode >= 200 && resp.StatusCode < 300, nil
+	default:
+		return false, fmt.Errorf("requirement has no readiness probe configured")
+	}
+}
+
+// publishedPort returns the ephemeral host port docker mapped to
+// containerPort on containerName, or "" if it isn't published yet.
+func publishedPort(ctx context.Context, containerName string, containerPort int) (string, error) {
+	out, err := exec.CommandContext(ctx, "docker", "port", containerName, fmt.Sprintf("%d/tcp", containerPort)).CombinedOutput()
+	if err != nil {
+		return "", nil
+	}
+
+	// Output looks like "0.0.0.0:54321\n[::]:54321\n"; take the first mapping.
+	line := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+	idx := strings.LastIndex(line, ":")
+	if idx == -1 {
+		return "", fmt.Errorf("unexpected docker port output: %s", out)
 	}
 
-	return cancel, containerName, []string{"NEO4J_URL=bolt://localhost:7687"}, nil
+	return line[idx+1:], nil
 }
 
 func randString(n int) string {
@@ -32,30 +32,135 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+var _ Client = (*stdioMCPClient)(nil)
+
+// defaultRequestTimeout bounds how long sendRequest waits for a response
+// when the caller's context carries no deadline of its own, so a server
+// that never replies can't wedge a caller forever.
+const defaultRequestTimeout = 60 * time.Second
+
+// defaultBackoff and defaultMaxBackoff seed ClientOptions.Backoff and
+// ClientOptions.MaxBackoff when a caller leaves them unset.
+const (
+	defaultBackoff    = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// stderrTailBytes caps how much of a crashed child's stderr is carried in
+// an EventExit, since a misbehaving server can write far more than is
+// useful for diagnosing why it died.
+const stderrTailBytes = 4 * 1024
+
+// ClientOptions configures how a stdioMCPClient supervises its child
+// process across the life of a session.
+type ClientOptions struct {
+	// RetryLimit caps how many times the child process is respawned after
+	// it exits unexpectedly. Zero (the default) means the client never
+	// reconnects - the first unexpected exit is terminal, matching the
+	// behavior before this option existed.
+	RetryLimit int
+	// Backoff is the delay before the first reconnect attempt. Defaults to
+	// defaultBackoff.
+	Backoff time.Duration
+	// MaxBackoff caps the exponentially increasing delay between later
+	// reconnect attempts. Defaults to defaultMaxBackoff.
+	MaxBackoff time.Duration
+	// OnReconnect, if set, is called after every reconnect attempt with the
+	// 1-based attempt number and the error that attempt produced (nil once
+	// an attempt succeeds).
+	OnReconnect func(attempt int, err error)
+}
+
+// EventType identifies what happened in a stdioMCPClient's Events stream.
+type EventType string
+
+const (
+	// EventStart fires once a child process has been started (including
+	// on every respawn).
+	EventStart EventType = "start"
+	// EventExit fires when a child process exits, whether cleanly (via
+	// Close) or unexpectedly.
+	EventExit EventType = "exit"
+	// EventReconnect fires before each reconnect attempt following an
+	// unexpected exit.
+	EventReconnect EventType = "reconnect"
+)
+
+// Event is a single process lifecycle event, emitted so CI tooling can
+// record a flaky MCP server as part of the audit metadata it writes.
+type Event struct {
+	Type       EventType
+	Attempt    int
+	ExitCode   int
+	StderrTail string
+	Err        error
+	Time       time.Time
+}
+
+// pendingRequest tracks a request awaiting a response so it can be replayed
+// against a freshly respawned child if the original one died first.
+type pendingRequest struct {
+	ctx    context.Context
+	method string
+	params any
+	ch     chan RPCResponse
+}
+
 type stdioMCPClient struct {
 	command string
 	env     []string
 	args    []string
+	opts    ClientOptions
+
+	stdinWriter   io.WriteCloser
+	stdin         sync.Mutex // guards stdinWriter, since encoder.Encode isn't safe for concurrent use and respawns swap the underlying pipe
+	requestID     atomic.Int64
+	responses     sync.Map // int64 -> *pendingRequest
+	notifications sync.Map // string (method) -> func(json.RawMessage)
 
-	stdin       io.WriteCloser
-	requestID   atomic.Int64
-	responses   sync.Map
-	close       func() error
+	rootCtx     context.Context
+	rootCancel  context.CancelFunc
+	closing     atomic.Bool
 	initialized atomic.Bool
+
+	initRequest mcp.InitializeRequest
+	debug       bool
+
+	events chan Event
 }
 
-func newMCPClient(command string, env []string, args ...string) *stdioMCPClient {
+func newMCPClient(command string, env []string, opts ClientOptions, args ...string) *stdioMCPClient {
 	return &stdioMCPClient{
 		command: command,
 		env:     env,
 		args:    args,
+		opts:    opts,
+		events:  make(chan Event, 64),
+	}
+}
+
+// Events returns the channel stdioMCPClient publishes process lifecycle
+// events to - start, exit, and reconnect attempts. It is buffered and
+// never blocks a send, so a caller that doesn't read from it simply misses
+// events rather than stalling the client.
+func (c *stdioMCPClient) Events() <-chan Event {
+	return c.events
+}
+
+func (c *stdioMCPClient) emit(event Event) {
+	event.Time = time.Now()
+	select {
+	case c.events <- event:
+	default:
 	}
 }
 
@@ -63,8 +168,33 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, request mcp.InitializeR
 	if c.initialized.Load() {
 		return nil, fmt.Errorf("client already initialized")
 	}
+	c.initRequest = request
+	c.debug = debug
+	c.rootCtx, c.rootCancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	done, stderr, err := c.spawn()
+	if err != nil {
+		return nil, err
+	}
 
-	ctxCmd, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	result, err := c.handshake(ctx, request, done, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.initialized.Store(true)
+	go c.supervise(done, stderr)
+	return result, nil
+}
+
+// spawn starts the child process, wiring its stdout into a fresh
+// readResponses loop and its stdin into subsequent writeMessage calls. The
+// returned channel receives the process's Wait error exactly once, after it
+// exits; ctxCmd is derived from c.rootCtx, so Close (or the original
+// Initialize context) terminates whichever generation of the process is
+// currently running.
+func (c *stdioMCPClient) spawn() (<-chan error, *bytes.Buffer, error) {
+	ctxCmd, cancel := context.WithCancel(c.rootCtx)
 	cmd := exec.CommandContext(ctxCmd, c.command, c.args...)
 	cmd.Env = c.env
 	cmd.Cancel = func() error {
@@ -72,7 +202,7 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, request mcp.InitializeR
 	}
 
 	var stderr bytes.Buffer
-	if debug {
+	if c.debug {
 		cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
 	} else {
 		cmd.Stderr = &stderr
@@ -81,82 +211,215 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, request mcp.InitializeR
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		cancel()
-		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+		return nil, nil, fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
-	c.stdin = stdin
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		cancel()
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	if err := cmd.Start(); err != nil {
 		cancel()
-		return nil, fmt.Errorf("failed to start command: %w", err)
+		return nil, nil, fmt.Errorf("failed to start command: %w", err)
 	}
 
-	c.close = func() error {
-		cancel()
-		return nil
-	}
+	c.stdin.Lock()
+	c.stdinWriter = stdin
+	c.stdin.Unlock()
+
+	go c.readResponses(bufio.NewReader(stdout))
+
+	done := make(chan error, 1)
 	go func() {
-		cmd.Wait()
+		done <- cmd.Wait()
 		cancel()
 	}()
-	go func() {
-		c.readResponses(bufio.NewReader(stdout))
-	}()
 
-	var result mcp.InitializeResult
-	errs := make(chan error)
+	c.emit(Event{Type: EventStart})
+	return done, &stderr, nil
+}
+
+// handshake runs the initialize request/response and the follow-up
+// notifications/initialized notification against whichever process spawn
+// most recently started, racing it against that process dying before the
+// handshake completes.
+func (c *stdioMCPClient) handshake(ctx context.Context, request mcp.InitializeRequest, done <-chan error, stderr *bytes.Buffer) (*mcp.InitializeResult, error) {
+	params := struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		ClientInfo      mcp.Implementation     `json:"clientInfo"`
+		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+	}{
+		ProtocolVersion: request.Params.ProtocolVersion,
+		ClientInfo:      request.Params.ClientInfo,
+		Capabilities:    request.Params.Capabilities,
+	}
+
+	type outcome struct {
+		result *mcp.InitializeResult
+		err    error
+	}
+	resultCh := make(chan outcome, 1)
 	go func() {
-		<-ctxCmd.Done()
-		errs <- errors.New(stderr.String())
+		response, err := c.sendRequest(ctx, "initialize", params)
+		if err != nil {
+			resultCh <- outcome{err: err}
+			return
+		}
+
+		var result mcp.InitializeResult
+		if err := json.Unmarshal(*response, &result); err != nil {
+			resultCh <- outcome{err: fmt.Errorf("failed to unmarshal response: %w", err)}
+			return
+		}
+
+		if err := c.Notify("notifications/initialized", nil); err != nil {
+			resultCh <- outcome{err: fmt.Errorf("failed to send initialized notification: %w", err)}
+			return
+		}
+
+		resultCh <- outcome{result: &result}
 	}()
-	go func() {
-		errs <- func() error {
-			params := struct {
-				ProtocolVersion string                 `json:"protocolVersion"`
-				ClientInfo      mcp.Implementation     `json:"clientInfo"`
-				Capabilities    mcp.ClientCapabilities `json:"capabilities"`
-			}{
-				ProtocolVersion: request.Params.ProtocolVersion,
-				ClientInfo:      request.Params.ClientInfo,
-				Capabilities:    request.Params.Capabilities,
-			}
 
-			response, err := c.sendRequest(ctx, "initialize", params)
+	select {
+	case <-done:
+		return nil, errors.New(strings.TrimSpace(stderr.String()))
+	case out := <-resultCh:
+		return out.result, out.err
+	}
+}
+
+// supervise watches the child process for the rest of the session,
+// respawning it with capped exponential backoff (per c.opts) after an
+// unexpected exit - one not triggered by Close - up to c.opts.RetryLimit
+// attempts. Each successful reconnect re-runs the cached initialize
+// handshake and replays any request still awaiting a response whose
+// context hasn't expired.
+func (c *stdioMCPClient) supervise(done <-chan error, stderr *bytes.Buffer) {
+	for {
+		exitErr := <-done
+
+		exitCode := 0
+		var exitError *exec.ExitError
+		if errors.As(exitErr, &exitError) {
+			exitCode = exitError.ExitCode()
+		}
+		c.emit(Event{Type: EventExit, ExitCode: exitCode, StderrTail: stderrTail(stderr), Err: exitErr})
+
+		if c.closing.Load() {
+			return
+		}
+
+		var reconnectErr error
+		reconnected := false
+		for attempt := 1; attempt <= c.opts.RetryLimit; attempt++ {
+			c.emit(Event{Type: EventReconnect, Attempt: attempt})
+			time.Sleep(backoffDelay(c.opts, attempt))
+
+			newDone, newStderr, err := c.spawn()
+			if err == nil {
+				_, err = c.handshake(context.Background(), c.initRequest, newDone, newStderr)
+			}
+			if c.opts.OnReconnect != nil {
+				c.opts.OnReconnect(attempt, err)
+			}
 			if err != nil {
-				return err
+				reconnectErr = err
+				continue
 			}
 
-			if err := json.Unmarshal(*response, &result); err != nil {
-				return fmt.Errorf("failed to unmarshal response: %w", err)
-			}
+			c.replayPending()
+			done, stderr = newDone, newStderr
+			reconnected = true
+			break
+		}
 
-			encoder := json.NewEncoder(stdin)
-			if err := encoder.Encode(mcp.JSONRPCNotification{
-				JSONRPC: mcp.JSONRPC_VERSION,
-				Notification: mcp.Notification{
-					Method: "notifications/initialized",
-				},
-			}); err != nil {
-				return fmt.Errorf("failed to marshal initialized notification: %w", err)
-			}
+		if !reconnected {
+			c.failPending(fmt.Errorf("mcp: %s did not recover after %d reconnect attempt(s): %w", c.command, c.opts.RetryLimit, reconnectErr))
+			return
+		}
+	}
+}
 
-			c.initialized.Store(true)
-			return nil
-		}()
-	}()
+// backoffDelay returns the delay before reconnect attempt (1-based),
+// doubling from opts.Backoff and capped at opts.MaxBackoff.
+func backoffDelay(opts ClientOptions, attempt int) time.Duration {
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	maxBackoff := opts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
 
-	return &result, <-errs
+	delay := backoff << (attempt - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// stderrTail returns the trailing stderrTailBytes of buf, trimmed of
+// surrounding whitespace, for inclusion in an EventExit.
+func stderrTail(buf *bytes.Buffer) string {
+	data := buf.Bytes()
+	if len(data) > stderrTailBytes {
+		data = data[len(data)-stderrTailBytes:]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// replayPending re-sends every request still awaiting a response, under
+// its original id, against the newly respawned child - unless its context
+// has already expired, in which case it's simply dropped.
+func (c *stdioMCPClient) replayPending() {
+	c.responses.Range(func(key, value any) bool {
+		id := key.(int64)
+		pending := value.(*pendingRequest)
+		if pending.ctx.Err() != nil {
+			c.responses.Delete(id)
+			return true
+		}
+
+		_ = c.writeMessage(mcp.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      id,
+			Request: mcp.Request{
+				Method: pending.method,
+			},
+			Params: pending.params,
+		})
+		return true
+	})
+}
+
+// failPending delivers err to every request still awaiting a response,
+// once the supervisor has given up reconnecting - otherwise those callers
+// would block until their own context's deadline instead of learning
+// promptly that the session is gone for good.
+func (c *stdioMCPClient) failPending(err error) {
+	message := err.Error()
+	c.responses.Range(func(key, value any) bool {
+		pending := value.(*pendingRequest)
+		c.responses.Delete(key)
+		pending.ch <- RPCResponse{Error: &message}
+		return true
+	})
 }
 
 func (c *stdioMCPClient) Close() error {
-	return c.close()
+	c.closing.Store(true)
+	c.rootCancel()
+	return nil
 }
 
+// readResponses pumps incoming messages off stdout for the lifetime of the
+// child process, delivering replies to whichever sendRequest call is
+// waiting on them and routing server-initiated notifications - progress,
+// logging, notifications/cancelled - to whatever OnNotification handler is
+// registered for that method, if any.
 func (c *stdioMCPClient) readResponses(stdout *bufio.Reader) error {
 	for {
 		buf, err := stdout.ReadBytes('\n')
@@ -170,18 +433,24 @@ func (c *stdioMCPClient) readResponses(stdout *bufio.Reader) error {
 		}
 
 		if baseMessage.ID == nil {
+			if baseMessage.Method == "" {
+				continue
+			}
+			if fn, ok := c.notifications.Load(baseMessage.Method); ok {
+				fn.(func(json.RawMessage))(baseMessage.Params)
+			}
 			continue
 		}
 
 		if ch, ok := c.responses.LoadAndDelete(*baseMessage.ID); ok {
-			responseChan := ch.(chan RPCResponse)
+			pending := ch.(*pendingRequest)
 
 			if baseMessage.Error != nil {
-				responseChan <- RPCResponse{
+				pending.ch <- RPCResponse{
 					Error: &baseMessage.Error.Message,
 				}
 			} else {
-				responseChan <- RPCResponse{
+				pending.ch <- RPCResponse{
 					Response: &baseMessage.Result,
 				}
 			}
@@ -189,13 +458,62 @@ func (c *stdioMCPClient) readResponses(stdout *bufio.Reader) error {
 	}
 }
 
+// OnNotification registers fn to be called with the params of every
+// server-initiated notification received for method, replacing any handler
+// previously registered for it. There is no way to unregister a handler;
+// callers that need to stop reacting should ignore the call within fn.
+func (c *stdioMCPClient) OnNotification(method string, fn func(params json.RawMessage)) {
+	c.notifications.Store(method, fn)
+}
+
+// jsonrpcNotification is the wire envelope for a client-to-server
+// notification. mcp.JSONRPCNotification can't carry arbitrary params (its
+// embedded Notification.Params only marshals "_meta" and similar metadata),
+// so notifications with a real payload - notifications/cancelled's
+// requestId, for instance - are built with this instead.
+type jsonrpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// Notify sends a one-way, client-to-server JSON-RPC notification - one the
+// server never replies to, such as notifications/initialized or
+// notifications/cancelled.
+func (c *stdioMCPClient) Notify(method string, params any) error {
+	return c.writeMessage(jsonrpcNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// writeMessage encodes message and writes it to the child process' stdin,
+// guarded by c.stdin so concurrent sendRequest/Notify calls don't race on
+// the shared encoder, and so a respawn swapping in a new stdin pipe can't
+// interleave with an in-flight write.
+func (c *stdioMCPClient) writeMessage(message any) error {
+	c.stdin.Lock()
+	defer c.stdin.Unlock()
+
+	return json.NewEncoder(c.stdinWriter).Encode(message)
+}
+
+// sendRequest issues a JSON-RPC request and waits for its response. If ctx
+// carries no earlier deadline, defaultRequestTimeout applies so a server
+// that never replies can't block the caller forever. If ctx is cancelled or
+// times out before a response arrives, sendRequest notifies the server with
+// notifications/cancelled and cleans up the pending response entry so a
+// late reply doesn't leak it.
 func (c *stdioMCPClient) sendRequest(ctx context.Context, method string, params any) (*json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
 	id := c.requestID.Add(1)
-	responseChan := make(chan RPCResponse, 1)
-	c.responses.Store(id, responseChan)
+	pending := &pendingRequest{ctx: ctx, method: method, params: params, ch: make(chan RPCResponse, 1)}
+	c.responses.Store(id, pending)
 
-	encoder := json.NewEncoder(c.stdin)
-	if err := encoder.Encode(mcp.JSONRPCRequest{
+	if err := c.writeMessage(mcp.JSONRPCRequest{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		ID:      id,
 		Request: mcp.Request{
@@ -203,13 +521,21 @@ func (c *stdioMCPClient) sendRequest(ctx context.Context, method string, params
 		},
 		Params: params,
 	}); err != nil {
+		c.responses.Delete(id)
 		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
 	select {
 	case <-ctx.Done():
+		c.responses.Delete(id)
+		if method != "initialize" {
+			notification := mcp.CancelledNotification{}
+			notification.Params.RequestId = id
+			notification.Params.Reason = ctx.Err().Error()
+			_ = c.Notify("notifications/cancelled", notification.Params)
+		}
 		return nil, ctx.Err()
-	case response := <-responseChan:
+	case response := <-pending.ch:
 		if response.Error != nil {
 			return nil, errors.New(*response.Error)
 		}
@@ -22,7 +22,24 @@ THE SOFTWARE.
 
 package mcp
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Client is the protocol-level contract both stdioMCPClient and
+// httpMCPClient satisfy, so code that only needs to speak MCP - as opposed
+// to also managing a container or process - can be written against either
+// transport.
+type Client interface {
+	Initialize(ctx context.Context, request mcp.InitializeRequest, debug bool) (*mcp.InitializeResult, error)
+	ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error)
+	ListPrompts(ctx context.Context, request mcp.ListPromptsRequest) (*mcp.ListPromptsResult, error)
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	Close() error
+}
 
 type RPCResponse struct {
 	Error    *string
@@ -33,6 +50,7 @@ type BaseMessage struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      *int64          `json:"id,omitempty"`
 	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *struct {
 		Code    int    `json:"code"`
@@ -66,3 +84,35 @@ type ToolAnnotations struct {
 type Items struct {
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
 }
+
+type Prompt struct {
+	Name        string           `json:"name" yaml:"name"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// Capabilities records which optional features a running server
+// advertised in its initialize response. Each field mirrors the presence
+// (not the sub-options) of the corresponding entry in mcp.ServerCapabilities.
+type Capabilities struct {
+	Logging   bool `json:"logging,omitempty" yaml:"logging,omitempty"`
+	Prompts   bool `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+	Resources bool `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Tools     bool `json:"tools,omitempty" yaml:"tools,omitempty"`
+}
+
+// Inventory is the full surface a server advertised during a single probe
+// session - capabilities plus its tool and prompt listings - bundled
+// together so a caller that wants more than ListTools doesn't have to
+// launch the server a second time just to also call ListPrompts.
+type Inventory struct {
+	Capabilities Capabilities `json:"capabilities"`
+	Tools        []Tool       `json:"tools,omitempty"`
+	Prompts      []Prompt     `json:"prompts,omitempty"`
+}
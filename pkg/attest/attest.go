@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package attest signs a just-built image digest with cosign, in keyless
+// (Fulcio/OIDC) or key-file mode, the same way `task build` would have a
+// human run `cosign sign` by hand afterwards. It shells out to the cosign
+// CLI rather than linking its Go module, matching pkg/signing's
+// verification side and how the rest of this repository drives docker and
+// git. SBOM and SLSA provenance attestation is produced by BuildKit itself
+// at build time (see pkg/builder.Options.Attest); this package only signs
+// the resulting digest and, for callers that want it recorded elsewhere,
+// reports back who signed it by asking pkg/signing to verify what it just
+// wrote.
+package attest
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/docker/mcp-registry/pkg/signing"
+)
+
+// Options configures a Sign call.
+type Options struct {
+	// KeyPath is a cosign private key file (or KMS URI) to sign with. Empty
+	// selects keyless signing against the public Fulcio CA and Rekor
+	// transparency log, the same as running `cosign sign --yes` with no
+	// --key.
+	KeyPath string
+	// KeyPassword is the password for KeyPath, fed to cosign over stdin so
+	// it never appears in argv or the environment. Ignored in keyless mode.
+	KeyPassword string
+}
+
+// SignatureRef is a signature cosign attached to an image, ready to be
+// recorded alongside it (e.g. in catalog.Tile.Signatures).
+type SignatureRef struct {
+	// Identity is the signer's certificate subject (keyless) or key
+	// identity (key-file).
+	Identity string `json:"identity,omitempty" yaml:"identity,omitempty"`
+	// Issuer is the OIDC issuer that vouched for Identity in keyless mode,
+	// empty in key-file mode.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+}
+
+// Sign signs ref (an image reference, normally pinned to the digest BuildKit
+// just produced) with cosign and returns the resulting signature(s), as
+// reported by verifying what was just written. It's safe to call
+// repeatedly: cosign signing the same digest twice just adds another
+// signature.
+func Sign(ctx context.Context, ref string, opts Options) ([]SignatureRef, error) {
+	args := []string{"sign", "--yes"}
+	if opts.KeyPath != "" {
+		args = append(args, "--key", opts.KeyPath)
+	}
+	args = append(args, ref)
+
+	cmd := exec.CommandContext(ctx, "cosign", args...)
+	if opts.KeyPath != "" && opts.KeyPassword != "" {
+		cmd.Env = append(cmd.Environ(), "COSIGN_PASSWORD="+opts.KeyPassword)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("attest: cosign sign %s: %w: %s", ref, err, out)
+	}
+
+	var trustedKeys []string
+	if opts.KeyPath != "" {
+		trustedKeys = []string{opts.KeyPath}
+	}
+
+	result, err := signing.Verify(ctx, ref, trustedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("attest: verifying signature just written for %s: %w", ref, err)
+	}
+
+	refs := make([]SignatureRef, 0, len(result.Signers))
+	for _, signer := range result.Signers {
+		refs = append(refs, SignatureRef{Identity: signer.Identity, Issuer: signer.Issuer})
+	}
+	return refs, nil
+}
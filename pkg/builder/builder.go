@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package builder builds and pulls the images `task build` produces. It
+// talks to BuildKit directly over its Go client so build status can be
+// surfaced structurally (see Event) instead of shelling out to the docker
+// CLI and scraping its terminal output, with a thin CLI-driven fallback
+// for environments that have no buildkitd to dial - a local `docker buildx`
+// install, but no daemon reachable at BUILDKIT_HOST.
+package builder
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// Driver selects how Build and Pull talk to the builder/registry.
+type Driver string
+
+const (
+	// DriverBuildKit dials buildkitd directly (via pkg/client) and pulls
+	// images through the moby/moby API client. It's the default: no
+	// docker CLI is required, and build events stream through Options.Events
+	// structurally rather than as scraped terminal output.
+	DriverBuildKit Driver = "buildkit"
+	// DriverCLI shells out to `docker buildx build` and `docker pull`, the
+	// way this package worked before BuildKit was wired in directly. Use it
+	// where neither a local buildkitd nor BUILDKIT_HOST is available.
+	DriverCLI Driver = "cli"
+)
+
+// Options configures a Build or Pull call.
+type Options struct {
+	// Driver selects the implementation. The zero value is DriverBuildKit.
+	Driver Driver
+	// BuildKitHost is the buildkitd endpoint to dial, e.g.
+	// "tcp://buildkitd:1234" or "docker-container://buildx_buildkit_mybuilder0".
+	// It defaults to the BUILDKIT_HOST environment variable, then to the
+	// local "docker-container://buildx_buildkit_default" driver, so CI
+	// workers can point every build at a shared remote buildkitd by setting
+	// BUILDKIT_HOST without any other plumbing.
+	BuildKitHost string
+	// GitHubToken is fed to the build as the GIT_AUTH_TOKEN secret so a
+	// server's Dockerfile can `git clone` private submodules or sibling
+	// repositories during the build. It's ignored by DriverCLI's pull path.
+	GitHubToken string
+	// Events receives structural build status as the build progresses. A
+	// nil Events discards them; callers that want terminal output render
+	// them with Render instead of leaving this nil.
+	Events chan<- *Event
+	// Attest has BuildKit generate and attach an SBOM and max-mode SLSA
+	// provenance attestation to the built image (BuildKit's own
+	// `--attest=type=sbom` / `type=provenance,mode=max`). DriverCLI ignores
+	// it: the docker CLI fallback predates attestation support here.
+	Attest bool
+}
+
+// Build builds server's image, tagging it "check" and server.Image, the
+// same as `docker buildx build -t check -t <image> ...` did before this
+// package existed. It returns the built image's resolved digest, so
+// callers can sign or attest that exact content (see pkg/attest) rather
+// than a mutable tag.
+func Build(ctx context.Context, server servers.Server, opts Options) (string, error) {
+	switch opts.Driver {
+	case DriverCLI:
+		return cliBuild(ctx, server, opts)
+	case DriverBuildKit, "":
+		return buildkitBuild(ctx, server, opts)
+	default:
+		return "", fmt.Errorf("builder: unknown driver %q", opts.Driver)
+	}
+}
+
+// Pull pulls server's (community, non-mcp/ namespaced) image, logging in to
+// its registry first if credentials are configured for it.
+func Pull(ctx context.Context, server servers.Server, opts Options) error {
+	switch opts.Driver {
+	case DriverCLI:
+		return cliPull(ctx, server)
+	case DriverBuildKit, "":
+		return clientPull(ctx, server)
+	default:
+		return fmt.Errorf("builder: unknown driver %q", opts.Driver)
+	}
+}
+
+// buildKitHost resolves the buildkitd endpoint to dial: opts.BuildKitHost,
+// then $BUILDKIT_HOST, then the local buildx "default" builder's container,
+// matching how `docker buildx build` itself picks a builder.
+func buildKitHost(opts Options) string {
+	if opts.BuildKitHost != "" {
+		return opts.BuildKitHost
+	}
+	if host := os.Getenv("BUILDKIT_HOST"); host != "" {
+		return host
+	}
+	return "docker-container://buildx_buildkit_default"
+}
@@ -0,0 +1,37 @@
+package builder
+
+import "testing"
+
+func TestGitRemote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://github.com/docker/mcp-registry", want: "github.com/docker/mcp-registry"},
+		{in: "http://gitlab.example.com/org/repo", want: "gitlab.example.com/org/repo"},
+		{in: "github.com/docker/mcp-registry", want: "github.com/docker/mcp-registry"},
+	}
+
+	for _, tt := range tests {
+		if got := gitRemote(tt.in); got != tt.want {
+			t.Errorf("gitRemote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildKitHost(t *testing.T) {
+	t.Setenv("BUILDKIT_HOST", "")
+
+	if got, want := buildKitHost(Options{}), "docker-container://buildx_buildkit_default"; got != want {
+		t.Errorf("buildKitHost(Options{}) = %q, want %q", got, want)
+	}
+
+	if got, want := buildKitHost(Options{BuildKitHost: "tcp://buildkitd:1234"}), "tcp://buildkitd:1234"; got != want {
+		t.Errorf("buildKitHost with explicit host = %q, want %q", got, want)
+	}
+
+	t.Setenv("BUILDKIT_HOST", "tcp://ci-buildkitd:1234")
+	if got, want := buildKitHost(Options{}), "tcp://ci-buildkitd:1234"; got != want {
+		t.Errorf("buildKitHost with $BUILDKIT_HOST = %q, want %q", got, want)
+	}
+}
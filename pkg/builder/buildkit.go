@@ -0,0 +1,175 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// buildkitBuild builds server's image by dialing buildkitd directly and
+// solving a dockerfile.v0 frontend request whose build context is an
+// llb.Git source, rather than shelling out to `docker buildx build` with a
+// string-concatenated "owner/repo.git#branch:dir" context URL. It returns
+// the built image's digest, as reported in the solve response's
+// "containerimage.digest" exporter field.
+func buildkitBuild(ctx context.Context, server servers.Server, opts Options) (string, error) {
+	c, err := client.New(ctx, buildKitHost(opts))
+	if err != nil {
+		return "", fmt.Errorf("builder: dialing buildkitd at %s: %w", buildKitHost(opts), err)
+	}
+	defer c.Close()
+
+	projectURL := server.Source.Project
+	branch := server.Source.Branch
+
+	gh := github.New()
+	repository, err := gh.GetProjectRepository(ctx, projectURL)
+	if err != nil {
+		return "", err
+	}
+	if branch == "" {
+		branch = repository.GetDefaultBranch()
+	}
+
+	sha, err := gh.GetCommitSHA1(ctx, projectURL, branch)
+	if err != nil {
+		return "", err
+	}
+
+	gitOpts := []llb.GitOption{llb.WithCustomName("Git context for " + server.Image)}
+	if opts.GitHubToken != "" {
+		gitOpts = append(gitOpts, llb.AuthTokenSecret("GIT_AUTH_TOKEN"))
+	}
+	contextState := llb.Git(gitRemote(projectURL), sha, gitOpts...)
+
+	dockerfile := server.GetDockerfile()
+	if dir := server.Source.Directory; dir != "" && dir != "." {
+		dockerfile = path.Join(dir, dockerfile)
+	}
+
+	solveOpt := client.SolveOpt{
+		Frontend: "dockerfile.v0",
+		FrontendAttrs: map[string]string{
+			"filename": dockerfile,
+		},
+		FrontendInputs: map[string]llb.State{
+			"context": contextState,
+		},
+		Exports: []client.ExportEntry{{
+			Type: client.ExporterImage,
+			Attrs: map[string]string{
+				"name":   strings.Join([]string{"check", server.Image}, ","),
+				"unpack": "true",
+				fmt.Sprintf("label:%s", specs.AnnotationRevision): sha,
+			},
+		}},
+	}
+
+	if opts.GitHubToken != "" {
+		solveOpt.Session = []session.Attachable{
+			secretsprovider.FromMap(map[string][]byte{
+				"GIT_AUTH_TOKEN": []byte(opts.GitHubToken),
+			}),
+		}
+	}
+
+	if opts.Attest {
+		solveOpt.FrontendAttrs["attest:sbom"] = ""
+		solveOpt.FrontendAttrs["attest:provenance"] = "mode=max"
+	}
+
+	events := make(chan *client.SolveStatus)
+	type solveResult struct {
+		res *client.SolveResponse
+		err error
+	}
+	results := make(chan solveResult, 1)
+	go func() {
+		res, err := c.Solve(ctx, nil, solveOpt, events)
+		results <- solveResult{res, err}
+	}()
+
+	for ev := range events {
+		if opts.Events != nil {
+			opts.Events <- ev
+		}
+	}
+	if opts.Events != nil {
+		close(opts.Events)
+	}
+
+	result := <-results
+	if result.err != nil {
+		return "", result.err
+	}
+	return result.res.ExporterResponse["containerimage.digest"], nil
+}
+
+// gitRemote strips projectURL's scheme, since llb.Git resolves a bare
+// "host/path" remote the same way a Dockerfile's `FROM git://...` would,
+// and prepending a scheme it already infers just duplicates it in build
+// cache keys.
+func gitRemote(projectURL string) string {
+	remote := strings.TrimPrefix(projectURL, "https://")
+	remote = strings.TrimPrefix(remote, "http://")
+	return remote
+}
+
+// clientPull pulls server's image through the moby/moby API client rather
+// than shelling out to `docker pull`, logging in to its registry first if
+// credentials are configured for it.
+func clientPull(ctx context.Context, server servers.Server) error {
+	if err := loginForPrivateRegistry(ctx, server.Image); err != nil {
+		fmt.Println("[WARNING]", err, "- attempting to pull without logging in")
+	}
+
+	cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("builder: creating docker client: %w", err)
+	}
+	defer cli.Close()
+
+	rc, err := cli.ImagePull(ctx, server.Image, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("builder: pulling %s: %w", server.Image, err)
+	}
+	defer rc.Close()
+
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/credentials"
+	"github.com/docker/mcp-registry/pkg/execenv"
+	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/reference"
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// cliBuild builds server's image by shelling out to `docker buildx build`,
+// the way this package worked before it talked to BuildKit directly. It's
+// the DriverCLI fallback for environments with no buildkitd to dial. It
+// returns the built image's digest, read back from the --metadata-file
+// buildx writes alongside the build.
+func cliBuild(ctx context.Context, server servers.Server, opts Options) (string, error) {
+	client := github.New()
+
+	projectURL := server.Source.Project
+	branch := server.Source.Branch
+
+	repository, err := client.GetProjectRepository(ctx, projectURL)
+	if err != nil {
+		return "", err
+	}
+	if branch == "" {
+		branch = repository.GetDefaultBranch()
+	}
+
+	sha, err := client.GetCommitSHA1(ctx, projectURL, branch)
+	if err != nil {
+		return "", err
+	}
+
+	gitURL := projectURL + ".git#"
+	if branch != "" {
+		gitURL += branch
+	}
+	if dir := server.Source.Directory; dir != "" && dir != "." {
+		gitURL += ":" + dir
+	}
+
+	metadataFile, err := os.CreateTemp("", "mcp-build-metadata-*.json")
+	if err != nil {
+		return "", fmt.Errorf("builder: creating metadata file: %w", err)
+	}
+	metadataFile.Close()
+	defer os.Remove(metadataFile.Name())
+
+	args := []string{"buildx", "build", "-f", server.GetDockerfile(), "-t", "check", "-t", server.Image, "--label", "org.opencontainers.image.revision=" + sha, "--metadata-file", metadataFile.Name()}
+
+	var cmd *exec.Cmd
+	if opts.GitHubToken != "" {
+		args = append(args, "--secret", "id=GIT_AUTH_TOKEN")
+		cmd = exec.CommandContext(ctx, "docker", append(args, gitURL)...)
+		cmd.Env = execenv.Build(execenv.Config{}, "GIT_AUTH_TOKEN="+opts.GitHubToken)
+	} else {
+		cmd = exec.CommandContext(ctx, "docker", append(args, gitURL)...)
+		cmd.Env = execenv.Build(execenv.Config{})
+	}
+
+	cmd.Dir = os.TempDir()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	return readDigestFromMetadataFile(metadataFile.Name())
+}
+
+// readDigestFromMetadataFile reads the "containerimage.digest" field
+// `docker buildx build --metadata-file` writes out on a successful build.
+func readDigestFromMetadataFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("builder: reading build metadata: %w", err)
+	}
+
+	var metadata struct {
+		Digest string `json:"containerimage.digest"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return "", fmt.Errorf("builder: parsing build metadata: %w", err)
+	}
+	return metadata.Digest, nil
+}
+
+// cliPull pulls server's image by shelling out to `docker pull`.
+func cliPull(ctx context.Context, server servers.Server) error {
+	if err := loginForPrivateRegistry(ctx, server.Image); err != nil {
+		fmt.Println("[WARNING]", err, "- attempting to pull without logging in")
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", "pull", server.Image)
+	cmd.Env = execenv.Build(execenv.Config{})
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// loginForPrivateRegistry resolves credentials for image's registry from
+// the docker credential helper configured on this machine (if any) and
+// logs in, so pulling an image from a private registry doesn't require
+// plumbing a separate secret through this tool.
+func loginForPrivateRegistry(ctx context.Context, image string) error {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return err
+	}
+	registry := ref.Normalize().Domain
+
+	creds, err := credentials.LookupDocker(registry)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", "login", registry, "--username", creds.Username, "--password-stdin")
+	cmd.Stdin = strings.NewReader(creds.Secret)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
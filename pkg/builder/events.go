@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/progress/progressui"
+)
+
+// Event is a single build-status update: a vertex (a build step) starting
+// or completing, a log line from a running vertex, or a progress update for
+// a long-running transfer. It's a thin re-export of *client.SolveStatus so
+// callers that only want to render it (Render, or their own JSON encoder)
+// never need to import buildkit themselves.
+type Event = client.SolveStatus
+
+// Render drains events and writes them to w as they arrive, using
+// buildkit's own progressui so DriverCLI and DriverBuildKit builds look the
+// same in a terminal. mode is one of progressui's display modes ("auto",
+// "plain", "tty", "quiet"); "" defaults to "auto". events is bidirectional
+// (rather than receive-only) because progressui.Display.UpdateFrom reads
+// directly off the channel Render is handed.
+func Render(w io.Writer, events chan *Event, mode string) error {
+	if mode == "" {
+		mode = "auto"
+	}
+
+	display, err := progressui.NewDisplay(w, progressui.DisplayMode(mode))
+	if err != nil {
+		return fmt.Errorf("builder: creating progress display: %w", err)
+	}
+
+	_, err = display.UpdateFrom(context.Background(), events)
+	return err
+}
+
+// RenderJSON drains events and writes one JSON object per line to w, for
+// callers that want to parse build progress themselves (e.g. a CI job
+// annotating a PR) instead of rendering it for a terminal.
+func RenderJSON(w io.Writer, events <-chan *Event) error {
+	enc := json.NewEncoder(w)
+	for ev := range events {
+		if err := enc.Encode(jsonEvent{
+			Vertexes:  ev.Vertexes,
+			Statuses:  ev.Statuses,
+			Logs:      ev.Logs,
+			Warnings:  ev.Warnings,
+			Timestamp: time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("builder: encoding build event: %w", err)
+		}
+	}
+	return nil
+}
+
+// jsonEvent mirrors client.SolveStatus, adding a wall-clock Timestamp since
+// SolveStatus's own vertex/log timestamps are only meaningful relative to
+// each other.
+type jsonEvent struct {
+	Vertexes  []*client.Vertex        `json:"vertexes,omitempty"`
+	Statuses  []*client.VertexStatus  `json:"statuses,omitempty"`
+	Logs      []*client.VertexLog     `json:"logs,omitempty"`
+	Warnings  []*client.VertexWarning `json:"warnings,omitempty"`
+	Timestamp time.Time               `json:"timestamp"`
+}
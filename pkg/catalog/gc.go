@@ -0,0 +1,201 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/hub"
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// GCReport is the result of a mark-and-sweep pass over catalogsDir: every
+// path GC found with no live server.yaml rooting it, grouped the way
+// `cmd/catalog-gc` reports and removes them.
+type GCReport struct {
+	// OrphanedCatalogs are catalogs/<name> directories (cmd/catalog's
+	// per-server output) with no corresponding servers/<name>.
+	OrphanedCatalogs []string
+	// OrphanedArtifacts are cached tool/readme files under
+	// catalogs/v<Version>/{tools,readme}/ that no live server references.
+	OrphanedArtifacts []string
+}
+
+// Empty reports whether GC found nothing to remove.
+func (r GCReport) Empty() bool {
+	return len(r.OrphanedCatalogs) == 0 && len(r.OrphanedArtifacts) == 0
+}
+
+// GC walks serversDir for the set of live server names and sweeps
+// catalogsDir for anything that set doesn't root: a per-server catalog
+// directory (catalogs/<name>/), or a cached tools/readme artifact
+// (catalogs/v<Version>/{tools,readme}/<name>.{json,md}). It's read-only;
+// callers remove the paths it reports (see cmd/catalog-gc's --delete).
+func GC(serversDir, catalogsDir string) (GCReport, error) {
+	live, err := liveServerNames(serversDir)
+	if err != nil {
+		return GCReport{}, err
+	}
+
+	var report GCReport
+
+	perServerDirs, err := os.ReadDir(catalogsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return GCReport{}, fmt.Errorf("catalog: reading %s: %w", catalogsDir, err)
+	}
+
+	versionPrefix := "v" + strconv.Itoa(Version)
+	for _, entry := range perServerDirs {
+		if !entry.IsDir() {
+			continue
+		}
+		if entry.Name() == versionPrefix {
+			artifacts, err := orphanedArtifacts(filepath.Join(catalogsDir, entry.Name()), live)
+			if err != nil {
+				return GCReport{}, err
+			}
+			report.OrphanedArtifacts = append(report.OrphanedArtifacts, artifacts...)
+			continue
+		}
+		if !live[entry.Name()] {
+			report.OrphanedCatalogs = append(report.OrphanedCatalogs, filepath.Join(catalogsDir, entry.Name()))
+		}
+	}
+
+	sort.Strings(report.OrphanedCatalogs)
+	sort.Strings(report.OrphanedArtifacts)
+	return report, nil
+}
+
+// OrphanedImages returns the repositories under namespace on Docker Hub
+// (e.g. "mcp") that no server.yaml in serversDir declares as its image, so
+// `cmd/catalog-gc --images` can flag them for pruning.
+func OrphanedImages(ctx context.Context, serversDir, namespace string) ([]string, error) {
+	declared, err := declaredImages(serversDir)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := hub.ListRepositories(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []string
+	for _, repo := range repos {
+		image := namespace + "/" + repo.Name
+		if !declared[image] {
+			orphaned = append(orphaned, image)
+		}
+	}
+
+	sort.Strings(orphaned)
+	return orphaned, nil
+}
+
+// declaredImages reads every servers/<name>/server.yaml under serversDir
+// and returns the set of images they declare.
+func declaredImages(serversDir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(serversDir)
+	if err != nil {
+		return nil, fmt.Errorf("catalog: reading %s: %w", serversDir, err)
+	}
+
+	declared := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(serversDir, entry.Name(), "server.yaml")
+		server, err := servers.Read(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("catalog: reading %s: %w", path, err)
+		}
+		if server.Image != "" {
+			declared[server.Image] = true
+		}
+	}
+	return declared, nil
+}
+
+// liveServerNames returns the set of server names with a servers/<name>/server.yaml.
+func liveServerNames(serversDir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(serversDir)
+	if err != nil {
+		return nil, fmt.Errorf("catalog: reading %s: %w", serversDir, err)
+	}
+
+	live := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(serversDir, entry.Name(), "server.yaml")); err == nil {
+			live[entry.Name()] = true
+		}
+	}
+	return live, nil
+}
+
+// orphanedArtifacts walks versionDir's tools/ and readme/ subdirectories
+// (catalogs/v<Version>/tools/<name>.json, catalogs/v<Version>/readme/<name>.md)
+// and returns the ones whose <name> isn't in live.
+func orphanedArtifacts(versionDir string, live map[string]bool) ([]string, error) {
+	var orphaned []string
+
+	for _, sub := range []string{"tools", "readme"} {
+		dir := filepath.Join(versionDir, sub)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("catalog: reading %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if !live[name] {
+				orphaned = append(orphaned, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	return orphaned, nil
+}
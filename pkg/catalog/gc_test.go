@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestGC(t *testing.T) {
+	root := t.TempDir()
+	serversDir := filepath.Join(root, "servers")
+	catalogsDir := filepath.Join(root, "catalogs")
+
+	mkServer(t, serversDir, "fetch")
+	mkCatalogDir(t, catalogsDir, "fetch")
+	mkCatalogDir(t, catalogsDir, "removed-server")
+
+	versionDir := filepath.Join(catalogsDir, "v"+strconv.Itoa(Version))
+	mkArtifact(t, versionDir, "tools", "fetch.json")
+	mkArtifact(t, versionDir, "tools", "removed-server.json")
+	mkArtifact(t, versionDir, "readme", "fetch.md")
+
+	report, err := GC(serversDir, catalogsDir)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+
+	wantCatalogs := []string{filepath.Join(catalogsDir, "removed-server")}
+	if !slices.Equal(report.OrphanedCatalogs, wantCatalogs) {
+		t.Errorf("OrphanedCatalogs = %v, want %v", report.OrphanedCatalogs, wantCatalogs)
+	}
+
+	wantArtifacts := []string{filepath.Join(versionDir, "tools", "removed-server.json")}
+	if !slices.Equal(report.OrphanedArtifacts, wantArtifacts) {
+		t.Errorf("OrphanedArtifacts = %v, want %v", report.OrphanedArtifacts, wantArtifacts)
+	}
+}
+
+func mkServer(t *testing.T, serversDir, name string) {
+	t.Helper()
+	dir := filepath.Join(serversDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "server.yaml"), []byte("name: "+name+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mkCatalogDir(t *testing.T, catalogsDir, name string) {
+	t.Helper()
+	dir := filepath.Join(catalogsDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "catalog.yaml"), []byte("version: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mkArtifact(t *testing.T, versionDir, sub, filename string) {
+	t.Helper()
+	dir := filepath.Join(versionDir, sub)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filename), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/docker/mcp-registry/internal/licenses"
 	"github.com/docker/mcp-registry/pkg/github"
 	"github.com/docker/mcp-registry/pkg/servers"
+	"github.com/docker/mcp-registry/pkg/signing"
 
 	"github.com/docker/mcp-registry/pkg/hub"
 )
@@ -136,6 +137,8 @@ func ToTile(ctx context.Context, server servers.Server) (Tile, error) {
 
 	pullCount := 0
 	starCount := 0
+	var digest string
+	var signatures []SignatureRef
 	if strings.HasPrefix(image, "mcp/") {
 		repoInfo, err := hub.GetRepositoryInfo(ctx, server.Image)
 		if err != nil {
@@ -143,6 +146,16 @@ func ToTile(ctx context.Context, server servers.Server) (Tile, error) {
 		}
 		pullCount = repoInfo.PullCount
 		starCount = repoInfo.StarCount
+
+		// Signing is best-effort: an unsigned or not-yet-pushed image
+		// shouldn't block catalog generation, it just ships without
+		// Digest/Signatures.
+		if result, err := signing.Verify(ctx, image, nil); err == nil {
+			digest = result.Digest
+			for _, signer := range result.Signers {
+				signatures = append(signatures, SignatureRef{Identity: signer.Identity, Issuer: signer.Issuer})
+			}
+		}
 	}
 
 	meta := Metadata{
@@ -188,6 +201,8 @@ func ToTile(ctx context.Context, server servers.Server) (Tile, error) {
 		Config:         config,
 		Metadata:       meta,
 		OAuth:          oauth,
+		Digest:         digest,
+		Signatures:     signatures,
 	}, nil
 }
 
@@ -154,6 +154,23 @@ type Tile struct {
 	Config         []Config       `json:"config,omitempty" yaml:"config,omitempty"`
 	Metadata       Metadata       `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	OAuth          OAuth          `json:"oauth,omitempty" yaml:"oauth,omitempty"`
+	// Digest is the built image's digest (sha256:...), recorded so a
+	// consumer can verify it against Signatures without re-resolving a
+	// mutable tag.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+	// Signatures lists the cosign signers that verified against Digest when
+	// this tile was generated, for mcp/ images only (see ToTile).
+	Signatures []SignatureRef `json:"signatures,omitempty" yaml:"signatures,omitempty"`
+}
+
+// SignatureRef is a cosign signature verified for a Tile's Digest.
+type SignatureRef struct {
+	// Identity is the signer's certificate subject (keyless) or key
+	// identity (key-file).
+	Identity string `json:"identity,omitempty" yaml:"identity,omitempty"`
+	// Issuer is the OIDC issuer that vouched for Identity in keyless mode,
+	// empty in key-file mode.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
 }
 
 type Metadata struct {
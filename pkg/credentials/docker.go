@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package credentials resolves registry and git credentials from the
+// tools already configured on the machine running the registry tooling
+// (docker's credential helpers, git's credential helpers), rather than
+// requiring every private upstream to be plumbed through its own
+// environment variable.
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DockerCredentials is a resolved username/secret pair for a registry.
+type DockerCredentials struct {
+	Username string
+	Secret   string
+}
+
+// dockerConfig mirrors the subset of ~/.docker/config.json this package
+// reads.
+type dockerConfig struct {
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// credentialHelperResponse is the JSON a docker-credential-* helper prints
+// to stdout for a `get` request, per
+// https://github.com/docker/docker-credential-helpers.
+type credentialHelperResponse struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// LookupDocker resolves credentials for registry (e.g. "ghcr.io",
+// "docker.io") using the credential helper configured for it in
+// ~/.docker/config.json, falling back to the global credsStore. It returns
+// an error if no helper is configured or the helper has no credentials for
+// the registry, which callers should treat as "proceed unauthenticated".
+func LookupDocker(registry string) (DockerCredentials, error) {
+	cfg, err := readDockerConfig()
+	if err != nil {
+		return DockerCredentials{}, err
+	}
+
+	helper := cfg.CredHelpers[registry]
+	if helper == "" {
+		helper = cfg.CredsStore
+	}
+	if helper == "" {
+		return DockerCredentials{}, fmt.Errorf("credentials: no credential helper configured for %s", registry)
+	}
+
+	return runCredentialHelper(helper, registry)
+}
+
+func readDockerConfig() (dockerConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return dockerConfig{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".docker", "config.json"))
+	if err != nil {
+		return dockerConfig{}, err
+	}
+
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return dockerConfig{}, fmt.Errorf("credentials: parsing docker config.json: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func runCredentialHelper(helper, registry string) (DockerCredentials, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return DockerCredentials{}, fmt.Errorf("credentials: docker-credential-%s get %s: %w", helper, registry, err)
+	}
+
+	var resp credentialHelperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return DockerCredentials{}, fmt.Errorf("credentials: parsing docker-credential-%s output: %w", helper, err)
+	}
+
+	return DockerCredentials{Username: resp.Username, Secret: resp.Secret}, nil
+}
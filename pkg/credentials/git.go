@@ -0,0 +1,87 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// GitCredentials is a resolved username/password (or token) pair for a git
+// remote.
+type GitCredentials struct {
+	Username string
+	Password string
+}
+
+// LookupGit resolves credentials for rawURL using `git credential fill`,
+// the same mechanism `git clone`/`git fetch` use to consult whatever
+// credential helpers (osxkeychain, libsecret, a GitHub CLI helper, a
+// `.git-credentials` file, ...) are configured on the machine. It returns
+// an error if git has no stored credentials for the host, which callers
+// should treat as "proceed unauthenticated".
+func LookupGit(ctx context.Context, rawURL string) (GitCredentials, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return GitCredentials{}, fmt.Errorf("credentials: parsing %q: %w", rawURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return GitCredentials{}, fmt.Errorf("credentials: %q is not an absolute URL", rawURL)
+	}
+
+	input := fmt.Sprintf("protocol=%s\nhost=%s\n\n", u.Scheme, u.Host)
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return GitCredentials{}, fmt.Errorf("credentials: git credential fill for %s: %w", u.Host, err)
+	}
+
+	creds := GitCredentials{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "username":
+			creds.Username = value
+		case "password":
+			creds.Password = value
+		}
+	}
+
+	if creds.Password == "" {
+		return GitCredentials{}, fmt.Errorf("credentials: no stored credentials for %s", u.Host)
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package execenv builds the environment subprocesses that shell out to
+// docker/buildx (or a docker-backed MCP server) run under. Rather than
+// forwarding the whole of os.Environ() - which leaks bookkeeping variables
+// a parent process set for itself, like systemd's NOTIFY_SOCKET, into a
+// child that has no business seeing them - it forwards only an explicit
+// allowlist, covering what those subprocesses actually need to find a
+// daemon, a remote builder, a proxy, or an SSH agent.
+package execenv
+
+import (
+	"os"
+	"runtime"
+)
+
+// DefaultAllow lists the environment variables Build forwards by default.
+var DefaultAllow = []string{
+	"PATH",
+	"HOME",
+	"DOCKER_HOST",
+	"DOCKER_CONTEXT",
+	"DOCKER_CONFIG",
+	"DOCKER_CERT_PATH",
+	"DOCKER_TLS_VERIFY",
+	"BUILDX_BUILDER",
+	"BUILDKIT_PROGRESS",
+	"SSH_AUTH_SOCK",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
+	"http_proxy", "https_proxy", "no_proxy",
+}
+
+// windowsAllow lists variables Build additionally forwards on Windows,
+// where Docker Desktop needs ProgramW6432 to locate Program Files even
+// when PATH has been trimmed down. See
+// https://github.com/docker/mcp-registry/issues/79.
+var windowsAllow = []string{"ProgramW6432"}
+
+// DefaultDeny lists environment variables Build always strips, even if a
+// caller's Config.Allow names them: they're systemd bookkeeping a parent
+// process sets for itself, and a subprocess inheriting them can be fooled
+// into acting on them - NOTIFY_SOCKET, for instance, causes some programs
+// to send spurious systemd readiness notifications on the parent's behalf.
+var DefaultDeny = []string{
+	"NOTIFY_SOCKET",
+	"LISTEN_PID",
+	"LISTEN_FDS",
+	"INVOCATION_ID",
+}
+
+// Config customizes Build beyond DefaultAllow/DefaultDeny.
+type Config struct {
+	// Allow adds additional variable names to forward.
+	Allow []string
+	// Deny adds additional variable names to always strip.
+	Deny []string
+}
+
+// Build assembles a subprocess environment: every variable named in
+// DefaultAllow or cfg.Allow (plus, on Windows, ProgramW6432) that's set in
+// the current process's environment, except any named in DefaultDeny or
+// cfg.Deny, followed by extra ("KEY=VALUE" pairs such as a GIT_AUTH_TOKEN
+// secret), appended last so they win over an inherited value of the same
+// name.
+func Build(cfg Config, extra ...string) []string {
+	return build(runtime.GOOS, cfg, extra...)
+}
+
+// build is Build with the OS pinned, so tests can exercise the Windows and
+// non-Windows allowlists without actually running on each.
+func build(goos string, cfg Config, extra ...string) []string {
+	allow := append(append([]string(nil), DefaultAllow...), cfg.Allow...)
+	if goos == "windows" {
+		allow = append(allow, windowsAllow...)
+	}
+
+	deny := make(map[string]bool, len(DefaultDeny)+len(cfg.Deny))
+	for _, name := range DefaultDeny {
+		deny[name] = true
+	}
+	for _, name := range cfg.Deny {
+		deny[name] = true
+	}
+
+	var env []string
+	seen := make(map[string]bool, len(allow))
+	for _, name := range allow {
+		if deny[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return append(env, extra...)
+}
@@ -0,0 +1,59 @@
+package execenv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildForwardsAllowlistAndDrops(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+	t.Setenv("NOTIFY_SOCKET", "/run/systemd/notify")
+	t.Setenv("SOME_RANDOM_VAR", "nope")
+
+	env := build("linux", Config{})
+
+	if !slices.Contains(env, "DOCKER_HOST=unix:///var/run/docker.sock") {
+		t.Errorf("build() = %v, want DOCKER_HOST forwarded", env)
+	}
+	for _, kv := range env {
+		if slices.Contains([]string{"NOTIFY_SOCKET", "SOME_RANDOM_VAR"}, kv) {
+			t.Errorf("build() forwarded %q, want it dropped", kv)
+		}
+	}
+}
+
+func TestBuildWindowsVsUnix(t *testing.T) {
+	t.Setenv("ProgramW6432", `C:\Program Files`)
+
+	linux := build("linux", Config{})
+	for _, kv := range linux {
+		if slices.Contains([]string{`ProgramW6432=C:\Program Files`}, kv) {
+			t.Errorf("build(\"linux\") forwarded ProgramW6432, want it Windows-only: %v", linux)
+		}
+	}
+
+	windows := build("windows", Config{})
+	if !slices.Contains(windows, `ProgramW6432=C:\Program Files`) {
+		t.Errorf("build(\"windows\") = %v, want ProgramW6432 forwarded", windows)
+	}
+}
+
+func TestBuildConfigAllowDenyAndExtra(t *testing.T) {
+	t.Setenv("MY_CUSTOM_VAR", "custom")
+	t.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+
+	env := build("linux", Config{
+		Allow: []string{"MY_CUSTOM_VAR"},
+		Deny:  []string{"DOCKER_HOST"},
+	}, "GIT_AUTH_TOKEN=secret")
+
+	if !slices.Contains(env, "MY_CUSTOM_VAR=custom") {
+		t.Errorf("build() = %v, want MY_CUSTOM_VAR forwarded", env)
+	}
+	if slices.Contains(env, "DOCKER_HOST=unix:///var/run/docker.sock") {
+		t.Errorf("build() = %v, want DOCKER_HOST denied", env)
+	}
+	if !slices.Contains(env, "GIT_AUTH_TOKEN=secret") {
+		t.Errorf("build() = %v, want extra GIT_AUTH_TOKEN appended", env)
+	}
+}
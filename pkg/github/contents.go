@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// ErrNotFound indicates the requested path doesn't exist at ref.
+var ErrNotFound = fmt.Errorf("not found")
+
+// GetFileContent returns the decoded contents of the file at path on
+// project, as of ref (a branch, tag, or commit SHA; "" for the default
+// branch). It returns ErrNotFound if path doesn't exist or is a directory.
+func (c *Client) GetFileContent(ctx context.Context, project, ref, path string) (string, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return "", err
+	}
+
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	for {
+		file, _, resp, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, opts)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if resp != nil && resp.StatusCode == 404 {
+			return "", ErrNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		if file == nil {
+			return "", ErrNotFound
+		}
+
+		return file.GetContent()
+	}
+}
+
+// ListDirectory returns the entries of the directory at path on project, as
+// of ref (a branch, tag, or commit SHA; "" for the default branch).
+func (c *Client) ListDirectory(ctx context.Context, project, ref, path string) ([]*github.RepositoryContent, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	for {
+		_, dir, resp, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, opts)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if resp != nil && resp.StatusCode == 404 {
+			return nil, ErrNotFound
+		}
+
+		return dir, err
+	}
+}
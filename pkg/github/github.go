@@ -97,6 +97,105 @@ func (c *Client) GetCommitSHA1(ctx context.Context, project, branch string) (str
 	}
 }
 
+// GetCommitObject fetches the low-level git commit object for sha,
+// including the GPG/SSH signature GitHub verified it against (if any).
+func (c *Client) GetCommitObject(ctx context.Context, project, sha string) (*github.Commit, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		commit, _, err := c.gh.Git.GetCommit(ctx, owner, repo, sha)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return commit, err
+	}
+}
+
+// GetSignedTag resolves name as a tag ref and returns its annotated tag
+// object, including the GPG/SSH signature GitHub verified it against. It
+// returns an error if the ref doesn't exist or is a lightweight tag (which
+// carries no signature of its own).
+func (c *Client) GetSignedTag(ctx context.Context, project, name string) (*github.Tag, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref *github.Reference
+	for {
+		var err error
+		ref, _, err = c.gh.Git.GetRef(ctx, owner, repo, "refs/tags/"+name)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	if ref.GetObject().GetType() != "tag" {
+		return nil, fmt.Errorf("%s is a lightweight tag and carries no signature of its own", name)
+	}
+
+	for {
+		tag, _, err := c.gh.Git.GetTag(ctx, owner, repo, ref.GetObject().GetSHA())
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return tag, err
+	}
+}
+
+// CreateReviewComments posts a pull request review made up of inline
+// comments, e.g. one per SARIF finding, anchoring each to its file and line
+// on commitSHA. body is the top-level review summary; pass "" for none.
+func (c *Client) CreateReviewComments(ctx context.Context, project string, number int, commitSHA, body string, comments []*github.DraftReviewComment) (*github.PullRequestReview, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &github.PullRequestReviewRequest{
+		CommitID: &commitSHA,
+		Event:    github.Ptr("COMMENT"),
+		Comments: comments,
+	}
+	if body != "" {
+		review.Body = &body
+	}
+
+	for {
+		result, _, err := c.gh.PullRequests.CreateReview(ctx, owner, repo, number, review)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return result, err
+	}
+}
+
+// RemainingCoreRateLimit reports the number of core API requests left in
+// the current rate-limit window, for callers that want to surface budget
+// remaining after a bulk operation like a parallel pin refresh.
+func (c *Client) RemainingCoreRateLimit(ctx context.Context) (int, error) {
+	for {
+		limits, _, err := c.gh.RateLimit.Get(ctx)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return limits.Core.Remaining, nil
+	}
+}
+
 func (c *Client) FindIcon(ctx context.Context, projectURL string) (string, error) {
 	repository, err := c.GetProjectRepository(ctx, projectURL)
 	if err != nil {
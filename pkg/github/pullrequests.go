@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// CompareCommits returns the GitHub comparison of base...head on project,
+// including the commits head has that base doesn't. Callers use this to
+// summarize an upstream's history (or, when project is the registry itself,
+// a branch about to be opened as a pull request) in a pin-update PR body.
+func (c *Client) CompareCommits(ctx context.Context, project, base, head string) (*github.CommitsComparison, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		comparison, _, err := c.gh.Repositories.CompareCommits(ctx, owner, repo, base, head, nil)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return comparison, err
+	}
+}
+
+// FindOpenPullRequest returns the open pull request on project whose head
+// branch is headBranch, or nil if none exists. Callers use this to reuse an
+// already-open pin-update PR instead of opening a duplicate.
+func (c *Client) FindOpenPullRequest(ctx context.Context, project, headBranch string) (*github.PullRequest, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		prs, _, err := c.gh.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+			State: "open",
+			Head:  owner + ":" + headBranch,
+		})
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(prs) == 0 {
+			return nil, nil
+		}
+
+		return prs[0], nil
+	}
+}
+
+// CreatePullRequest opens a pull request on project from headBranch onto
+// baseBranch.
+func (c *Client) CreatePullRequest(ctx context.Context, project, title, body, headBranch, baseBranch string) (*github.PullRequest, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	newPR := &github.NewPullRequest{
+		Title: github.Ptr(title),
+		Head:  github.Ptr(headBranch),
+		Base:  github.Ptr(baseBranch),
+		Body:  github.Ptr(body),
+	}
+
+	for {
+		pr, _, err := c.gh.PullRequests.Create(ctx, owner, repo, newPR)
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return pr, err
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	ghapi "github.com/google/go-github/v70/github"
+)
+
+// maxConditionalRetries bounds how many times GetCommitSHA1Cached backs off
+// on a secondary rate limit or transient server error before giving up, so
+// a persistently broken upstream doesn't hang a refresh run forever.
+const maxConditionalRetries = 6
+
+// RefreshCacheFileName is the conventional name callers should use when
+// persisting a RefreshCache to a workspace, e.g. `update-pins -jobs 8`
+// refreshing every server's pinned branch.
+const RefreshCacheFileName = ".refresh-cache.json"
+
+// refreshCacheEntry records the last known state of a single {project,
+// branch} pair, letting subsequent refreshes ask GitHub for only what
+// changed.
+type refreshCacheEntry struct {
+	// SHA is the commit the branch pointed at as of CheckedAt. It doubles
+	// as the ETag GetCommitSHA1 sends back as If-None-Match.
+	SHA string `json:"sha"`
+	// LastModified is the upstream Last-Modified response header, when
+	// GitHub provided one, for endpoints that support If-Modified-Since.
+	LastModified string `json:"lastModified,omitempty"`
+	// CheckedAt is when this entry was last confirmed against the API,
+	// successfully or via a 304.
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// refreshCacheFile is the on-disk representation of a RefreshCache.
+type refreshCacheFile struct {
+	// RunStartedAt marks the beginning of the refresh run that is either
+	// still in progress or most recently completed. A -resume run treats
+	// every entry checked at or after this timestamp as already handled,
+	// so an interrupted refresh doesn't repeat work it already confirmed.
+	RunStartedAt time.Time                    `json:"runStartedAt"`
+	Entries      map[string]refreshCacheEntry `json:"entries"`
+}
+
+// RefreshCache persists per-{project,branch} conditional-request state
+// across invocations, so a resumed or rerun refresh can skip branches that
+// haven't moved instead of paying a full API call for each one. It is safe
+// for concurrent use by multiple worker goroutines.
+type RefreshCache struct {
+	path string
+
+	mu      sync.Mutex
+	data    refreshCacheFile
+	calls   int
+	cached  int
+	changed int
+}
+
+// LoadRefreshCache reads the cache file at path, starting a fresh empty
+// cache if it doesn't exist yet. When resume is false, any run already
+// recorded in the file is considered finished and a new run begins, so
+// every entry will be re-verified (cheaply, via conditional requests)
+// rather than skipped outright.
+func LoadRefreshCache(path string, resume bool) (*RefreshCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newRefreshCache(path), nil
+		}
+		return nil, fmt.Errorf("read refresh cache %s: %w", path, err)
+	}
+
+	var file refreshCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse refresh cache %s: %w", path, err)
+	}
+	if file.Entries == nil {
+		file.Entries = make(map[string]refreshCacheEntry)
+	}
+	if !resume {
+		file.RunStartedAt = time.Now()
+	}
+
+	return &RefreshCache{path: path, data: file}, nil
+}
+
+// newRefreshCache starts an empty cache rooted at path, beginning a new run.
+func newRefreshCache(path string) *RefreshCache {
+	return &RefreshCache{
+		path: path,
+		data: refreshCacheFile{
+			RunStartedAt: time.Now(),
+			Entries:      make(map[string]refreshCacheEntry),
+		},
+	}
+}
+
+// Save persists the cache to its path.
+func (c *RefreshCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.saveLocked()
+}
+
+func (c *RefreshCache) saveLocked() error {
+	data, err := json.MarshalIndent(c.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal refresh cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("write refresh cache %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// Finish clears the in-progress run marker and saves the cache, signaling
+// that the refresh completed and a future invocation should start fresh
+// unless it's explicitly resuming an interrupted run.
+func (c *RefreshCache) Finish() error {
+	c.mu.Lock()
+	c.data.RunStartedAt = time.Time{}
+	defer c.mu.Unlock()
+	return c.saveLocked()
+}
+
+// AlreadyCheckedThisRun reports whether project/branch was already
+// confirmed during the run currently recorded in the cache, returning its
+// cached SHA when so. Callers use this under -resume to skip API calls
+// entirely for branches an interrupted run already verified.
+func (c *RefreshCache) AlreadyCheckedThisRun(project, branch string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data.RunStartedAt.IsZero() {
+		return "", false
+	}
+	entry, ok := c.data.Entries[cacheKey(project, branch)]
+	if !ok || entry.CheckedAt.Before(c.data.RunStartedAt) {
+		return "", false
+	}
+	return entry.SHA, true
+}
+
+// Summary reports how many API calls this cache drove, how many of those
+// came back 304 Not Modified, and how many branches actually moved.
+func (c *RefreshCache) Summary() (calls, cached, changed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls, c.cached, c.changed
+}
+
+func cacheKey(project, branch string) string {
+	return project + "@" + branch
+}
+
+// GetCommitSHA1Cached resolves branch's head commit on project, sending
+// If-None-Match with the last SHA this cache observed so an unchanged
+// branch costs GitHub zero rate-limit points. The cache entry (and this
+// run's call/304/changed counters) is updated before returning.
+func (c *Client) GetCommitSHA1Cached(ctx context.Context, cache *RefreshCache, project, branch string) (string, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return "", err
+	}
+
+	lastSHA, _ := cache.lastSHA(project, branch)
+
+	for attempt := 0; ; attempt++ {
+		sha, resp, err := c.gh.Repositories.GetCommitSHA1(ctx, owner, repo, branch, lastSHA)
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			cache.recordChecked(project, branch, lastSHA, resp)
+			return lastSHA, nil
+		}
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+		if err != nil {
+			if attempt < maxConditionalRetries && shouldBackoffAndRetry(err) {
+				if waitErr := sleepWithBackoff(ctx, attempt, secondaryRetryAfter(err)); waitErr != nil {
+					return "", waitErr
+				}
+				continue
+			}
+			return "", err
+		}
+
+		cache.recordResolved(project, branch, lastSHA, sha, resp)
+		return sha, nil
+	}
+}
+
+// shouldBackoffAndRetry reports whether err represents a secondary rate
+// limit (403) or a transient server error (429/5xx) worth retrying, as
+// opposed to a permanent failure like a missing branch.
+func shouldBackoffAndRetry(err error) bool {
+	var abuseErr *ghapi.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return true
+	}
+	var errResp *ghapi.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		status := errResp.Response.StatusCode
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// secondaryRetryAfter extracts the Retry-After duration GitHub reported
+// for a secondary rate limit, if any.
+func secondaryRetryAfter(err error) time.Duration {
+	var abuseErr *ghapi.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return *abuseErr.RetryAfter
+	}
+	return 0
+}
+
+// sleepWithBackoff waits for retryAfter when GitHub supplied one, or an
+// exponentially increasing delay with jitter otherwise, so a fleet of
+// parallel workers hitting the same secondary limit doesn't retry in
+// lockstep.
+func sleepWithBackoff(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		base := time.Second << attempt
+		delay = base/2 + time.Duration(rand.Int63n(int64(base/2)+1))
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// lastSHA returns the SHA this cache last observed for project/branch, if any.
+func (c *RefreshCache) lastSHA(project, branch string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.data.Entries[cacheKey(project, branch)]
+	return entry.SHA, ok
+}
+
+// recordChecked records a 304 response: the branch is unchanged.
+func (c *RefreshCache) recordChecked(project, branch, sha string, resp *ghapi.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	c.cached++
+	c.setEntryLocked(project, branch, sha, resp)
+}
+
+// recordResolved records a 200 response, noting whether the branch moved.
+func (c *RefreshCache) recordResolved(project, branch, lastSHA, sha string, resp *ghapi.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if lastSHA != "" && lastSHA != sha {
+		c.changed++
+	}
+	c.setEntryLocked(project, branch, sha, resp)
+}
+
+func (c *RefreshCache) setEntryLocked(project, branch, sha string, resp *ghapi.Response) {
+	entry := refreshCacheEntry{SHA: sha, CheckedAt: time.Now()}
+	if resp != nil {
+		entry.LastModified = resp.Header.Get("Last-Modified")
+	}
+	if c.data.Entries == nil {
+		c.data.Entries = make(map[string]refreshCacheEntry)
+	}
+	c.data.Entries[cacheKey(project, branch)] = entry
+}
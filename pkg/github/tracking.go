@@ -0,0 +1,201 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v70/github"
+	"golang.org/x/mod/semver"
+)
+
+// Track strategy values recognized by ResolveTrackedCommit beyond a
+// "^"/"~" semver constraint.
+const (
+	TrackLatestRelease = "latest-release"
+	TrackLatestTag     = "latest-tag"
+)
+
+// ListTags returns up to 100 tags for project's repository.
+func (c *Client) ListTags(ctx context.Context, project string) ([]*github.RepositoryTag, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tags, _, err := c.gh.Repositories.ListTags(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return tags, err
+	}
+}
+
+// ListReleases returns up to 100 releases for project's repository, in the
+// order the GitHub API returns them (newest first).
+func (c *Client) ListReleases(ctx context.Context, project string) ([]*github.RepositoryRelease, error) {
+	owner, repo, err := extractOrgAndProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		releases, _, err := c.gh.Repositories.ListReleases(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+		if sleepOnRateLimitError(ctx, err) {
+			continue
+		}
+
+		return releases, err
+	}
+}
+
+// ResolveTrackedCommit resolves project's newest tag under strategy - one
+// of TrackLatestRelease, TrackLatestTag, or a caret/tilde semver constraint
+// such as "^1.2" or "~1.2.3" - returning the matching tag name and the
+// commit SHA it points at. Only tags whose name parses as a semantic
+// version (with an optional leading "v") are considered; anything else is
+// silently ignored rather than rejected, since upstreams commonly mix
+// release tags with other refs (e.g. "nightly").
+func (c *Client) ResolveTrackedCommit(ctx context.Context, project, strategy string) (tag, sha string, err error) {
+	matches, err := trackConstraintMatcher(strategy)
+	if err != nil {
+		return "", "", err
+	}
+
+	candidates, err := c.trackCandidates(ctx, project, strategy)
+	if err != nil {
+		return "", "", err
+	}
+
+	best := ""
+	for name := range candidates {
+		version := normalizeSemver(name)
+		if version == "" || !matches(version) {
+			continue
+		}
+		if best == "" || semver.Compare(normalizeSemver(best), version) < 0 {
+			best = name
+		}
+	}
+	if best == "" {
+		return "", "", fmt.Errorf("no tag on %s satisfies track %q", project, strategy)
+	}
+
+	sha = candidates[best]
+	if sha == "" {
+		// Releases don't carry a commit SHA directly; their tag name
+		// resolves to one the same way a branch name does.
+		sha, err = c.GetCommitSHA1(ctx, project, best)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return best, sha, nil
+}
+
+// trackCandidates returns the tag names strategy should choose among,
+// mapped to their commit SHA where it's already known from the listing
+// (tags carry one; releases don't, and are resolved lazily by
+// ResolveTrackedCommit instead).
+func (c *Client) trackCandidates(ctx context.Context, project, strategy string) (map[string]string, error) {
+	if strategy == TrackLatestRelease {
+		releases, err := c.ListReleases(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make(map[string]string, len(releases))
+		for _, release := range releases {
+			if release.GetDraft() || release.GetPrerelease() {
+				continue
+			}
+			candidates[release.GetTagName()] = ""
+		}
+		return candidates, nil
+	}
+
+	tags, err := c.ListTags(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[string]string, len(tags))
+	for _, t := range tags {
+		candidates[t.GetName()] = t.GetCommit().GetSHA()
+	}
+	return candidates, nil
+}
+
+// trackConstraintMatcher returns a predicate selecting the normalized
+// semver versions strategy allows. TrackLatestRelease and TrackLatestTag
+// match every candidate - trackCandidates has already restricted the set
+// to releases or tags; anything else is parsed as a caret ("^1.2",
+// compatible within the same leading version component) or tilde ("~1.2",
+// same major.minor) constraint, mirroring the subset of npm's semver range
+// syntax registry maintainers are most likely to reach for.
+func trackConstraintMatcher(strategy string) (func(version string) bool, error) {
+	switch strategy {
+	case TrackLatestRelease, TrackLatestTag:
+		return func(string) bool { return true }, nil
+	}
+
+	if len(strategy) < 2 {
+		return nil, fmt.Errorf("unsupported track strategy %q: expected %q, %q, or a \"^\"/\"~\" semver constraint", strategy, TrackLatestRelease, TrackLatestTag)
+	}
+
+	op, base := strategy[0], strategy[1:]
+	if op != '^' && op != '~' {
+		return nil, fmt.Errorf("unsupported track strategy %q: expected %q, %q, or a \"^\"/\"~\" semver constraint", strategy, TrackLatestRelease, TrackLatestTag)
+	}
+
+	baseVersion := normalizeSemver(base)
+	if baseVersion == "" {
+		return nil, fmt.Errorf("track constraint %q is not a valid semver", strategy)
+	}
+
+	if op == '^' {
+		return func(version string) bool {
+			return semver.Major(version) == semver.Major(baseVersion) && semver.Compare(version, baseVersion) >= 0
+		}, nil
+	}
+	return func(version string) bool {
+		return semver.MajorMinor(version) == semver.MajorMinor(baseVersion) && semver.Compare(version, baseVersion) >= 0
+	}, nil
+}
+
+// normalizeSemver canonicalizes name into the "vX.Y[.Z]" form
+// golang.org/x/mod/semver expects, returning "" if name isn't a valid
+// semantic version.
+func normalizeSemver(name string) string {
+	if !strings.HasPrefix(name, "v") {
+		name = "v" + name
+	}
+	if !semver.IsValid(name) {
+		return ""
+	}
+	return name
+}
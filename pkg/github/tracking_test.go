@@ -0,0 +1,57 @@
+package github
+
+import "testing"
+
+func TestNormalizeSemver(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "1.2.3", want: "v1.2.3"},
+		{in: "v1.2.3", want: "v1.2.3"},
+		{in: "1.2", want: "v1.2"},
+		{in: "nightly", want: ""},
+		{in: "v1.2.3-rc1", want: "v1.2.3-rc1"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeSemver(tt.in); got != tt.want {
+			t.Errorf("normalizeSemver(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTrackConstraintMatcher(t *testing.T) {
+	tests := []struct {
+		strategy string
+		version  string
+		want     bool
+		wantErr  bool
+	}{
+		{strategy: TrackLatestTag, version: "v0.1.0", want: true},
+		{strategy: "^1.2", version: "v1.2.0", want: true},
+		{strategy: "^1.2", version: "v1.9.9", want: true},
+		{strategy: "^1.2", version: "v1.1.9", want: false},
+		{strategy: "^1.2", version: "v2.0.0", want: false},
+		{strategy: "~1.2", version: "v1.2.5", want: true},
+		{strategy: "~1.2", version: "v1.3.0", want: false},
+		{strategy: "not-a-constraint", wantErr: true},
+		{strategy: "^nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		matches, err := trackConstraintMatcher(tt.strategy)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("trackConstraintMatcher(%q): expected error, got none", tt.strategy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("trackConstraintMatcher(%q): unexpected error: %v", tt.strategy, err)
+		}
+		if got := matches(tt.version); got != tt.want {
+			t.Errorf("trackConstraintMatcher(%q)(%q) = %v, want %v", tt.strategy, tt.version, got, tt.want)
+		}
+	}
+}
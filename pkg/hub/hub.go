@@ -28,6 +28,14 @@ import (
 	"net/http"
 )
 
+// repositoryResponse is the subset of Hub's repository-info response this
+// package exposes to callers (pkg/catalog's tile generation, for
+// pull/star counts).
+type repositoryResponse struct {
+	PullCount int `json:"pull_count"`
+	StarCount int `json:"star_count"`
+}
+
 func GetRepositoryInfo(ctx context.Context, repo string) (*repositoryResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://hub.docker.com/v2/repositories/"+repo+"/", nil)
 	if err != nil {
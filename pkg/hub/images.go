@@ -0,0 +1,182 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/mcp-registry/pkg/credentials"
+)
+
+// Repository is the subset of Hub's repository listing this package needs
+// to find images `cmd/catalog-gc` has no server.yaml for.
+type Repository struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Tag is the subset of Hub's tag listing this package needs.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// repositoryPage mirrors the paginated envelope every Hub v2 list endpoint
+// returns.
+type repositoryPage struct {
+	Next    string       `json:"next"`
+	Results []Repository `json:"results"`
+}
+
+type tagPage struct {
+	Next    string `json:"next"`
+	Results []Tag  `json:"results"`
+}
+
+// ListRepositories returns every repository under namespace (e.g. "mcp"),
+// following Hub's pagination until it runs out of pages.
+func ListRepositories(ctx context.Context, namespace string) ([]Repository, error) {
+	url := fmt.Sprintf("https://hub.docker.com/v2/namespaces/%s/repositories?page_size=100", namespace)
+
+	var repos []Repository
+	for url != "" {
+		var page repositoryPage
+		if err := getJSON(ctx, url, &page); err != nil {
+			return nil, fmt.Errorf("hub: listing repositories under %s: %w", namespace, err)
+		}
+		repos = append(repos, page.Results...)
+		url = page.Next
+	}
+	return repos, nil
+}
+
+// ListTags returns every tag on repo (e.g. "mcp/fetch"), following Hub's
+// pagination until it runs out of pages.
+func ListTags(ctx context.Context, repo string) ([]Tag, error) {
+	url := fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/tags?page_size=100", repo)
+
+	var tags []Tag
+	for url != "" {
+		var page tagPage
+		if err := getJSON(ctx, url, &page); err != nil {
+			return nil, fmt.Errorf("hub: listing tags for %s: %w", repo, err)
+		}
+		tags = append(tags, page.Results...)
+		url = page.Next
+	}
+	return tags, nil
+}
+
+// DeleteRepository deletes repo (e.g. "mcp/some-orphaned-server") from Hub
+// entirely, authenticating with the docker.io credentials the local
+// `docker login` has stored (see pkg/credentials.LookupDocker).
+func DeleteRepository(ctx context.Context, repo string) error {
+	token, err := loginToken(ctx)
+	if err != nil {
+		return fmt.Errorf("hub: deleting %s: %w", repo, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, "https://hub.docker.com/v2/repositories/"+repo+"/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("hub: deleting %s: %w", repo, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("hub: deleting %s: unexpected status %s", repo, resp.Status)
+	}
+	return nil
+}
+
+// loginToken exchanges the docker.io credentials the local `docker login`
+// has stored for a Hub JWT, the same way `docker login` itself does, so
+// repository deletion doesn't need a separately plumbed Hub token.
+func loginToken(ctx context.Context) (string, error) {
+	creds, err := credentials.LookupDocker("docker.io")
+	if err != nil {
+		return "", fmt.Errorf("resolving docker.io credentials (run `docker login` first): %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"username": creds.Username,
+		"password": creds.Secret,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://hub.docker.com/v2/users/login", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("logging in to Hub: unexpected status %s", resp.Status)
+	}
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+		return "", err
+	}
+	return login.Token, nil
+}
+
+// getJSON issues an unauthenticated GET against url and decodes its JSON
+// body into v. Hub's public list endpoints don't require a logged-in
+// session.
+func getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
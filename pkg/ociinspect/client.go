@@ -0,0 +1,327 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package ociinspect reads an image's config (working directory,
+// entrypoint, command, environment, labels) directly from its registry,
+// over the OCI distribution HTTP API
+// (https://github.com/opencontainers/distribution-spec). It exists so the
+// registry tooling can inspect an already-published image - to import it
+// via `--image` or to validate a pinned `image:` in server.yaml - without
+// requiring a local `docker pull` or `docker build` first.
+package ociinspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/credentials"
+	"github.com/docker/mcp-registry/pkg/reference"
+)
+
+// manifestAccept lists the manifest media types this client knows how to
+// follow, in preference order. A manifest list/index is resolved to its
+// first linux/amd64 (or first) entry.
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}, ", ")
+
+// Config is the subset of the OCI image config this package exposes.
+type Config struct {
+	WorkingDir string            `json:"WorkingDir"`
+	Entrypoint []string          `json:"Entrypoint"`
+	Cmd        []string          `json:"Cmd"`
+	Env        []string          `json:"Env"`
+	Labels     map[string]string `json:"Labels"`
+}
+
+type imageConfig struct {
+	Config Config `json:"config"`
+}
+
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// Inspect fetches ref's manifest and image config straight from its
+// registry and returns the config. It resolves docker credentials for the
+// ref's registry the same way `docker login` would (see
+// pkg/credentials.LookupDocker), falling back to an unauthenticated
+// request if none are configured, so public images need no setup.
+func Inspect(ctx context.Context, ref string) (Config, error) {
+	r, err := reference.Parse(ref)
+	if err != nil {
+		return Config{}, err
+	}
+	r = r.Normalize()
+
+	client := &Client{httpClient: http.DefaultClient}
+	if creds, err := credentials.LookupDocker(r.Domain); err == nil {
+		client.username = creds.Username
+		client.password = creds.Secret
+	}
+
+	return client.GetConfig(ctx, r)
+}
+
+// Client talks to a single registry over HTTPS, handling the distribution
+// spec's token-based Bearer auth challenge transparently.
+type Client struct {
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// GetConfig fetches ref's manifest (resolving an index/manifest-list to a
+// single-platform manifest if necessary) and returns its image config.
+func (c *Client) GetConfig(ctx context.Context, ref reference.Reference) (Config, error) {
+	tagOrDigest := ref.Tag
+	if ref.Digest != "" {
+		tagOrDigest = ref.Digest
+	}
+
+	m, err := c.getManifest(ctx, ref.Domain, ref.Path, tagOrDigest)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if len(m.Manifests) > 0 {
+		digest := m.Manifests[0].Digest
+		for _, candidate := range m.Manifests {
+			if candidate.Platform.OS == "linux" && candidate.Platform.Architecture == "amd64" {
+				digest = candidate.Digest
+				break
+			}
+		}
+		m, err = c.getManifest(ctx, ref.Domain, ref.Path, digest)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	if m.Config.Digest == "" {
+		return Config{}, fmt.Errorf("ociinspect: manifest for %s has no config descriptor", ref)
+	}
+
+	blob, err := c.getBlob(ctx, ref.Domain, ref.Path, m.Config.Digest)
+	if err != nil {
+		return Config{}, err
+	}
+	defer blob.Close()
+
+	var cfg imageConfig
+	if err := json.NewDecoder(blob).Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("ociinspect: decoding image config: %w", err)
+	}
+
+	return cfg.Config, nil
+}
+
+func (c *Client) getManifest(ctx context.Context, domain, path, tagOrDigest string) (manifest, error) {
+	var m manifest
+	err := c.do(ctx, domain, path, "manifests", tagOrDigest, manifestAccept, func(resp *http.Response) error {
+		return json.NewDecoder(resp.Body).Decode(&m)
+	})
+	if err != nil {
+		return manifest{}, fmt.Errorf("ociinspect: fetching manifest for %s/%s:%s: %w", domain, path, tagOrDigest, err)
+	}
+	return m, nil
+}
+
+func (c *Client) getBlob(ctx context.Context, domain, path, digest string) (io.ReadCloser, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", registryHost(domain), path, digest)
+
+	resp, err := c.request(ctx, domain, path, http.MethodGet, url, "")
+	if err != nil {
+		return nil, fmt.Errorf("ociinspect: fetching blob %s/%s@%s: %w", domain, path, digest, err)
+	}
+	return resp.Body, nil
+}
+
+// do issues a GET against the registry's manifests or blobs endpoint and
+// hands the response to fn, closing the body once fn returns.
+func (c *Client) do(ctx context.Context, domain, path, kind, ref, accept string, fn func(*http.Response) error) error {
+	url := fmt.Sprintf("https://%s/v2/%s/%s/%s", registryHost(domain), path, kind, ref)
+
+	resp, err := c.request(ctx, domain, path, http.MethodGet, url, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return fn(resp)
+}
+
+// request performs an authenticated GET, resolving a Bearer token against
+// the registry's auth challenge (per the distribution spec) the first time
+// it sees a 401, then retrying once with the token attached.
+func (c *Client) request(ctx context.Context, domain, path, method, url, accept string) (*http.Response, error) {
+	do := func(token string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return c.httpClient.Do(req)
+	}
+
+	resp, err := do("")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("Www-Authenticate")
+		resp.Body.Close()
+
+		token, err := c.authenticate(ctx, domain, path, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = do(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GET %s: %s: %s", url, resp.Status, body)
+	}
+
+	return resp, nil
+}
+
+// authenticate exchanges a WWW-Authenticate Bearer challenge for a token
+// from the realm's auth server, per
+// https://distribution.github.io/distribution/spec/auth/token/. It sends
+// the client's basic auth credentials, if any, to the auth server so
+// private images can be resolved.
+func (c *Client) authenticate(ctx context.Context, domain, path, challenge string) (string, error) {
+	realm, params, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+	if err != nil {
+		return "", err
+	}
+
+	q := req.URL.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("authenticating against %s: %w", domain, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("authenticating against %s: %s: %s", domain, resp.Status, body)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decoding token response from %s: %w", domain, err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// parseBearerChallenge splits a `Bearer realm="...",service="...",scope="..."`
+// WWW-Authenticate header into the realm URL and its remaining key/value
+// parameters.
+func parseBearerChallenge(challenge string) (realm string, params map[string]string, err error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", nil, fmt.Errorf("ociinspect: unsupported auth challenge %q", challenge)
+	}
+
+	params = map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := kv[0]
+		value := strings.Trim(kv[1], `"`)
+		if key == "realm" {
+			realm = value
+			continue
+		}
+		params[key] = value
+	}
+
+	if realm == "" {
+		return "", nil, fmt.Errorf("ociinspect: auth challenge %q has no realm", challenge)
+	}
+	return realm, params, nil
+}
+
+// registryHost maps the implicit "docker.io" domain to the host that
+// actually serves the distribution API (Docker Hub splits its registry
+// frontend from its "docker.io" branding host).
+func registryHost(domain string) string {
+	if domain == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return domain
+}
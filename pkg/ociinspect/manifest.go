@@ -0,0 +1,180 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package ociinspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/docker/mcp-registry/pkg/credentials"
+	"github.com/docker/mcp-registry/pkg/reference"
+)
+
+// Platform identifies one image variant within a multi-platform manifest
+// list/index.
+type Platform struct {
+	OS           string
+	Architecture string
+}
+
+// String renders p as the conventional "os/arch" form.
+func (p Platform) String() string {
+	return p.OS + "/" + p.Architecture
+}
+
+// RequiredPociPlatforms are the platforms cmd/validate requires a poci
+// tool's container image to publish.
+var RequiredPociPlatforms = []Platform{
+	{OS: "linux", Architecture: "amd64"},
+	{OS: "linux", Architecture: "arm64"},
+}
+
+// ManifestSummary is what a caller needs to decide whether an image
+// reference is acceptable without pulling any layer data: its resolved
+// digest, the platforms it actually publishes, and the total size of its
+// layers as reported by the registry.
+type ManifestSummary struct {
+	// Digest is ref's resolved digest: the manifest list/index digest for
+	// a multi-platform image, or the single manifest's digest otherwise.
+	Digest string
+	// Platforms lists every platform this reference publishes a manifest
+	// for, out of RequiredPociPlatforms.
+	Platforms []Platform
+	// LayerBytes sums every inspected platform manifest's layer sizes, as
+	// reported by the registry's manifest metadata - no layer blobs are
+	// fetched, so this is the compressed size on the wire, not the
+	// uncompressed size on disk.
+	LayerBytes int64
+}
+
+// HasPlatform reports whether s.Platforms contains p.
+func (s ManifestSummary) HasPlatform(p Platform) bool {
+	for _, have := range s.Platforms {
+		if have == p {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectManifest resolves ref (as Inspect does) and summarizes its
+// manifest: which of RequiredPociPlatforms it publishes and the combined
+// layer size across them. It's the daemonless replacement for a `docker
+// pull` run just to confirm an image exists and measure its size.
+func InspectManifest(ctx context.Context, ref string) (ManifestSummary, error) {
+	r, err := reference.Parse(ref)
+	if err != nil {
+		return ManifestSummary{}, err
+	}
+	r = r.Normalize()
+
+	client := &Client{httpClient: http.DefaultClient}
+	if creds, err := credentials.LookupDocker(r.Domain); err == nil {
+		client.username = creds.Username
+		client.password = creds.Secret
+	}
+
+	tagOrDigest := r.Tag
+	if r.Digest != "" {
+		tagOrDigest = r.Digest
+	}
+
+	raw, m, err := client.getManifestRaw(ctx, r.Domain, r.Path, tagOrDigest)
+	if err != nil {
+		return ManifestSummary{}, err
+	}
+
+	summary := ManifestSummary{Digest: digestOf(raw)}
+
+	if len(m.Manifests) == 0 {
+		// A single-platform manifest carries no platform field of its
+		// own; report it against every required platform we can't rule
+		// out, and fold its layers into the budget once.
+		summary.LayerBytes = layerBytes(m)
+		summary.Platforms = RequiredPociPlatforms
+		return summary, nil
+	}
+
+	for _, required := range RequiredPociPlatforms {
+		var digest string
+		for _, candidate := range m.Manifests {
+			if candidate.Platform.OS == required.OS && candidate.Platform.Architecture == required.Architecture {
+				digest = candidate.Digest
+				break
+			}
+		}
+		if digest == "" {
+			continue
+		}
+
+		platformManifest, err := client.getManifest(ctx, r.Domain, r.Path, digest)
+		if err != nil {
+			return ManifestSummary{}, err
+		}
+
+		summary.Platforms = append(summary.Platforms, required)
+		summary.LayerBytes += layerBytes(platformManifest)
+	}
+
+	return summary, nil
+}
+
+// layerBytes sums a manifest's reported layer sizes.
+func layerBytes(m manifest) int64 {
+	var total int64
+	for _, layer := range m.Layers {
+		total += layer.Size
+	}
+	return total
+}
+
+// digestOf returns the sha256 digest of raw in "sha256:<hex>" form, the
+// same value the registry would report as the manifest's own digest.
+func digestOf(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// getManifestRaw is like getManifest but also returns the exact bytes the
+// registry sent, so the caller can compute the manifest's own digest.
+func (c *Client) getManifestRaw(ctx context.Context, domain, path, tagOrDigest string) ([]byte, manifest, error) {
+	var raw []byte
+	var m manifest
+	err := c.do(ctx, domain, path, "manifests", tagOrDigest, manifestAccept, func(resp *http.Response) error {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		raw = body
+		return json.Unmarshal(body, &m)
+	})
+	if err != nil {
+		return nil, manifest{}, fmt.Errorf("ociinspect: fetching manifest for %s/%s:%s: %w", domain, path, tagOrDigest, err)
+	}
+	return raw, m, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package reference parses and normalizes OCI image references (the
+// `[domain/]path[:tag][@digest]` grammar used by docker and other OCI
+// tools), so that the rest of the registry can validate and compare image
+// references without shelling out to `docker inspect` just to check syntax.
+package reference
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultDomain = "docker.io"
+	defaultTag    = "latest"
+)
+
+// domainPattern matches a reference domain: a DNS-ish host, optionally with
+// a port, that is distinguished from the first path component by containing
+// a '.', a ':', or being "localhost".
+var (
+	pathComponentPattern = regexp.MustCompile(`^[a-z0-9]+((?:[._]|__|[-]+)[a-z0-9]+)*$`)
+	tagPattern           = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestPattern        = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9]{32,}$`)
+)
+
+// Reference is a parsed OCI image reference.
+type Reference struct {
+	Domain string // e.g. "docker.io", "ghcr.io"
+	Path   string // e.g. "library/neo4j", "mcp/fetch"
+	Tag    string // e.g. "latest"; empty if Digest is set and no tag was given
+	Digest string // e.g. "sha256:<hex>"; empty if not pinned
+}
+
+// Parse validates s against the OCI reference grammar and splits it into
+// domain, path, tag and digest. Either Tag or Digest (or both) may be set;
+// at least one always is once Normalize has been applied.
+func Parse(s string) (Reference, error) {
+	if s == "" {
+		return Reference{}, fmt.Errorf("reference: empty reference")
+	}
+
+	remainder := s
+	var digest string
+	if idx := strings.LastIndex(remainder, "@"); idx != -1 {
+		digest = remainder[idx+1:]
+		remainder = remainder[:idx]
+		if !digestPattern.MatchString(digest) {
+			return Reference{}, fmt.Errorf("reference: invalid digest %q", digest)
+		}
+	}
+
+	// A tag is the last ':'-separated component of the last '/'-separated
+	// component, as long as it doesn't look like a port on the domain.
+	var tag string
+	lastSlash := strings.LastIndex(remainder, "/")
+	lastColon := strings.LastIndex(remainder, ":")
+	if lastColon != -1 && lastColon > lastSlash {
+		tag = remainder[lastColon+1:]
+		remainder = remainder[:lastColon]
+		if !tagPattern.MatchString(tag) {
+			return Reference{}, fmt.Errorf("reference: invalid tag %q", tag)
+		}
+	}
+
+	if remainder == "" {
+		return Reference{}, fmt.Errorf("reference: missing repository path in %q", s)
+	}
+
+	domain, path := splitDomain(remainder)
+	for _, component := range strings.Split(path, "/") {
+		if !pathComponentPattern.MatchString(component) {
+			return Reference{}, fmt.Errorf("reference: invalid path component %q in %q", component, s)
+		}
+	}
+
+	return Reference{Domain: domain, Path: path, Tag: tag, Digest: digest}, nil
+}
+
+// splitDomain separates a leading registry domain from the repository path,
+// following the same heuristic as docker/distribution: the first path
+// component is a domain if it contains a '.' or ':', or is "localhost".
+func splitDomain(s string) (domain, path string) {
+	idx := strings.Index(s, "/")
+	if idx == -1 {
+		return "", s
+	}
+
+	first := s[:idx]
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first, s[idx+1:]
+	}
+
+	return "", s
+}
+
+// Normalize fills in the default domain ("docker.io") and tag ("latest")
+// when they were not present in the original reference, mirroring how
+// docker resolves a short reference like "postgres" to
+// "docker.io/library/postgres:latest". A reference pinned by digest keeps
+// its tag empty unless one was explicitly given.
+func (r Reference) Normalize() Reference {
+	n := r
+
+	if n.Domain == "" {
+		n.Domain = defaultDomain
+		if !strings.Contains(n.Path, "/") {
+			n.Path = "library/" + n.Path
+		}
+	}
+
+	if n.Tag == "" && n.Digest == "" {
+		n.Tag = defaultTag
+	}
+
+	return n
+}
+
+// String renders the reference back into `domain/path[:tag][@digest]` form.
+func (r Reference) String() string {
+	var b strings.Builder
+	if r.Domain != "" {
+		b.WriteString(r.Domain)
+		b.WriteString("/")
+	}
+	b.WriteString(r.Path)
+	if r.Tag != "" {
+		b.WriteString(":")
+		b.WriteString(r.Tag)
+	}
+	if r.Digest != "" {
+		b.WriteString("@")
+		b.WriteString(r.Digest)
+	}
+	return b.String()
+}
+
+// WithDigest returns a copy of the reference pinned to digest, keeping the
+// existing tag (if any) alongside it, e.g. "mcp/fetch:latest@sha256:...".
+func (r Reference) WithDigest(digest string) (Reference, error) {
+	if !digestPattern.MatchString(digest) {
+		return Reference{}, fmt.Errorf("reference: invalid digest %q", digest)
+	}
+	pinned := r
+	pinned.Digest = digest
+	return pinned, nil
+}
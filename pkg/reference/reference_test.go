@@ -0,0 +1,59 @@
+package reference
+
+import "testing"
+
+func TestParseAndNormalize(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantErr  bool
+		wantNorm string
+	}{
+		{in: "mcp/fetch", wantNorm: "docker.io/mcp/fetch:latest"},
+		{in: "postgres", wantNorm: "docker.io/library/postgres:latest"},
+		{in: "ghcr.io/org/tool:v1.2.3", wantNorm: "ghcr.io/org/tool:v1.2.3"},
+		{in: "mcp/fetch@sha256:" + sampleDigestHex, wantNorm: "docker.io/mcp/fetch@sha256:" + sampleDigestHex},
+		{in: "", wantErr: true},
+		{in: "UPPER/case", wantErr: true},
+		{in: "mcp/fetch@not-a-digest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ref, err := Parse(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q): expected error, got none", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got := ref.Normalize().String(); got != tt.wantNorm {
+			t.Errorf("Parse(%q).Normalize().String() = %q, want %q", tt.in, got, tt.wantNorm)
+		}
+	}
+}
+
+func TestWithDigest(t *testing.T) {
+	ref, err := Parse("mcp/fetch:latest")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	pinned, err := ref.WithDigest("sha256:" + sampleDigestHex)
+	if err != nil {
+		t.Fatalf("WithDigest: %v", err)
+	}
+
+	want := "mcp/fetch:latest@sha256:" + sampleDigestHex
+	if got := pinned.String(); got != want {
+		t.Errorf("WithDigest().String() = %q, want %q", got, want)
+	}
+
+	if _, err := ref.WithDigest("sha256:short"); err == nil {
+		t.Error("WithDigest with a too-short digest: expected error, got none")
+	}
+}
+
+const sampleDigestHex = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85"
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// linuxCapabilityVersion3 is _LINUX_CAPABILITY_VERSION_3 from
+// linux/capability.h, the only version still handled by modern kernels
+// that covers the full 64-bit capability space (two 32-bit words).
+const linuxCapabilityVersion3 = 0x20080522
+
+// capLastCap is CAP_LAST_CAP as of recent kernels (CAP_CHECKPOINT_RESTORE,
+// 40). Bounding-set drop calls beyond whatever the running kernel actually
+// defines simply fail with EINVAL, which dropCapabilities ignores, so a
+// newer kernel with more capabilities than this constant just leaves the
+// highest few undropped from the bounding set - they're still cleared from
+// the effective/permitted/inheritable sets by capset below regardless.
+const capLastCap = 40
+
+// capUserHeader mirrors struct __user_cap_header_struct.
+type capUserHeader struct {
+	version uint32
+	pid     int32
+}
+
+// capUserData mirrors struct __user_cap_data_struct. Version 3 packs 64
+// capability bits into two of these, index 0 for bits 0-31 and index 1 for
+// bits 32-63.
+type capUserData struct {
+	effective   uint32
+	permitted   uint32
+	inheritable uint32
+}
+
+// setNoNewPrivs sets PR_SET_NO_NEW_PRIVS, a prerequisite (absent
+// CAP_SYS_ADMIN, which dropCapabilities removes anyway) for installing the
+// seccomp filter below, and a standalone hardening measure in its own
+// right: it keeps the agent and anything it execs from gaining privileges
+// through a setuid/setcap binary.
+func setNoNewPrivs() error {
+	return unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+}
+
+// dropCapabilities empties the process's entire capability state: every
+// bounding-set capability, then the effective/permitted/inheritable sets
+// via capset(2), then the ambient set. The agent (and anything it execs)
+// is left with no capabilities at all, matching an ordinary unprivileged
+// process regardless of what the reviewer container itself was started
+// with.
+func dropCapabilities() error {
+	for capability := 0; capability <= capLastCap; capability++ {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("drop bounding capability %d: %w", capability, err)
+		}
+	}
+
+	header := capUserHeader{version: linuxCapabilityVersion3, pid: 0}
+	var data [2]capUserData
+	if _, _, errno := unix.Syscall(unix.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return fmt.Errorf("capset: %w", errno)
+	}
+
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("clear ambient capabilities: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Landlock filesystem access rights (ABI v1), from linux/landlock.h. Only
+// the filesystem bits exist at ABI v1, which is all this package asks for.
+const (
+	landlockAccessFSExecute    = 1 << 0
+	landlockAccessFSWriteFile  = 1 << 1
+	landlockAccessFSReadFile   = 1 << 2
+	landlockAccessFSReadDir    = 1 << 3
+	landlockAccessFSRemoveDir  = 1 << 4
+	landlockAccessFSRemoveFile = 1 << 5
+	landlockAccessFSMakeChar   = 1 << 6
+	landlockAccessFSMakeDir    = 1 << 7
+	landlockAccessFSMakeReg    = 1 << 8
+	landlockAccessFSMakeSock   = 1 << 9
+	landlockAccessFSMakeFifo   = 1 << 10
+	landlockAccessFSMakeBlock  = 1 << 11
+	landlockAccessFSMakeSym    = 1 << 12
+)
+
+// landlockAccessFSReadOnly is granted for ReadOnlyPath: traversal and read,
+// nothing that creates, removes, or modifies content.
+const landlockAccessFSReadOnly = landlockAccessFSExecute | landlockAccessFSReadFile | landlockAccessFSReadDir
+
+// landlockAccessFSFull is granted for WritablePaths: every ABI v1 right.
+const landlockAccessFSFull = landlockAccessFSReadOnly | landlockAccessFSWriteFile |
+	landlockAccessFSRemoveDir | landlockAccessFSRemoveFile | landlockAccessFSMakeChar |
+	landlockAccessFSMakeDir | landlockAccessFSMakeReg | landlockAccessFSMakeSock |
+	landlockAccessFSMakeFifo | landlockAccessFSMakeBlock | landlockAccessFSMakeSym
+
+// landlockRuleTypePathBeneath is LANDLOCK_RULE_PATH_BENEATH.
+const landlockRuleTypePathBeneath = 1
+
+// landlockRulesetAttr mirrors struct landlock_ruleset_attr (ABI v1, a
+// single handled_access_fs field; later ABIs append handled_access_net,
+// which this package doesn't need).
+type landlockRulesetAttr struct {
+	handledAccessFS uint64
+}
+
+// landlockPathBeneathAttr mirrors struct landlock_path_beneath_attr.
+type landlockPathBeneathAttr struct {
+	allowedAccess uint64
+	parentFD      int32
+}
+
+// restrictFilesystem makes readOnlyPath read-only to this process and
+// everything it execs, while writablePaths keep full access. It tries
+// Landlock first (unprivileged, available since Linux 5.13) and falls back
+// to a private bind mount (which needs CAP_SYS_ADMIN, still available at
+// this point since dropCapabilities hasn't run yet) when the kernel
+// doesn't support Landlock.
+func restrictFilesystem(readOnlyPath string, writablePaths []string) error {
+	if readOnlyPath == "" {
+		return nil
+	}
+
+	err := restrictFilesystemLandlock(readOnlyPath, writablePaths)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, unix.ENOSYS) {
+		return err
+	}
+
+	logWarn("Landlock unavailable (%v), falling back to a bind mount", err)
+	return bindMountReadOnly(readOnlyPath)
+}
+
+// restrictFilesystemLandlock builds a single Landlock ruleset granting
+// landlockAccessFSReadOnly under readOnlyPath and landlockAccessFSFull
+// under each of writablePaths, then applies it to the calling thread with
+// landlock_restrict_self. Every access right not explicitly granted by a
+// rule is denied everywhere once the ruleset is in effect, so
+// writablePaths must be listed even when they aren't beneath readOnlyPath.
+func restrictFilesystemLandlock(readOnlyPath string, writablePaths []string) error {
+	attr := landlockRulesetAttr{handledAccessFS: landlockAccessFSFull}
+	rulesetFD, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("landlock_create_ruleset: %w", errno)
+	}
+	defer unix.Close(int(rulesetFD))
+
+	if err := addLandlockPathRule(int(rulesetFD), readOnlyPath, landlockAccessFSReadOnly); err != nil {
+		return err
+	}
+	for _, path := range writablePaths {
+		if err := addLandlockPathRule(int(rulesetFD), path, landlockAccessFSFull); err != nil {
+			return err
+		}
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, rulesetFD, 0, 0); errno != 0 {
+		return fmt.Errorf("landlock_restrict_self: %w", errno)
+	}
+	return nil
+}
+
+// addLandlockPathRule grants access to path under rulesetFD.
+func addLandlockPathRule(rulesetFD int, path string, accessFS uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("open %s for landlock rule: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	attr := landlockPathBeneathAttr{allowedAccess: accessFS, parentFD: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFD), landlockRuleTypePathBeneath, uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("landlock_add_rule %s: %w", path, errno)
+	}
+	return nil
+}
+
+// bindMountReadOnly makes path read-only for this process by unsharing a
+// private mount namespace (so the change never escapes to the host or
+// sibling containers), bind-mounting path over itself, and remounting that
+// bind read-only - the standard two-step MS_BIND/MS_REMOUNT|MS_RDONLY
+// dance, since Linux doesn't honor MS_RDONLY on the initial bind mount.
+func bindMountReadOnly(path string) error {
+	if err := unix.Unshare(unix.CLONE_NEWNS); err != nil {
+		return fmt.Errorf("unshare mount namespace: %w", err)
+	}
+	if err := unix.Mount("", "/", "", unix.MS_PRIVATE|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("make mount namespace private: %w", err)
+	}
+	if err := unix.Mount(path, path, "", unix.MS_BIND, ""); err != nil {
+		return fmt.Errorf("bind mount %s: %w", path, err)
+	}
+	if err := unix.Mount("", path, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY, ""); err != nil {
+		return fmt.Errorf("remount %s read-only: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package sandbox confines the reviewer agent process the security-reviewer
+// shells out to: an LLM agent with shell tool access, running untrusted
+// third-party repository content through its context. On Linux, Wrap
+// re-execs the calling binary through a trampoline that, before the real
+// agent is exec'd, drops all capabilities, sets NoNewPrivs, installs a
+// seccomp syscall allowlist, and uses Landlock (falling back to a bind
+// mount) to make the repository checkout read-only while leaving the
+// agent's input/output directories writable. Go's os/exec has no hook for
+// any of this - the restrictions have to be applied by the child itself
+// between fork and exec - hence the trampoline. Anything other than Linux
+// degrades to running the agent unsandboxed, with a warning.
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Mode selects how strictly Wrap enforces the sandbox.
+type Mode string
+
+const (
+	// ModeOff runs the agent with no sandboxing at all.
+	ModeOff Mode = "off"
+	// ModeStrict applies every available restriction and fails the run if
+	// any of them can't be applied (unsupported kernel, missing
+	// privilege, unsupported platform).
+	ModeStrict Mode = "strict"
+	// ModePermissive applies whatever restrictions are available and logs
+	// a warning for any that aren't, but never fails the run because of it.
+	ModePermissive Mode = "permissive"
+)
+
+// DefaultMode is used when REVIEW_SANDBOX is unset.
+const DefaultMode = ModeStrict
+
+// ParseMode validates a REVIEW_SANDBOX value, case-insensitively, and
+// falls back to DefaultMode for an empty string.
+func ParseMode(value string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case "":
+		return DefaultMode, nil
+	case ModeOff:
+		return ModeOff, nil
+	case ModeStrict:
+		return ModeStrict, nil
+	case ModePermissive:
+		return ModePermissive, nil
+	default:
+		return "", fmt.Errorf("invalid sandbox mode %q (want %s, %s, or %s)", value, ModeOff, ModeStrict, ModePermissive)
+	}
+}
+
+// Config describes the sandbox a single agent invocation should run under.
+type Config struct {
+	// Mode selects enforcement behavior; see the Mode constants.
+	Mode Mode
+	// ReadOnlyPath is made read-only inside the sandbox - the repository
+	// checkout under review, which the agent should only need to read.
+	ReadOnlyPath string
+	// WritablePaths remain fully writable even though they may be nested
+	// under ReadOnlyPath, e.g. the reviewer's input/output directories.
+	WritablePaths []string
+	// Env is the exact environment passed to the sandboxed process. It
+	// should already be filtered to an explicit allowlist (see Environ),
+	// not a copy of the parent's full environment.
+	Env []string
+}
+
+// Environ builds a minimal environment for the sandboxed agent: every
+// variable named in allow that's set in the current process, plus any
+// variable whose name starts with "REVIEW_" (the reviewer's own config
+// knobs, which some agents re-read, e.g. a custom prompt template path).
+// It deliberately does not return os.Environ() in full - that would hand
+// the untrusted agent whatever else happens to be set in the container,
+// from unrelated credentials to orchestration bookkeeping.
+func Environ(allow ...string) []string {
+	var env []string
+	seen := make(map[string]bool, len(allow))
+	for _, name := range allow {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	for _, kv := range os.Environ() {
+		name, _, ok := strings.Cut(kv, "=")
+		if !ok || seen[name] || !strings.HasPrefix(name, "REVIEW_") {
+			continue
+		}
+		seen[name] = true
+		env = append(env, kv)
+	}
+	return env
+}
+
+// logWarn prints a sandbox warning in the same style as the reviewer's own
+// logging, without this package having to depend on the reviewer's logger.
+func logWarn(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "[sandbox] WARNING: %s\n", fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/docker/docker/pkg/reexec"
+)
+
+func init() {
+	reexec.Register(trampolineName, trampolineMain)
+}
+
+// Init must be called as the first thing in main(). It returns true when
+// the current process is actually the sandbox trampoline dispatched to by
+// reexec - in which case trampolineMain has already applied the sandbox
+// and exec'd the real agent (or exited on failure), and the caller should
+// simply return rather than running its normal startup.
+func Init() bool {
+	return reexec.Init()
+}
+
+// Wrap re-execs cmd's program through the sandbox trampoline registered by
+// Init. The returned *exec.Cmd replaces cmd entirely: its Path becomes the
+// reexec'd copy of this binary, and its Args carry cfg and cmd's original
+// Path/Args through to the trampoline. cmd.Stdin/Stdout/Stderr/Dir are
+// preserved; cmd.Env is replaced by cfg.Env.
+func Wrap(cmd *exec.Cmd, cfg Config) (*exec.Cmd, error) {
+	if cfg.Mode == ModeOff {
+		return cmd, nil
+	}
+
+	trampoline := trampolineArgs{
+		mode:          cfg.Mode,
+		readOnlyPath:  cfg.ReadOnlyPath,
+		writablePaths: cfg.WritablePaths,
+		execPath:      cmd.Path,
+		argv:          cmd.Args,
+	}
+
+	wrapped := reexec.Command(append([]string{trampolineName}, trampoline.encode()...)...)
+	wrapped.Stdin = cmd.Stdin
+	wrapped.Stdout = cmd.Stdout
+	wrapped.Stderr = cmd.Stderr
+	wrapped.Dir = cmd.Dir
+	wrapped.Env = cfg.Env
+	return wrapped, nil
+}
+
+// trampolineMain is the reexec entry point: it applies the sandbox
+// described by os.Args[1:] (see trampolineArgs) and then replaces this
+// process's image with the real agent command. It never returns normally -
+// either syscall.Exec succeeds and this code stops existing, or something
+// fails and the function exits the process directly, since there is no
+// caller left to hand an error to once reexec has dispatched here.
+func trampolineMain() {
+	args, err := decodeTrampolineArgs(os.Args[1:])
+	if err != nil {
+		fatal(err)
+	}
+
+	if err := restrictSelf(args.mode, args.readOnlyPath, args.writablePaths); err != nil {
+		if args.mode == ModeStrict {
+			fatal(fmt.Errorf("sandbox: %w", err))
+		}
+		logWarn("%v (continuing unsandboxed for this layer)", err)
+	}
+
+	env := os.Environ()
+	if err := syscall.Exec(args.execPath, args.argv, env); err != nil {
+		fatal(fmt.Errorf("sandbox: exec %s: %w", args.execPath, err))
+	}
+}
+
+// restrictSelf applies, in order, the filesystem restriction, NoNewPrivs,
+// capability drop, and seccomp filter. Filesystem restriction runs first
+// because the bind-mount fallback needs CAP_SYS_ADMIN, which the
+// capability drop below removes. The seccomp filter runs last because
+// installing it forecloses some of the syscalls the earlier steps use.
+func restrictSelf(mode Mode, readOnlyPath string, writablePaths []string) error {
+	if err := restrictFilesystem(readOnlyPath, writablePaths); err != nil {
+		return fmt.Errorf("restrict filesystem: %w", err)
+	}
+	if err := setNoNewPrivs(); err != nil {
+		return fmt.Errorf("set NoNewPrivs: %w", err)
+	}
+	if err := dropCapabilities(); err != nil {
+		return fmt.Errorf("drop capabilities: %w", err)
+	}
+	if err := installSeccompFilter(mode); err != nil {
+		return fmt.Errorf("install seccomp filter: %w", err)
+	}
+	return nil
+}
+
+// fatal reports err in the reviewer's log style and terminates the
+// process. Used from the trampoline, which has no caller to return an
+// error to.
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "[sandbox] ERROR: %v\n", err)
+	os.Exit(1)
+}
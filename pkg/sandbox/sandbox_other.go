@@ -0,0 +1,51 @@
+//go:build !linux
+
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Init always returns false on non-Linux platforms: there is no
+// trampoline to dispatch to, since Wrap never creates one.
+func Init() bool {
+	return false
+}
+
+// Wrap returns cmd unchanged on non-Linux platforms, after warning that
+// none of the requested sandboxing is available here - seccomp, Landlock,
+// and the bind-mount fallback are all Linux-specific. This applies
+// regardless of Mode, including ModeStrict: REVIEW_SANDBOX is about
+// confining what the agent can do, not about making the review fail on
+// platforms that can't provide that confinement.
+func Wrap(cmd *exec.Cmd, cfg Config) (*exec.Cmd, error) {
+	if cfg.Mode == ModeOff {
+		return cmd, nil
+	}
+	logWarn("sandboxing is not supported on %s; running the agent unsandboxed", runtime.GOOS)
+	cmd.Env = cfg.Env
+	return cmd, nil
+}
@@ -0,0 +1,115 @@
+package sandbox
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    Mode
+		wantErr bool
+	}{
+		{"", DefaultMode, false},
+		{"off", ModeOff, false},
+		{"Strict", ModeStrict, false},
+		{" PERMISSIVE ", ModePermissive, false},
+		{"yolo", "", true},
+	}
+	for _, c := range cases {
+		got, err := ParseMode(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseMode(%q): expected error, got nil", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMode(%q): %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseMode(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestEnvironAllowlistsAndPrefix(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+	t.Setenv("REVIEW_SARIF_PATH", "/workspace/output/report.sarif")
+	t.Setenv("SOME_OTHER_VAR", "nope")
+
+	env := Environ("PATH", "ANTHROPIC_API_KEY")
+
+	for _, want := range []string{"PATH=/usr/bin", "ANTHROPIC_API_KEY=sk-test", "REVIEW_SARIF_PATH=/workspace/output/report.sarif"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("Environ() = %v, want %q", env, want)
+		}
+	}
+	for _, kv := range env {
+		if kv == "SOME_OTHER_VAR=nope" {
+			t.Errorf("Environ() forwarded SOME_OTHER_VAR, want it dropped: %v", env)
+		}
+	}
+}
+
+func TestEnvironSkipsUnsetAllowedNames(t *testing.T) {
+	if env := Environ("DEFINITELY_NOT_SET_ANYWHERE"); len(env) != 0 {
+		t.Errorf("Environ() = %v, want empty for an unset allowed name", env)
+	}
+}
+
+func TestTrampolineArgsRoundTrip(t *testing.T) {
+	in := trampolineArgs{
+		mode:          ModeStrict,
+		readOnlyPath:  "/workspace/input/repository",
+		writablePaths: []string{"/workspace/input", "/workspace/output"},
+		execPath:      "/usr/local/bin/claude",
+		argv:          []string{"claude", "--print", "--model", "claude-opus"},
+	}
+
+	out, err := decodeTrampolineArgs(in.encode())
+	if err != nil {
+		t.Fatalf("decodeTrampolineArgs: %v", err)
+	}
+	if out.mode != in.mode {
+		t.Errorf("mode = %q, want %q", out.mode, in.mode)
+	}
+	if out.readOnlyPath != in.readOnlyPath {
+		t.Errorf("readOnlyPath = %q, want %q", out.readOnlyPath, in.readOnlyPath)
+	}
+	if !slices.Equal(out.writablePaths, in.writablePaths) {
+		t.Errorf("writablePaths = %v, want %v", out.writablePaths, in.writablePaths)
+	}
+	if out.execPath != in.execPath {
+		t.Errorf("execPath = %q, want %q", out.execPath, in.execPath)
+	}
+	if !slices.Equal(out.argv, in.argv) {
+		t.Errorf("argv = %v, want %v", out.argv, in.argv)
+	}
+}
+
+func TestTrampolineArgsRoundTripNoWritablePaths(t *testing.T) {
+	in := trampolineArgs{
+		mode:         ModePermissive,
+		readOnlyPath: "/workspace/input/repository",
+		execPath:     "/usr/local/bin/codex",
+		argv:         []string{"codex"},
+	}
+
+	out, err := decodeTrampolineArgs(in.encode())
+	if err != nil {
+		t.Fatalf("decodeTrampolineArgs: %v", err)
+	}
+	if len(out.writablePaths) != 0 {
+		t.Errorf("writablePaths = %v, want empty", out.writablePaths)
+	}
+}
+
+func TestDecodeTrampolineArgsRejectsShortInput(t *testing.T) {
+	if _, err := decodeTrampolineArgs([]string{"strict", "/repo"}); err == nil {
+		t.Fatal("decodeTrampolineArgs: expected error for too-short input, got nil")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Classic BPF opcodes used by the filter below (linux/bpf_common.h). Named
+// individually rather than pulled from a BPF library, since this is the
+// entire extent of what the filter needs.
+const (
+	bpfLdW  = 0x00 | 0x20 | 0x80 // BPF_LD | BPF_W | BPF_ABS
+	bpfJeqK = 0x05 | 0x10 | 0x00 // BPF_JMP | BPF_JEQ | BPF_K
+	bpfRetK = 0x06 | 0x00        // BPF_RET | BPF_K
+)
+
+// seccomp_data field offsets (linux/seccomp.h): the filter only ever needs
+// the syscall number and the calling process's audit architecture.
+const (
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+)
+
+// auditArchX86_64 is AUDIT_ARCH_X86_64 from linux/audit.h. Reviewer images
+// only ship for x86_64 today (see pkg/builder), so the filter rejects
+// anything built/run under a different syscall ABI (notably a 32-bit
+// compat call) outright rather than trying to cover it.
+const auditArchX86_64 = 0xc000003e
+
+// seccomp(2) mode/flag constants (linux/seccomp.h).
+const (
+	seccompSetModeFilter = 1
+)
+
+// SECCOMP_RET_* actions (linux/seccomp.h). RET_LOG allows the call through
+// but asks the kernel to audit-log it, which is what backs ModePermissive:
+// a denied-by-default syscall is recorded rather than blocked.
+const (
+	seccompRetKillProcess = 0x80000000
+	seccompRetLog         = 0x7ffc0000
+	seccompRetAllow       = 0x7fff0000
+)
+
+// allowedSyscalls lists the syscalls a reviewer agent (an LLM CLI that
+// reads/writes files, forks a shell, and makes outbound HTTPS calls) needs
+// to run at all. It's deliberately not exhaustive of every syscall a
+// well-behaved program might use - unexpected ones are denied (or logged,
+// under ModePermissive) rather than silently added here, since an omission
+// just makes the agent fail loudly rather than opening a hole. Syscalls
+// that would let an agent escape the sandbox or disturb the host -
+// ptrace, mount/umount2, reboot, {,f,}init_module, delete_module, bpf,
+// perf_event_open, process_vm_{read,write}v, setns, kexec_load, swapon,
+// swapoff, acct, quotactl, iopl, ioperm - are intentionally absent.
+var allowedSyscalls = []uintptr{
+	unix.SYS_READ, unix.SYS_WRITE, unix.SYS_OPEN, unix.SYS_OPENAT, unix.SYS_CLOSE,
+	unix.SYS_STAT, unix.SYS_FSTAT, unix.SYS_LSTAT, unix.SYS_NEWFSTATAT, unix.SYS_STATX,
+	unix.SYS_POLL, unix.SYS_PPOLL, unix.SYS_SELECT, unix.SYS_PSELECT6,
+	unix.SYS_LSEEK, unix.SYS_MMAP, unix.SYS_MPROTECT, unix.SYS_MUNMAP, unix.SYS_BRK,
+	unix.SYS_MREMAP, unix.SYS_MSYNC, unix.SYS_MINCORE, unix.SYS_MADVISE,
+	unix.SYS_RT_SIGACTION, unix.SYS_RT_SIGPROCMASK, unix.SYS_RT_SIGRETURN,
+	unix.SYS_RT_SIGPENDING, unix.SYS_RT_SIGTIMEDWAIT, unix.SYS_RT_SIGQUEUEINFO,
+	unix.SYS_RT_SIGSUSPEND, unix.SYS_SIGALTSTACK,
+	unix.SYS_IOCTL, unix.SYS_PREAD64, unix.SYS_PWRITE64, unix.SYS_READV, unix.SYS_WRITEV,
+	unix.SYS_PREADV, unix.SYS_PWRITEV, unix.SYS_PREADV2, unix.SYS_PWRITEV2,
+	unix.SYS_ACCESS, unix.SYS_FACCESSAT, unix.SYS_FACCESSAT2,
+	unix.SYS_PIPE, unix.SYS_PIPE2, unix.SYS_DUP, unix.SYS_DUP2, unix.SYS_DUP3,
+	unix.SYS_SCHED_YIELD, unix.SYS_SCHED_GETAFFINITY, unix.SYS_SCHED_SETAFFINITY,
+	unix.SYS_NANOSLEEP, unix.SYS_CLOCK_NANOSLEEP, unix.SYS_CLOCK_GETTIME, unix.SYS_CLOCK_GETRES,
+	unix.SYS_GETPID, unix.SYS_GETTID, unix.SYS_GETPPID,
+	unix.SYS_SOCKET, unix.SYS_SOCKETPAIR, unix.SYS_CONNECT, unix.SYS_ACCEPT, unix.SYS_ACCEPT4,
+	unix.SYS_SENDTO, unix.SYS_RECVFROM, unix.SYS_SENDMSG, unix.SYS_RECVMSG, unix.SYS_RECVMMSG, unix.SYS_SENDMMSG,
+	unix.SYS_SHUTDOWN, unix.SYS_BIND, unix.SYS_LISTEN,
+	unix.SYS_GETSOCKNAME, unix.SYS_GETPEERNAME, unix.SYS_SETSOCKOPT, unix.SYS_GETSOCKOPT,
+	unix.SYS_CLONE, unix.SYS_CLONE3, unix.SYS_FORK, unix.SYS_VFORK,
+	unix.SYS_EXECVE, unix.SYS_EXECVEAT, unix.SYS_EXIT, unix.SYS_EXIT_GROUP,
+	unix.SYS_WAIT4, unix.SYS_WAITID, unix.SYS_KILL, unix.SYS_TGKILL, unix.SYS_TKILL,
+	unix.SYS_UNAME, unix.SYS_FCNTL, unix.SYS_FLOCK, unix.SYS_FSYNC, unix.SYS_FDATASYNC,
+	unix.SYS_TRUNCATE, unix.SYS_FTRUNCATE, unix.SYS_FALLOCATE,
+	unix.SYS_GETDENTS64, unix.SYS_GETCWD, unix.SYS_CHDIR, unix.SYS_FCHDIR,
+	unix.SYS_RENAME, unix.SYS_RENAMEAT, unix.SYS_RENAMEAT2,
+	unix.SYS_MKDIR, unix.SYS_MKDIRAT, unix.SYS_RMDIR, unix.SYS_CREAT,
+	unix.SYS_LINK, unix.SYS_LINKAT, unix.SYS_UNLINK, unix.SYS_UNLINKAT,
+	unix.SYS_SYMLINK, unix.SYS_SYMLINKAT, unix.SYS_READLINK, unix.SYS_READLINKAT,
+	unix.SYS_CHMOD, unix.SYS_FCHMOD, unix.SYS_FCHMODAT,
+	unix.SYS_CHOWN, unix.SYS_FCHOWN, unix.SYS_LCHOWN, unix.SYS_FCHOWNAT, unix.SYS_UMASK,
+	unix.SYS_UTIMENSAT, unix.SYS_GETTIMEOFDAY, unix.SYS_GETRLIMIT, unix.SYS_SETRLIMIT, unix.SYS_PRLIMIT64,
+	unix.SYS_GETRUSAGE, unix.SYS_SYSINFO, unix.SYS_TIMES,
+	unix.SYS_GETUID, unix.SYS_GETGID, unix.SYS_GETEUID, unix.SYS_GETEGID,
+	unix.SYS_SETPGID, unix.SYS_GETPGID, unix.SYS_GETPGRP, unix.SYS_SETSID, unix.SYS_GETSID,
+	unix.SYS_GETGROUPS, unix.SYS_CAPGET, unix.SYS_ARCH_PRCTL, unix.SYS_PRCTL,
+	unix.SYS_SET_TID_ADDRESS, unix.SYS_SET_ROBUST_LIST, unix.SYS_GET_ROBUST_LIST,
+	unix.SYS_FUTEX, unix.SYS_RESTART_SYSCALL,
+	unix.SYS_EPOLL_CREATE1, unix.SYS_EPOLL_CTL, unix.SYS_EPOLL_WAIT, unix.SYS_EPOLL_PWAIT,
+	unix.SYS_EVENTFD2, unix.SYS_SIGNALFD4, unix.SYS_TIMERFD_CREATE,
+	unix.SYS_TIMERFD_SETTIME, unix.SYS_TIMERFD_GETTIME,
+	unix.SYS_INOTIFY_INIT1, unix.SYS_INOTIFY_ADD_WATCH, unix.SYS_INOTIFY_RM_WATCH,
+	unix.SYS_SPLICE, unix.SYS_TEE, unix.SYS_SYNC_FILE_RANGE, unix.SYS_VMSPLICE, unix.SYS_COPY_FILE_RANGE,
+	unix.SYS_GETRANDOM, unix.SYS_MEMFD_CREATE, unix.SYS_MEMBARRIER, unix.SYS_MLOCK, unix.SYS_MUNLOCK,
+	unix.SYS_GETXATTR, unix.SYS_LGETXATTR, unix.SYS_FGETXATTR, unix.SYS_LISTXATTR,
+	unix.SYS_SETXATTR, unix.SYS_LSETXATTR, unix.SYS_FSETXATTR,
+	unix.SYS_GETCPU, unix.SYS_SCHED_GETPARAM, unix.SYS_SCHED_SETPARAM,
+	unix.SYS_IOPRIO_GET, unix.SYS_IOPRIO_SET,
+}
+
+// bpfFilter appends a load/jump/return instruction to program.
+func bpfStmt(program []unix.SockFilter, code uint16, k uint32) []unix.SockFilter {
+	return append(program, unix.SockFilter{Code: code, K: k})
+}
+
+func bpfJump(program []unix.SockFilter, code uint16, k uint32, jt, jf uint8) []unix.SockFilter {
+	return append(program, unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k})
+}
+
+// buildSeccompProgram compiles allowedSyscalls (plus the x86_64 arch
+// check) into a classic BPF program, with defaultAction taken for any
+// syscall not on the list. Every jump in the generated program covers
+// exactly one instruction, by construction - each allowed syscall gets its
+// own "RET_ALLOW" landing pad immediately below its comparison - so the
+// list can grow arbitrarily long without the 8-bit jt/jf jump-distance
+// limit classic BPF imposes ever coming into play.
+func buildSeccompProgram(defaultAction uint32) []unix.SockFilter {
+	var program []unix.SockFilter
+
+	program = bpfStmt(program, bpfLdW, seccompDataArchOffset)
+	// jt=1 skips over the single KILL instruction that follows when the
+	// arch matches; jf=0 falls straight into it otherwise.
+	program = bpfJump(program, bpfJeqK, auditArchX86_64, 1, 0)
+	program = bpfStmt(program, bpfRetK, seccompRetKillProcess)
+
+	program = bpfStmt(program, bpfLdW, seccompDataNrOffset)
+	for _, nr := range allowedSyscalls {
+		// jf=1 skips the RET_ALLOW landing pad when this syscall doesn't
+		// match, falling through to the next check; jt=0 falls straight
+		// into the landing pad when it does.
+		program = bpfJump(program, bpfJeqK, uint32(nr), 0, 1)
+		program = bpfStmt(program, bpfRetK, seccompRetAllow)
+	}
+	program = bpfStmt(program, bpfRetK, defaultAction)
+
+	return program
+}
+
+// installSeccompFilter compiles and installs the syscall allowlist.
+// ModeStrict kills the process outright on any syscall not in
+// allowedSyscalls; ModePermissive allows it through but asks the kernel to
+// audit-log it, so an incomplete allowlist degrades to observability
+// rather than breaking the agent.
+func installSeccompFilter(mode Mode) error {
+	defaultAction := uint32(seccompRetKillProcess)
+	if mode == ModePermissive {
+		defaultAction = seccompRetLog
+	}
+
+	program := buildSeccompProgram(defaultAction)
+	fprog := unix.SockFprog{
+		Len:    uint16(len(program)),
+		Filter: &program[0],
+	}
+
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, 0, uintptr(unsafe.Pointer(&fprog)))
+	if errno != 0 {
+		return fmt.Errorf("seccomp: %w", errno)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package sandbox
+
+import "testing"
+
+// TestBuildSeccompProgramJumpsAreInRange guards the invariant
+// buildSeccompProgram relies on instead of a generic jump-distance
+// computation: every jt/jf in the generated program is 0 or 1, so the
+// 8-bit classic BPF jump field can never overflow no matter how long
+// allowedSyscalls grows.
+func TestBuildSeccompProgramJumpsAreInRange(t *testing.T) {
+	program := buildSeccompProgram(seccompRetKillProcess)
+	for i, instr := range program {
+		if instr.Jt > 1 || instr.Jf > 1 {
+			t.Fatalf("instruction %d: Jt=%d Jf=%d, want both <= 1", i, instr.Jt, instr.Jf)
+		}
+	}
+}
+
+func TestBuildSeccompProgramLength(t *testing.T) {
+	// 3 instructions for the arch check + 1 load of the syscall number +
+	// 2 instructions per allowed syscall + 1 trailing default RET.
+	want := 3 + 1 + 2*len(allowedSyscalls) + 1
+	if got := len(buildSeccompProgram(seccompRetKillProcess)); got != want {
+		t.Errorf("len(buildSeccompProgram(...)) = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sandbox
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// trampolineName is the reexec-registered entry point the sandboxed child
+// dispatches to (see sandbox_linux.go's init). It's only ever compared
+// against argv[0] internally, so its exact value doesn't matter beyond
+// being stable and recognizable in a process listing.
+const trampolineName = "mcp-registry-security-reviewer-sandbox"
+
+// trampolineArgs is the argv the reexec'd trampoline process receives
+// (after the leading trampolineName element reexec's dispatch consumes):
+// a fixed prefix describing the sandbox to apply, followed by the real
+// agent command's own argv. It's carried entirely through argv rather
+// than the environment so the agent's final environment is exactly
+// Config.Env, with nothing extra leaked in for bookkeeping.
+type trampolineArgs struct {
+	mode          Mode
+	readOnlyPath  string
+	writablePaths []string
+	execPath      string
+	argv          []string
+}
+
+// encode renders t as the argv elements to follow trampolineName.
+func (t trampolineArgs) encode() []string {
+	out := []string{
+		string(t.mode),
+		t.readOnlyPath,
+		strings.Join(t.writablePaths, string(filepath.ListSeparator)),
+		t.execPath,
+	}
+	return append(out, t.argv...)
+}
+
+// decodeTrampolineArgs parses the argv encode produced.
+func decodeTrampolineArgs(args []string) (trampolineArgs, error) {
+	const prefixLen = 4
+	if len(args) < prefixLen {
+		return trampolineArgs{}, fmt.Errorf("sandbox: malformed trampoline args (got %d, want at least %d)", len(args), prefixLen)
+	}
+	var writable []string
+	if args[2] != "" {
+		writable = filepath.SplitList(args[2])
+	}
+	return trampolineArgs{
+		mode:          Mode(args[0]),
+		readOnlyPath:  args[1],
+		writablePaths: writable,
+		execPath:      args[3],
+		argv:          args[prefixLen:],
+	}, nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package sarif parses and validates the minimal subset of SARIF 2.1.0
+// (https://docs.oasis-open.org/sarif/sarif/v2.1.0/) that the security
+// reviewer agent and `post-review-comments` exchange: one run, one rule per
+// finding, and a single physical location per result. It intentionally does
+// not model the full spec (no code flows, no multi-run logs) since nothing
+// downstream consumes more than that yet.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Version is the SARIF schema version this package reads and writes.
+const Version = "2.1.0"
+
+// schemaURL is the canonical $schema value for SARIF 2.1.0 logs.
+const schemaURL = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// Level is the severity of a single result, as defined by the SARIF spec's
+// result.level property.
+type Level string
+
+const (
+	// LevelError marks a finding as blocking severity.
+	LevelError Level = "error"
+	// LevelWarning marks a finding as medium severity.
+	LevelWarning Level = "warning"
+	// LevelNote marks a finding as informational.
+	LevelNote Level = "note"
+)
+
+// Valid reports whether l is one of the levels this package accepts.
+func (l Level) Valid() bool {
+	switch l {
+	case LevelError, LevelWarning, LevelNote:
+		return true
+	default:
+		return false
+	}
+}
+
+// Log is the top-level SARIF document: a single tool run containing every
+// finding from one review.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run describes the tool that produced the results and the results
+// themselves.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool identifies the analysis tool, per SARIF's tool.driver object.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver names the component that produced the results.
+type Driver struct {
+	Name string `json:"name"`
+}
+
+// Result is a single finding.
+type Result struct {
+	// RuleID identifies the class of finding, e.g. "hardcoded-credential".
+	RuleID string `json:"ruleId"`
+	// Level is the finding's severity.
+	Level Level `json:"level"`
+	// Message is the human-readable description of the finding.
+	Message Message `json:"message"`
+	// Locations lists where the finding was observed. The reviewer agent is
+	// asked for exactly one, but the field stays a slice to match SARIF.
+	Locations []Location `json:"locations"`
+	// Properties is SARIF's free-form property bag. The reviewer agent
+	// never sets it; the multi-agent consolidation step in
+	// cmd/security-reviewer uses it to record its reconciliation confidence.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Message wraps a result's free text, per SARIF's message object.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location is a single physicalLocation entry.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation pinpoints a finding within a checked-out repository.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation identifies the affected file, relative to the
+// repository root under review.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is the affected line range within the artifact.
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// NewLog wraps results produced by toolName into a single-run SARIF log.
+func NewLog(toolName string, results []Result) Log {
+	return Log{
+		Schema:  schemaURL,
+		Version: Version,
+		Runs: []Run{
+			{
+				Tool:    Tool{Driver: Driver{Name: toolName}},
+				Results: results,
+			},
+		},
+	}
+}
+
+// ReadFile parses the SARIF log at path and validates every result,
+// returning an error that names the first malformed entry it finds.
+func ReadFile(path string) (Log, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Log{}, fmt.Errorf("read sarif file: %w", err)
+	}
+
+	var log Log
+	if err := json.Unmarshal(data, &log); err != nil {
+		return Log{}, fmt.Errorf("parse sarif file: %w", err)
+	}
+	if err := log.Validate(); err != nil {
+		return Log{}, err
+	}
+	return log, nil
+}
+
+// WriteFile renders l as indented JSON at path.
+func WriteFile(path string, l Log) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sarif log: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Validate checks that every run and result carries the fields this
+// package and its consumers rely on.
+func (l Log) Validate() error {
+	if l.Version != Version {
+		return fmt.Errorf("unsupported sarif version %q (expected %s)", l.Version, Version)
+	}
+	if len(l.Runs) == 0 {
+		return fmt.Errorf("sarif log has no runs")
+	}
+	for runIdx, run := range l.Runs {
+		if run.Tool.Driver.Name == "" {
+			return fmt.Errorf("run %d: tool.driver.name is required", runIdx)
+		}
+		for resultIdx, result := range run.Results {
+			if err := result.Validate(); err != nil {
+				return fmt.Errorf("run %d, result %d: %w", runIdx, resultIdx, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks a single result for the fields this package relies on.
+func (r Result) Validate() error {
+	if r.RuleID == "" {
+		return fmt.Errorf("ruleId is required")
+	}
+	if !r.Level.Valid() {
+		return fmt.Errorf("invalid level %q", r.Level)
+	}
+	if r.Message.Text == "" {
+		return fmt.Errorf("message.text is required")
+	}
+	if len(r.Locations) == 0 {
+		return fmt.Errorf("at least one location is required")
+	}
+	for locIdx, loc := range r.Locations {
+		artifact := loc.PhysicalLocation.ArtifactLocation.URI
+		if artifact == "" {
+			return fmt.Errorf("location %d: physicalLocation.artifactLocation.uri is required", locIdx)
+		}
+		region := loc.PhysicalLocation.Region
+		if region.StartLine <= 0 {
+			return fmt.Errorf("location %d: region.startLine must be positive", locIdx)
+		}
+		if region.EndLine != 0 && region.EndLine < region.StartLine {
+			return fmt.Errorf("location %d: region.endLine must not precede startLine", locIdx)
+		}
+	}
+	return nil
+}
+
+// Results flattens every result across every run, in run order.
+func (l Log) Results() []Result {
+	var results []Result
+	for _, run := range l.Runs {
+		results = append(results, run.Results...)
+	}
+	return results
+}
+
+// CountByLevel tallies l's results by severity level, for callers that want
+// to report counts (e.g. in a log line) without duplicating severity logic.
+func CountByLevel(l Log) map[Level]int {
+	counts := map[Level]int{}
+	for _, result := range l.Results() {
+		counts[result.Level]++
+	}
+	return counts
+}
+
+// HighestSeverityLabel derives a single label from the most severe level
+// present across l's results, for use where downstream automation (e.g. PR
+// labeling) wants one summary label rather than per-finding detail. It
+// returns "" when l has no results.
+func HighestSeverityLabel(l Log) string {
+	sawWarning := false
+	sawNote := false
+	for _, result := range l.Results() {
+		switch result.Level {
+		case LevelError:
+			return "security-review/error"
+		case LevelWarning:
+			sawWarning = true
+		case LevelNote:
+			sawNote = true
+		}
+	}
+	switch {
+	case sawWarning:
+		return "security-review/warning"
+	case sawNote:
+		return "security-review/note"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package scaffold renders a language-specific Dockerfile for a community
+// server whose upstream repository doesn't have one, so `task create
+// --generate-dockerfile` can still produce a buildable server.yaml instead
+// of requiring a contributor to hand-write a build recipe. Templates are
+// embedded so using this package needs no extra tooling beyond the Go
+// toolchain that already builds this repo.
+//
+// A generated Dockerfile is written to servers/<name>/Dockerfile for
+// review, but the build itself still fetches its context from the
+// upstream git repository (see pkg/builder) - a contributor needs to
+// commit the generated file upstream (or point --directory at a fork)
+// before `task build` will actually pick it up.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"text/template"
+)
+
+//go:embed templates/*.Dockerfile.tmpl
+var templatesFS embed.FS
+
+// Template identifies a language-specific Dockerfile template.
+type Template string
+
+const (
+	TemplateNode      Template = "node"
+	TemplatePythonUV  Template = "python-uv"
+	TemplatePythonPip Template = "python-pip"
+	TemplateGo        Template = "go"
+	TemplateRust      Template = "rust"
+)
+
+// manifestFiles maps each auto-detectable template to the manifest file in
+// the repository root whose presence identifies it. TemplatePythonPip has
+// no entry: pyproject.toml alone doesn't distinguish uv from pip, so Detect
+// prefers TemplatePythonUV and TemplatePythonPip must be selected explicitly.
+var manifestFiles = map[Template]string{
+	TemplateNode:     "package.json",
+	TemplatePythonUV: "pyproject.toml",
+	TemplateGo:       "go.mod",
+	TemplateRust:     "Cargo.toml",
+}
+
+// Params fills the placeholders a template substitutes into its Dockerfile.
+type Params struct {
+	// Image is the mcp/* tag the built image is labeled with.
+	Image string
+	// Entrypoint is the script, binary, or package the runtime invokes.
+	Entrypoint string
+	// InstallCmd builds or installs the project's dependencies, e.g.
+	// "npm ci --omit=dev" or "go build -o server .".
+	InstallCmd string
+	// Runtime is the interpreter or binary the image's ENTRYPOINT invokes.
+	Runtime string
+}
+
+// Detect picks the template matching the manifest file it finds first,
+// probing exists (e.g. a GitHub contents lookup, or a local os.Stat) for
+// each candidate in a fixed, deterministic order. It reports false if none
+// of the known manifest files are present.
+func Detect(exists func(path string) bool) (Template, bool) {
+	for _, tmpl := range []Template{TemplateNode, TemplatePythonUV, TemplateGo, TemplateRust} {
+		if exists(manifestFiles[tmpl]) {
+			return tmpl, true
+		}
+	}
+	return "", false
+}
+
+// Templates lists every supported template name, sorted, for flag usage
+// strings and error messages.
+func Templates() []string {
+	names := []string{string(TemplateNode), string(TemplatePythonUV), string(TemplatePythonPip), string(TemplateGo), string(TemplateRust)}
+	sort.Strings(names)
+	return names
+}
+
+// Render renders tmpl's embedded Dockerfile template with params.
+func Render(tmpl Template, params Params) (string, error) {
+	path := "templates/" + string(tmpl) + ".Dockerfile.tmpl"
+	raw, err := templatesFS.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("scaffold: unknown template %q", tmpl)
+	}
+
+	t, err := template.New(string(tmpl)).Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("scaffold: parsing template %q: %w", tmpl, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		return "", fmt.Errorf("scaffold: rendering template %q: %w", tmpl, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,64 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		present   map[string]bool
+		wantTmpl  Template
+		wantFound bool
+	}{
+		{name: "node", present: map[string]bool{"package.json": true}, wantTmpl: TemplateNode, wantFound: true},
+		{name: "python-uv", present: map[string]bool{"pyproject.toml": true}, wantTmpl: TemplatePythonUV, wantFound: true},
+		{name: "go", present: map[string]bool{"go.mod": true}, wantTmpl: TemplateGo, wantFound: true},
+		{name: "rust", present: map[string]bool{"Cargo.toml": true}, wantTmpl: TemplateRust, wantFound: true},
+		{name: "node wins over go.mod", present: map[string]bool{"package.json": true, "go.mod": true}, wantTmpl: TemplateNode, wantFound: true},
+		{name: "nothing present", present: nil, wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, found := Detect(func(path string) bool { return tt.present[path] })
+			if found != tt.wantFound {
+				t.Fatalf("Detect() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && tmpl != tt.wantTmpl {
+				t.Errorf("Detect() = %q, want %q", tmpl, tt.wantTmpl)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	out, err := Render(TemplateGo, Params{
+		Image:      "mcp/example",
+		Entrypoint: "server",
+		InstallCmd: "go build -o server .",
+		Runtime:    "/usr/local/bin/server",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{"mcp/example", "go build -o server .", "/usr/local/bin/server"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := Render("bogus", Params{}); err == nil {
+		t.Error("Render with an unknown template: expected error, got none")
+	}
+}
+
+func TestTemplatesSorted(t *testing.T) {
+	names := Templates()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("Templates() not sorted: %v", names)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package servers
+
+// Categories lists the canonical values for meta.category, shared by the
+// wizard (which offers them as a select) and cmd/validate-registry (which
+// rejects a server.yaml naming anything else).
+var Categories = []string{
+	"ai",
+	"data-visualization",
+	"database",
+	"devops",
+	"ecommerce",
+	"finance",
+	"games",
+	"communication",
+	"monitoring",
+	"productivity",
+	"search",
+}
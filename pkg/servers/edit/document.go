@@ -0,0 +1,214 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package edit makes targeted, comment- and style-preserving edits to a
+// server.yaml file. It operates on yaml.v3's *yaml.Node tree rather than
+// servers.Server, so an edit like pinning source.commit doesn't disturb
+// anything else in the file: field order, indentation, quoting, and head/
+// line comments on untouched nodes all survive a round trip, unlike
+// re-marshaling a decoded struct (or the line-splitting + regexp that
+// cmd/update-pins used to do this).
+package edit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a single server.yaml file loaded for editing.
+type Document struct {
+	path     string
+	root     *yaml.Node
+	mapping  *yaml.Node
+	original []byte
+}
+
+// Open reads and parses the server.yaml at path.
+func Open(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("edit: reading %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("edit: parsing %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("edit: %s is empty", path)
+	}
+
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("edit: %s does not have a top-level mapping", path)
+	}
+
+	return &Document{path: path, root: &root, mapping: mapping, original: data}, nil
+}
+
+// Path returns the file path the document was opened from.
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Bytes renders the document's current state back to YAML, preserving
+// whatever comments, ordering, and style survived the edits made to it.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d.root); err != nil {
+		return nil, fmt.Errorf("edit: encoding %s: %w", d.path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("edit: encoding %s: %w", d.path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Changed reports whether any edit altered the document's rendered output
+// relative to what Open read from disk.
+func (d *Document) Changed() (bool, error) {
+	rendered, err := d.Bytes()
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(rendered, d.original), nil
+}
+
+// SetCommit pins the document's source.commit field to sha.
+func (d *Document) SetCommit(sha string) error {
+	return d.SetPath("source.commit", sha)
+}
+
+// SetBranch sets the document's source.branch field to branch.
+func (d *Document) SetBranch(branch string) error {
+	return d.SetPath("source.branch", branch)
+}
+
+// SetTag pins the document's source.tag field to tag, recording the semver
+// tag source.commit was resolved from when source.track is set.
+func (d *Document) SetTag(tag string) error {
+	return d.SetPath("source.tag", tag)
+}
+
+// SetPath sets the scalar value at a dotted path of nested mapping keys
+// (e.g. "source.commit"), creating any intermediate mapping along the way
+// that doesn't already exist. It's the general case SetCommit, SetBranch,
+// and SetTag are built on; reach for it directly for a field those don't
+// already name, such as a future source.version.
+func (d *Document) SetPath(path, value string) error {
+	segments := strings.Split(path, ".")
+	if len(segments) == 0 || segments[0] == "" {
+		return fmt.Errorf("edit: invalid path %q", path)
+	}
+
+	mapping := d.mapping
+	for _, segment := range segments[:len(segments)-1] {
+		mapping = subMapping(mapping, segment)
+	}
+	setField(mapping, segments[len(segments)-1], value)
+	return nil
+}
+
+// SetImage rewrites the document's top-level image field to ref in full,
+// e.g. to pin it to a digest-qualified reference like
+// "mcp/fetch:latest@sha256:...". Reach for SetImageTag instead when only
+// the tag portion is changing.
+func (d *Document) SetImage(ref string) error {
+	_, image, found := field(d.mapping, "image")
+	if !found {
+		return fmt.Errorf("edit: %s has no image field", d.path)
+	}
+	image.Value = ref
+	return nil
+}
+
+// SetImageTag rewrites the document's top-level image field to tag,
+// preserving the repository portion (everything before the last ':' that
+// follows the last '/', so a registry host's own ':port' isn't mistaken
+// for a tag separator).
+func (d *Document) SetImageTag(tag string) error {
+	_, image, found := field(d.mapping, "image")
+	if !found {
+		return fmt.Errorf("edit: %s has no image field", d.path)
+	}
+
+	repo := image.Value
+	if idx := lastTagSeparator(repo); idx >= 0 {
+		repo = repo[:idx]
+	}
+	image.Value = repo + ":" + tag
+	return nil
+}
+
+// SetToolImageDigest pins the container image of the tools entry named
+// toolName to ref (expected to already carry the verified digest, e.g.
+// "mcp/fetch:latest@sha256:...").
+func (d *Document) SetToolImageDigest(toolName, ref string) error {
+	_, toolsNode, found := field(d.mapping, "tools")
+	if !found || toolsNode.Kind != yaml.SequenceNode {
+		return fmt.Errorf("edit: %s has no tools list", d.path)
+	}
+
+	for _, tool := range toolsNode.Content {
+		if tool.Kind != yaml.MappingNode {
+			continue
+		}
+		_, nameNode, found := field(tool, "name")
+		if !found || nameNode.Value != toolName {
+			continue
+		}
+
+		_, containerNode, found := field(tool, "container")
+		if !found || containerNode.Kind != yaml.MappingNode {
+			return fmt.Errorf("edit: %s tool %s has no container mapping", d.path, toolName)
+		}
+
+		setField(containerNode, "image", ref)
+		return nil
+	}
+
+	return fmt.Errorf("edit: %s has no tool named %s", d.path, toolName)
+}
+
+// AddSecret appends a secret requirement to the document's
+// config.secrets list.
+func (d *Document) AddSecret(secret servers.Secret) error {
+	config := subMapping(d.mapping, "config")
+	secrets := subSequence(config, "secrets")
+
+	entry := &yaml.Node{Kind: yaml.MappingNode}
+	appendField(entry, "name", secret.Name)
+	appendField(entry, "env", secret.Env)
+	if secret.Example != "" {
+		appendField(entry, "example", secret.Example)
+	}
+
+	secrets.Content = append(secrets.Content, entry)
+	return nil
+}
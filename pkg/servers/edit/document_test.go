@@ -0,0 +1,57 @@
+package edit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTempServer(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing temp server.yaml: %v", err)
+	}
+	return path
+}
+
+func TestSetPathReplacesExistingField(t *testing.T) {
+	path := writeTempServer(t, "name: example\nsource:\n  project: https://github.com/example/example\n  commit: aaaa # pinned\n")
+
+	doc, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := doc.SetPath("source.commit", "bbbb"); err != nil {
+		t.Fatalf("SetPath: %v", err)
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !strings.Contains(string(out), "commit: bbbb # pinned") {
+		t.Errorf("expected commit to be replaced in place with its comment kept, got:\n%s", out)
+	}
+}
+
+func TestSetPathCreatesMissingIntermediateMappings(t *testing.T) {
+	path := writeTempServer(t, "name: example\nsource:\n  project: https://github.com/example/example\n")
+
+	doc, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := doc.SetPath("source.version", "1.2.3"); err != nil {
+		t.Fatalf("SetPath: %v", err)
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !strings.Contains(string(out), "version: 1.2.3") {
+		t.Errorf("expected a new version field under source, got:\n%s", out)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package edit
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// field returns the key/value node pair within mapping whose key scalar is
+// name.
+func field(mapping *yaml.Node, name string) (key, value *yaml.Node, found bool) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1], true
+		}
+	}
+	return nil, nil, false
+}
+
+// setField sets name's scalar value within mapping, creating the key/value
+// pair at the end of the mapping if it doesn't already exist. When the key
+// already exists, only its value is replaced - its original position,
+// comments, and the key node itself are left untouched.
+func setField(mapping *yaml.Node, name, value string) {
+	_, v, found := field(mapping, name)
+	if found {
+		v.Kind = yaml.ScalarNode
+		v.Value = value
+		v.Tag = "!!str"
+		return
+	}
+
+	appendField(mapping, name, value)
+}
+
+// appendField appends a new name: value scalar pair to the end of mapping.
+func appendField(mapping *yaml.Node, name, value string) {
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value, Tag: "!!str"},
+	)
+}
+
+// subMapping returns the mapping node nested at name within mapping,
+// creating it (as an empty mapping) if it doesn't already exist.
+func subMapping(mapping *yaml.Node, name string) *yaml.Node {
+	_, v, found := field(mapping, name)
+	if found && v.Kind == yaml.MappingNode {
+		return v
+	}
+
+	sub := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+		sub,
+	)
+	return sub
+}
+
+// subSequence returns the sequence node nested at name within mapping,
+// creating it (as an empty sequence) if it doesn't already exist.
+func subSequence(mapping *yaml.Node, name string) *yaml.Node {
+	_, v, found := field(mapping, name)
+	if found && v.Kind == yaml.SequenceNode {
+		return v
+	}
+
+	sub := &yaml.Node{Kind: yaml.SequenceNode}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+		sub,
+	)
+	return sub
+}
+
+// lastTagSeparator returns the index of the ':' separating an image
+// reference's repository from its tag, or -1 if repo has no tag. A ':'
+// that appears before the last '/' belongs to a registry host's port
+// (e.g. "localhost:5000/mcp/foo"), not a tag separator.
+func lastTagSeparator(repo string) int {
+	colon := strings.LastIndex(repo, ":")
+	if colon < 0 {
+		return -1
+	}
+	if slash := strings.LastIndex(repo, "/"); slash > colon {
+		return -1
+	}
+	return colon
+}
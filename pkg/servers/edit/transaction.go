@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package edit
+
+import (
+	"fmt"
+	"os"
+)
+
+// Transaction batches edits across many server.yaml files so a caller can
+// preview every pending change as a unified diff, then commit all of them
+// (or none) to disk.
+type Transaction struct {
+	docs  map[string]*Document
+	order []string
+}
+
+// NewTransaction starts an empty Transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{docs: make(map[string]*Document)}
+}
+
+// Edit opens path (reusing the already-open Document if this transaction
+// has edited it before) and passes it to fn, which should call one or more
+// of Document's Set*/Add* methods.
+func (t *Transaction) Edit(path string, fn func(*Document) error) error {
+	doc, ok := t.docs[path]
+	if !ok {
+		var err error
+		doc, err = Open(path)
+		if err != nil {
+			return err
+		}
+		t.docs[path] = doc
+		t.order = append(t.order, path)
+	}
+
+	return fn(doc)
+}
+
+// Preview renders a unified diff of every document in the transaction that
+// has pending changes, in the order they were first edited.
+func (t *Transaction) Preview() (string, error) {
+	var out string
+	for _, path := range t.order {
+		doc := t.docs[path]
+
+		changed, err := doc.Changed()
+		if err != nil {
+			return "", err
+		}
+		if !changed {
+			continue
+		}
+
+		rendered, err := doc.Bytes()
+		if err != nil {
+			return "", err
+		}
+
+		out += unifiedDiff(path, doc.original, rendered)
+	}
+	return out, nil
+}
+
+// Commit writes every changed document in the transaction to disk, as a
+// temp-file-plus-rename per file so a reader never observes a
+// half-written server.yaml. Every document is rendered and staged to its
+// temp file before any rename happens, so a rendering failure partway
+// through leaves every original file untouched. It returns the paths that
+// were actually modified, in the order they were first edited.
+func (t *Transaction) Commit() ([]string, error) {
+	type staged struct {
+		path string
+		tmp  string
+	}
+
+	var stagedFiles []staged
+	rollback := func() {
+		for _, s := range stagedFiles {
+			os.Remove(s.tmp)
+		}
+	}
+
+	for _, path := range t.order {
+		doc := t.docs[path]
+
+		changed, err := doc.Changed()
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		rendered, err := doc.Bytes()
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, rendered, 0o644); err != nil {
+			rollback()
+			return nil, fmt.Errorf("edit: staging %s: %w", path, err)
+		}
+		stagedFiles = append(stagedFiles, staged{path: path, tmp: tmp})
+	}
+
+	var committed []string
+	for _, s := range stagedFiles {
+		if err := os.Rename(s.tmp, s.path); err != nil {
+			// Every staged file up to this one has already been renamed
+			// into place and can't be atomically undone; surface which
+			// files did land so the caller can report the partial result.
+			return committed, fmt.Errorf("edit: committing %s: %w", s.path, err)
+		}
+		committed = append(committed, s.path)
+	}
+
+	return committed, nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package jsonschema reflects over pkg/servers' Go types to emit a Draft
+// 2020-12 JSON Schema document describing server.yaml, so consumers (the
+// wizard, editor tooling, PR review) don't have to reverse-engineer the
+// shape of servers.Server from its struct tags by hand. It honors the
+// yaml/json struct tags pkg/servers already carries (preferring yaml, since
+// that's what server.yaml itself is written in) and each field's omitempty
+// flag to decide what's required.
+//
+// This is a separate, generated artifact from schemas/server.schema.json,
+// the hand-maintained schema cmd/validate-registry enforces: the
+// hand-maintained schema can express constraints (regex patterns,
+// conditionals, descriptions aimed at humans) that struct tags alone can't
+// capture, while this package guarantees its output can never drift from
+// the Go types that actually decode server.yaml.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// draftID identifies the JSON Schema dialect emitted documents declare.
+const draftID = "https://json-schema.org/draft/2020-12/schema"
+
+// Generate reflects over servers.Server and its nested types and returns the
+// Draft 2020-12 JSON Schema document describing them, as a JSON-marshalable
+// value. Recursive types (Schema, by way of SchemaList) are represented with
+// "$ref"/"$defs" rather than inlined, the same way the schema would have to
+// be hand-written.
+func Generate() (map[string]any, error) {
+	g := &generator{defs: map[string]any{}}
+
+	ref, err := g.defType(reflect.TypeOf(servers.Server{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"$schema":     draftID,
+		"$id":         "https://github.com/docker/mcp-registry/pkg/servers/jsonschema/server.schema.json",
+		"title":       "servers.Server",
+		"description": "Reflected from the servers.Server Go type (pkg/servers). See schemas/server.schema.json for the hand-maintained schema cmd/validate-registry enforces, which layers on constraints this generator can't derive from struct tags alone.",
+		"$ref":        ref["$ref"],
+		"$defs":       g.defs,
+	}, nil
+}
+
+// MarshalIndent renders Generate's document as indented JSON.
+func MarshalIndent() ([]byte, error) {
+	doc, err := Generate()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// generator accumulates the named struct types reached while walking
+// servers.Server, so each one is emitted once under "$defs" and referenced
+// by "$ref" everywhere else - the only way to describe SchemaList's
+// recursion through Schema without generating forever.
+type generator struct {
+	defs map[string]any
+}
+
+// defType returns a "$ref" to t's definition, generating it into g.defs
+// first if this is the first time t has been reached. A placeholder is
+// installed before recursing so a type that (transitively) refers back to
+// itself resolves to the same "$ref" instead of looping.
+func (g *generator) defType(t reflect.Type) (map[string]any, error) {
+	name := t.Name()
+	ref := map[string]any{"$ref": "#/$defs/" + name}
+
+	if _, seen := g.defs[name]; seen {
+		return ref, nil
+	}
+	g.defs[name] = map[string]any{}
+
+	schema, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	g.defs[name] = schema
+	return ref, nil
+}
+
+// structSchema builds the object schema for struct type t: one property per
+// exported field, named and required per fieldTag, with ",inline" fields
+// (SchemaEntry.Schema is the only one in pkg/servers today) flattened into
+// the parent rather than nested.
+func (g *generator) structSchema(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, inline, skip := fieldTag(field)
+		if skip {
+			continue
+		}
+
+		if inline {
+			nested, err := g.structSchema(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for prop, schema := range nested["properties"].(map[string]any) {
+				properties[prop] = schema
+			}
+			if nestedRequired, ok := nested["required"].([]string); ok {
+				required = append(required, nestedRequired...)
+			}
+			continue
+		}
+
+		schema, err := g.fieldSchema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("jsonschema: %s.%s: %w", t.Name(), field.Name, err)
+		}
+		properties[name] = schema
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	doc := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		doc["required"] = required
+	}
+	return doc, nil
+}
+
+// fieldSchema returns the schema for a single field's type.
+func (g *generator) fieldSchema(t reflect.Type) (map[string]any, error) {
+	if t == reflect.TypeOf(servers.SchemaList{}) {
+		return g.schemaListSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.fieldSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Slice, reflect.Array:
+		item, err := g.fieldSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": item}, nil
+	case reflect.Map:
+		additional, err := g.fieldSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": additional}, nil
+	case reflect.Interface:
+		// `any` (e.g. Schema.Default, Env.Example): no constraint beyond
+		// being present, since server.yaml allows any JSON value there.
+		return map[string]any{}, nil
+	case reflect.Struct:
+		return g.defType(t)
+	default:
+		return nil, fmt.Errorf("unsupported field kind %s", t.Kind())
+	}
+}
+
+// schemaListSchema describes SchemaList's ordered-map semantics: it
+// marshals as a JSON object keyed by property name (see
+// SchemaList.MarshalYAML), so the schema constrains every value to a
+// Schema and documents the sibling "x-order" key that records the
+// declaration order a plain JSON object can't guarantee on its own.
+func (g *generator) schemaListSchema() (map[string]any, error) {
+	entry, err := g.defType(reflect.TypeOf(servers.Schema{}))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"type":                 "object",
+		"description":          "Ordered map of property name to Schema. JSON object key order isn't guaranteed to round-trip, so the declared order is also recorded under the sibling \"x-order\" property.",
+		"additionalProperties": entry,
+		"properties": map[string]any{
+			"x-order": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "This object's property names, in declaration order.",
+			},
+		},
+	}, nil
+}
+
+// fieldTag resolves field's JSON Schema property name and whether it's
+// required ("omitempty" absent), inlined ("inline" present), or skipped
+// entirely ("-"), preferring its yaml tag (server.yaml's own format) and
+// falling back to its json tag when no yaml tag is present.
+func fieldTag(field reflect.StructField) (name string, omitempty, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		tag = field.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "", false, false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, flag := range parts[1:] {
+		switch flag {
+		case "omitempty":
+			omitempty = true
+		case "inline":
+			inline = true
+		}
+	}
+	if name == "" && !inline {
+		name = field.Name
+	}
+	return name, omitempty, inline, false
+}
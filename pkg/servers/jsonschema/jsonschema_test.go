@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestGenerateCompiles(t *testing.T) {
+	doc, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal generated schema: %v", err)
+	}
+
+	if _, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(payload)); err != nil {
+		t.Fatalf("generated schema does not compile: %v", err)
+	}
+}
+
+func TestGenerateRequiredAndOptionalFields(t *testing.T) {
+	doc, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	defs, ok := doc["$defs"].(map[string]any)
+	if !ok {
+		t.Fatalf("$defs is not an object: %T", doc["$defs"])
+	}
+
+	server, ok := defs["Server"].(map[string]any)
+	if !ok {
+		t.Fatalf("$defs.Server is not an object: %T", defs["Server"])
+	}
+
+	required, _ := server["required"].([]string)
+	if !containsString(required, "name") {
+		t.Errorf("expected \"name\" (no omitempty) to be required, got %v", required)
+	}
+	if containsString(required, "image") {
+		t.Errorf("expected \"image\" (omitempty) to not be required, got %v", required)
+	}
+}
+
+func TestSchemaListRendersAsOrderedObject(t *testing.T) {
+	doc, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	defs := doc["$defs"].(map[string]any)
+	schemaDef := defs["Schema"].(map[string]any)
+	properties := schemaDef["properties"].(map[string]any)
+
+	propertiesField, ok := properties["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("Schema.properties (the SchemaList field) is not an object: %T", properties["properties"])
+	}
+	if propertiesField["type"] != "object" {
+		t.Errorf("SchemaList should render as a JSON object, got type %v", propertiesField["type"])
+	}
+
+	xOrder, ok := propertiesField["properties"].(map[string]any)["x-order"]
+	if !ok {
+		t.Fatal("expected SchemaList schema to document a sibling \"x-order\" property")
+	}
+	if xOrder.(map[string]any)["type"] != "array" {
+		t.Errorf("x-order should be an array, got %v", xOrder)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -27,18 +27,86 @@ import (
 )
 
 type Server struct {
-	Name        string          `yaml:"name" json:"name"`
-	Image       string          `yaml:"image,omitempty" json:"image,omitempty"`
-	Type        string          `yaml:"type" json:"type"`
-	LongLived   bool            `yaml:"longLived,omitempty" json:"longLived,omitempty"`
-	Meta        Meta            `yaml:"meta,omitempty" json:"meta,omitempty"`
-	About       About           `yaml:"about,omitempty" json:"about,omitempty"`
-	Source      Source          `yaml:"source,omitempty" json:"source,omitempty"`
-	Run         Run             `yaml:"run,omitempty" json:"run,omitempty"`
-	Config      Config          `yaml:"config,omitempty" json:"config,omitempty"`
-	OAuth       []OAuthProvider `yaml:"oauth,omitempty" json:"oauth,omitempty"`
-	Tools       []Tool          `yaml:"tools,omitempty" json:"tools,omitempty"`
-	Requirement string          `yaml:"requirement,omitempty" json:"requirement,omitempty"`
+	Name         string          `yaml:"name" json:"name"`
+	Image        string          `yaml:"image,omitempty" json:"image,omitempty"`
+	Type         string          `yaml:"type" json:"type"`
+	LongLived    bool            `yaml:"longLived,omitempty" json:"longLived,omitempty"`
+	Meta         Meta            `yaml:"meta,omitempty" json:"meta,omitempty"`
+	About        About           `yaml:"about,omitempty" json:"about,omitempty"`
+	Source       Source          `yaml:"source,omitempty" json:"source,omitempty"`
+	Run          Run             `yaml:"run,omitempty" json:"run,omitempty"`
+	Config       Config          `yaml:"config,omitempty" json:"config,omitempty"`
+	OAuth        []OAuthProvider `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+	Tools        []Tool          `yaml:"tools,omitempty" json:"tools,omitempty"`
+	Requirements []Requirement   `yaml:"requirements,omitempty" json:"requirements,omitempty"`
+	Sandbox      *Sandbox        `yaml:"sandbox,omitempty" json:"sandbox,omitempty"`
+	// Dynamic declares which parts of a "remote"-type entry are discovered
+	// by calling the server directly rather than read from a static file
+	// checked into the registry.
+	Dynamic *Dynamic `yaml:"dynamic,omitempty" json:"dynamic,omitempty"`
+	// Remote configures the network transport for a "remote"-type entry,
+	// i.e. one reached over HTTP rather than run as a local container.
+	Remote Remote `yaml:"remote,omitempty" json:"remote,omitempty"`
+}
+
+// Dynamic declares runtime behavior for a "remote"-type server whose
+// capabilities aren't known ahead of time and must be discovered when the
+// server is reached, rather than read from a file checked into the
+// registry.
+type Dynamic struct {
+	// Tools indicates the server's tool list is discovered by calling the
+	// server directly instead of being read from tools.json.
+	Tools bool `yaml:"tools,omitempty" json:"tools,omitempty"`
+}
+
+// Remote is the connection info for a "remote"-type server.yaml entry.
+type Remote struct {
+	// TransportType selects the MCP transport the server speaks, e.g.
+	// "streamable-http" or "sse".
+	TransportType string `yaml:"transport_type,omitempty" json:"transport_type,omitempty"`
+	// URL is the server's endpoint.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+}
+
+// Sandbox constrains how internal/mcp's docker backend runs a server's
+// container while probing it for tools, so a registry entry that hasn't
+// opted into anything more permissive can't exfiltrate data over the
+// network or exhaust the host. A nil Sandbox gets a hardened default
+// profile rather than docker's own (permissive) defaults; see
+// internal/mcp's defaultSandbox.
+type Sandbox struct {
+	// Network selects the container's network mode: "none", "bridge", or
+	// "host".
+	Network string `yaml:"network,omitempty" json:"network,omitempty"`
+	// ReadOnlyRootFS mounts the container's root filesystem read-only.
+	ReadOnlyRootFS bool `yaml:"readOnlyRootFS,omitempty" json:"readOnlyRootFS,omitempty"`
+	// User overrides the container's user, as "uid:gid".
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	// Memory caps container memory, in docker --memory syntax (e.g. "512m").
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
+	// CPUs caps container CPU share, in docker --cpus syntax (e.g. "1.5").
+	CPUs string `yaml:"cpus,omitempty" json:"cpus,omitempty"`
+	// PidsLimit caps the number of processes the container can create.
+	PidsLimit int `yaml:"pidsLimit,omitempty" json:"pidsLimit,omitempty"`
+	// Tmpfs mounts writable tmpfs paths (e.g. "/tmp") inside an otherwise
+	// read-only container.
+	Tmpfs []string `yaml:"tmpfs,omitempty" json:"tmpfs,omitempty"`
+	// SeccompProfile points at a seccomp JSON profile applied via
+	// `--security-opt seccomp=<path>`, allow-listing the syscalls the
+	// container may make.
+	SeccompProfile string `yaml:"seccompProfile,omitempty" json:"seccompProfile,omitempty"`
+}
+
+// Requirement declares a sidecar that must be running before `mcp.Tools` can
+// probe the server, e.g. a database the server expects to find on its
+// network. Type selects a built-in sidecar spec (see internal/mcp's
+// requirement registry); Image and Env let a server.yaml override the
+// default image or add/override environment variables passed to the
+// sidecar.
+type Requirement struct {
+	Type  string            `yaml:"type" json:"type"`
+	Image string            `yaml:"image,omitempty" json:"image,omitempty"`
+	Env   map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
 }
 
 type Secret struct {
@@ -84,6 +152,39 @@ type Source struct {
 	Branch     string `yaml:"branch,omitempty" json:"branch,omitempty"`
 	Directory  string `yaml:"directory,omitempty" json:"directory,omitempty"`
 	Dockerfile string `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
+	// Commit pins a "server"-type entry to the audited revision of
+	// Project/Branch; see cmd/validate's isCommitPinnedIfNecessary and
+	// cmd/update-pins, which only ever advances it to a newer commit.
+	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
+	// Track opts update-pins into release/tag-based tracking instead of
+	// following Branch's head: "latest-release" resolves to the newest
+	// published GitHub release, "latest-tag" to the newest semver tag, and
+	// a "^"/"~" constraint such as "^1.2" to the newest tag satisfying it.
+	// See pkg/github.ResolveTrackedCommit.
+	Track string `yaml:"track,omitempty" json:"track,omitempty"`
+	// Tag records the semver tag update-pins resolved Commit from, when
+	// Track is set. update-pins writes this back alongside Commit; it
+	// isn't meant to be hand-edited.
+	Tag   string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	Trust *Trust `yaml:"trust,omitempty" json:"trust,omitempty"`
+}
+
+// Trust opts a server into signature-checked pin refreshes: `update-pins`
+// only advances source.commit to a revision it can verify, instead of
+// trusting the branch head outright. See cmd/update-pins and pkg/trust.
+type Trust struct {
+	// Signers lists the GPG/SSH key IDs allowed to sign a commit or tag
+	// that update-pins is willing to pin to. A commit verified by GitHub
+	// but signed by a key not in this list is rejected.
+	Signers []string `yaml:"signers,omitempty" json:"signers,omitempty"`
+	// RequireSignedTag requires source.branch to name a signed, annotated
+	// tag rather than a branch, so a pin can only advance to a revision
+	// the upstream maintainer explicitly tagged and signed.
+	RequireSignedTag bool `yaml:"require_signed_tag,omitempty" json:"require_signed_tag,omitempty"`
+	// TargetsFile points at a TUF-style targets metadata JSON file,
+	// sibling to server.yaml, that must also countersign the candidate
+	// revision (see pkg/trust.CheckTargets).
+	TargetsFile string `yaml:"targets_file,omitempty" json:"targets_file,omitempty"`
 }
 
 type Run struct {
@@ -124,9 +225,19 @@ type Property struct {
 }
 
 type Container struct {
-	Image   string   `yaml:"image,omitempty" json:"image,omitempty"`
-	Command []string `yaml:"command,omitempty" json:"command,omitempty"`
-	Volumes []string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Image   string          `yaml:"image,omitempty" json:"image,omitempty"`
+	Command []string        `yaml:"command,omitempty" json:"command,omitempty"`
+	Volumes []string        `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Trust   *ContainerTrust `yaml:"trust,omitempty" json:"trust,omitempty"`
+}
+
+// ContainerTrust opts a poci tool's container image into cosign signature
+// verification: cmd/validate's isPociValid only accepts the image when
+// cosign verifies a signature from one of Signers (a Fulcio/Rekor
+// identity, e.g. an email or a workflow identity URI), or from any
+// keyless identity when Signers is empty. See pkg/signing.
+type ContainerTrust struct {
+	Signers []string `yaml:"signers,omitempty" json:"signers,omitempty"`
 }
 
 type Meta struct {
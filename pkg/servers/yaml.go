@@ -22,3 +22,14 @@ func Read(path string) (Server, error) {
 
 	return server, nil
 }
+
+// Parse decodes a server.yaml document already held in memory, e.g. a
+// revision read via `git show <ref>:<path>` rather than the working tree.
+func Parse(data []byte) (Server, error) {
+	var server Server
+	if err := yaml.Unmarshal(data, &server); err != nil {
+		return Server{}, fmt.Errorf("failed to decode server data: %w", err)
+	}
+
+	return server, nil
+}
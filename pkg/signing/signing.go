@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package signing verifies cosign/Notary signatures and in-toto/SLSA
+// provenance attestations for OCI image references, using cosign's
+// tag-based discovery scheme (the image's signatures and attestations are
+// published as sibling tags derived from its digest). It shells out to the
+// cosign CLI rather than linking its Go module, matching how the rest of
+// this repository drives docker and git.
+package signing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/docker/mcp-registry/pkg/ociinspect"
+)
+
+// Signer identifies who produced a verified signature or attestation, as
+// reported by cosign's keyless (Fulcio) or key-based verification.
+type Signer struct {
+	Identity string `json:"identity"`
+	Issuer   string `json:"issuer"`
+	// RekorUUID is the transparency-log entry ID cosign's bundle recorded
+	// for this signature, when the signature was logged (keyless
+	// verification always logs one; key-based verification only does when
+	// --rekor-url applies). Empty when cosign didn't attach a bundle.
+	RekorUUID string `json:"rekorUUID,omitempty"`
+}
+
+// Attestation is a verified in-toto attestation attached to an image.
+type Attestation struct {
+	PredicateType string `json:"predicateType"`
+	Signer        Signer `json:"signer"`
+}
+
+// Result is the outcome of verifying an image reference's supply-chain
+// metadata.
+type Result struct {
+	// Digest is the subject digest the signatures/attestations were
+	// verified against.
+	Digest string
+	// Signers lists every identity whose signature verified successfully.
+	Signers []Signer
+	// Attestations lists every in-toto attestation that verified
+	// successfully, e.g. SLSA provenance.
+	Attestations []Attestation
+}
+
+// Verified reports whether at least one signature was found and verified.
+func (r Result) Verified() bool {
+	return len(r.Signers) > 0
+}
+
+// cosignSignaturePayload mirrors the subset of `cosign verify --output json`
+// output this package relies on.
+type cosignSignaturePayload struct {
+	Optional struct {
+		Issuer  string `json:"Issuer"`
+		Subject string `json:"Subject"`
+	} `json:"optional"`
+	// Bundle is only populated when cosign logged the signature to a
+	// transparency log (Rekor); Payload.LogID identifies that entry.
+	Bundle struct {
+		Payload struct {
+			LogID string `json:"logID"`
+		} `json:"Payload"`
+	} `json:"Bundle"`
+}
+
+// cosignAttestationPayload mirrors the subset of
+// `cosign verify-attestation --output json` output this package relies on.
+type cosignAttestationPayload struct {
+	Optional struct {
+		Issuer  string `json:"Issuer"`
+		Subject string `json:"Subject"`
+	} `json:"optional"`
+	PredicateType string `json:"payloadType"`
+}
+
+// Verify looks up and verifies cosign signatures and SLSA/in-toto
+// attestations for ref against trustedKeys (a set of cosign public key
+// paths/URLs) or, if trustedKeys is empty, keyless verification against the
+// public Fulcio/Rekor transparency log. It returns the verified subject
+// digest plus every signer/attestation that verified successfully; it is
+// not an error for an image to have none, but the caller should treat an
+// empty Result as unverified.
+func Verify(ctx context.Context, ref string, trustedKeys []string) (Result, error) {
+	digest, err := resolveDigest(ctx, ref)
+	if err != nil {
+		return Result{}, fmt.Errorf("signing: resolving digest for %s: %w", ref, err)
+	}
+
+	result := Result{Digest: digest}
+
+	signaturePayloads, err := runCosignJSONLines(ctx, append([]string{"verify"}, cosignTrustArgs(ref, trustedKeys)...))
+	if err == nil {
+		for _, raw := range signaturePayloads {
+			var payload cosignSignaturePayload
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				continue
+			}
+			result.Signers = append(result.Signers, Signer{
+				Identity:  payload.Optional.Subject,
+				Issuer:    payload.Optional.Issuer,
+				RekorUUID: payload.Bundle.Payload.LogID,
+			})
+		}
+	}
+
+	attestationPayloads, err := runCosignJSONLines(ctx, append([]string{"verify-attestation"}, cosignTrustArgs(ref, trustedKeys)...))
+	if err == nil {
+		for _, raw := range attestationPayloads {
+			var payload cosignAttestationPayload
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				continue
+			}
+			result.Attestations = append(result.Attestations, Attestation{
+				PredicateType: payload.PredicateType,
+				Signer: Signer{
+					Identity: payload.Optional.Subject,
+					Issuer:   payload.Optional.Issuer,
+				},
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func cosignTrustArgs(ref string, trustedKeys []string) []string {
+	args := make([]string, 0, 2*len(trustedKeys)+2)
+	if len(trustedKeys) == 0 {
+		args = append(args, "--certificate-identity-regexp", ".*", "--certificate-oidc-issuer-regexp", ".*")
+	}
+	for _, key := range trustedKeys {
+		args = append(args, "--key", key)
+	}
+	args = append(args, "--output", "json", ref)
+	return args
+}
+
+// runCosignJSONLines runs `cosign <args>` and splits its stdout into the
+// individual JSON documents it emits (one per verified signature/
+// attestation bundle).
+func runCosignJSONLines(ctx context.Context, args []string) ([]json.RawMessage, error) {
+	out, err := exec.CommandContext(ctx, "cosign", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	var lines []json.RawMessage
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			break
+		}
+		lines = append(lines, raw)
+	}
+	return lines, nil
+}
+
+// resolveDigest resolves ref's manifest digest directly against its
+// registry, the same way cosign itself looks up what it signed - so
+// verifying a signature never requires the image to already be pulled
+// locally.
+func resolveDigest(ctx context.Context, ref string) (string, error) {
+	summary, err := ociinspect.InspectManifest(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return summary.Digest, nil
+}
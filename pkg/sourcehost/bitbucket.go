@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sourcehost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Bitbucket authenticates with an app password rather than a bare token;
+// envBitbucketUsername pairs with it for HTTP basic auth.
+const (
+	envBitbucketUsername    = "BITBUCKET_USERNAME"
+	envBitbucketAppPassword = "BITBUCKET_APP_PASSWORD"
+)
+
+// bitbucketResolver resolves refs via the Bitbucket Cloud 2.0 "get a
+// commit" REST API (repositories/{workspace}/{repo}/commit/{ref}) and
+// materializes trees via a git clone authenticated with
+// BITBUCKET_APP_PASSWORD when present.
+type bitbucketResolver struct {
+	host       string
+	httpClient *http.Client
+}
+
+func newBitbucket(host string) *bitbucketResolver {
+	return &bitbucketResolver{host: host, httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type bitbucketCommit struct {
+	Hash string `json:"hash"`
+}
+
+func (b *bitbucketResolver) ResolveRef(ctx context.Context, project, ref string) (string, error) {
+	owner, repo, err := pathOwnerRepo(project)
+	if err != nil {
+		return "", err
+	}
+
+	if err := hostLimiter.wait(ctx, b.host); err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/commit/%s", owner, repo, url.PathEscape(ref))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if password := os.Getenv(envBitbucketAppPassword); password != "" {
+		req.SetBasicAuth(os.Getenv(envBitbucketUsername), password)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sourcehost: bitbucket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("sourcehost: bitbucket: reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sourcehost: bitbucket: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var commit bitbucketCommit
+	if err := json.Unmarshal(body, &commit); err != nil {
+		return "", fmt.Errorf("sourcehost: bitbucket: parsing commit response: %w", err)
+	}
+
+	return commit.Hash, nil
+}
+
+func (b *bitbucketResolver) FetchTree(ctx context.Context, project, sha, dest string) error {
+	return gitFetchTree(ctx, b.host, cloneURL(project, "x-token-auth", os.Getenv(envBitbucketAppPassword)), sha, dest)
+}
+
+func (b *bitbucketResolver) ArchiveURL(project, sha string) string {
+	owner, repo, err := pathOwnerRepo(project)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("https://bitbucket.org/%s/%s/get/%s.tar.gz", owner, repo, sha)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sourcehost
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/credentials"
+)
+
+// genericGitResolver resolves refs and trees for hosts without a dedicated
+// API integration (or a plain filesystem path) by shelling out to git
+// directly, the same way `git clone`/`git ls-remote` would.
+type genericGitResolver struct {
+	host string
+}
+
+func newGenericGit(host string) *genericGitResolver {
+	return &genericGitResolver{host: host}
+}
+
+func (g *genericGitResolver) ResolveRef(ctx context.Context, project, ref string) (string, error) {
+	if err := hostLimiter.wait(ctx, g.host); err != nil {
+		return "", err
+	}
+
+	remote := remoteURL(ctx, project)
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", remote, ref)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("sourcehost: git ls-remote %s %s: %w: %s", redact(remote), ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	line := strings.TrimSpace(stdout.String())
+	if line == "" {
+		return "", fmt.Errorf("sourcehost: %s has no ref %q", project, ref)
+	}
+
+	fields := strings.Fields(line)
+	return fields[0], nil
+}
+
+func (g *genericGitResolver) FetchTree(ctx context.Context, project, sha, dest string) error {
+	return gitFetchTree(ctx, g.host, remoteURL(ctx, project), sha, dest)
+}
+
+func (g *genericGitResolver) ArchiveURL(project, sha string) string {
+	// Arbitrary git remotes have no universal tarball endpoint.
+	return ""
+}
+
+// remoteURL resolves project to a clone/fetch URL, consulting git's own
+// credential helpers (the same ones `git clone` would use) for hosts this
+// package has no dedicated API integration for. It falls back to project
+// unchanged when no stored credentials are found, or when project isn't an
+// absolute URL (e.g. a local filesystem path).
+func remoteURL(ctx context.Context, project string) string {
+	creds, err := credentials.LookupGit(ctx, project)
+	if err != nil {
+		return project
+	}
+
+	u, err := url.Parse(project)
+	if err != nil {
+		return project
+	}
+	u.User = url.UserPassword(creds.Username, creds.Password)
+	return u.String()
+}
+
+// cloneURL embeds a token's basic-auth credentials (tokenUser:token) into
+// project's URL for an authenticated clone, or returns project unchanged
+// when token is empty.
+func cloneURL(project, tokenUser, token string) string {
+	if token == "" {
+		return project
+	}
+
+	u, err := url.Parse(project)
+	if err != nil {
+		return project
+	}
+	u.User = url.UserPassword(tokenUser, token)
+	return u.String()
+}
+
+// gitFetchTree clones cloneURL into dest and checks out sha, fetching it
+// explicitly first if it isn't reachable from the default branch (e.g. a
+// PR or feature-branch commit). Every Resolver shares this implementation,
+// since a plain (non-bare) checkout is assembled identically regardless of
+// which host the repository is on.
+func gitFetchTree(ctx context.Context, host, cloneURL, sha, dest string) error {
+	if err := hostLimiter.wait(ctx, host); err != nil {
+		return err
+	}
+
+	if err := runGit(ctx, "", "clone", cloneURL, dest); err != nil {
+		return fmt.Errorf("sourcehost: cloning %s: %w", redact(cloneURL), err)
+	}
+
+	if err := ensureCommit(ctx, dest, sha); err != nil {
+		return err
+	}
+
+	if err := runGit(ctx, dest, "checkout", "--detach", sha); err != nil {
+		return fmt.Errorf("sourcehost: checking out %s: %w", sha, err)
+	}
+
+	return nil
+}
+
+// ensureCommit verifies that sha exists in the repository at dir, fetching
+// it from the "origin" remote first if it doesn't.
+func ensureCommit(ctx context.Context, dir, sha string) error {
+	if err := runGit(ctx, dir, "rev-parse", "--verify", sha); err == nil {
+		return nil
+	}
+	if err := runGit(ctx, dir, "fetch", "origin", sha); err != nil {
+		return fmt.Errorf("sourcehost: fetching %s: %w", sha, err)
+	}
+	return runGit(ctx, dir, "rev-parse", "--verify", sha)
+}
+
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// redact strips userinfo credentials from a URL before it's embedded in an
+// error message.
+func redact(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = url.User(u.User.Username())
+	return u.String()
+}
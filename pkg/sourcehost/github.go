@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sourcehost
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/mcp-registry/pkg/github"
+)
+
+// githubResolver resolves refs via the GitHub API (through pkg/github's
+// own rate-limit-aware client) and materializes trees via a git clone
+// authenticated with GITHUB_TOKEN when present.
+type githubResolver struct {
+	client *github.Client
+}
+
+// NewGitHub wraps an existing github.Client as a Resolver, letting a
+// caller apply its own client construction (e.g. github.NewFromServer's
+// per-repository unauthenticated-access quirks) while still going through
+// the common Resolver interface.
+func NewGitHub(client *github.Client) Resolver {
+	return &githubResolver{client: client}
+}
+
+func (g *githubResolver) ResolveRef(ctx context.Context, project, ref string) (string, error) {
+	return g.client.GetCommitSHA1(ctx, project, ref)
+}
+
+func (g *githubResolver) FetchTree(ctx context.Context, project, sha, dest string) error {
+	return gitFetchTree(ctx, "github.com", cloneURL(project, "x-access-token", os.Getenv("GITHUB_TOKEN")), sha, dest)
+}
+
+func (g *githubResolver) ArchiveURL(project, sha string) string {
+	owner, repo, err := pathOwnerRepo(project)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", owner, repo, sha)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sourcehost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// envGitLabToken authenticates requests to the GitLab API and git clones,
+// as a personal/project access token.
+const envGitLabToken = "GITLAB_TOKEN"
+
+// gitlabResolver resolves refs via the GitLab "get a single commit" REST
+// API (projects/:id/repository/commits/:sha) and materializes trees via a
+// git clone authenticated with GITLAB_TOKEN when present.
+type gitlabResolver struct {
+	host       string
+	httpClient *http.Client
+}
+
+func newGitLab(host string) *gitlabResolver {
+	return &gitlabResolver{host: host, httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type gitlabCommit struct {
+	ID string `json:"id"`
+}
+
+func (g *gitlabResolver) ResolveRef(ctx context.Context, project, ref string) (string, error) {
+	owner, repo, err := pathOwnerRepo(project)
+	if err != nil {
+		return "", err
+	}
+
+	if err := hostLimiter.wait(ctx, g.host); err != nil {
+		return "", err
+	}
+
+	projectID := url.PathEscape(owner + "/" + repo)
+	endpoint := fmt.Sprintf("https://%s/api/v4/projects/%s/repository/commits/%s", g.host, projectID, url.PathEscape(ref))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if token := os.Getenv(envGitLabToken); token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sourcehost: gitlab: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("sourcehost: gitlab: reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sourcehost: gitlab: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var commit gitlabCommit
+	if err := json.Unmarshal(body, &commit); err != nil {
+		return "", fmt.Errorf("sourcehost: gitlab: parsing commit response: %w", err)
+	}
+
+	return commit.ID, nil
+}
+
+func (g *gitlabResolver) FetchTree(ctx context.Context, project, sha, dest string) error {
+	return gitFetchTree(ctx, g.host, cloneURL(project, "oauth2", os.Getenv(envGitLabToken)), sha, dest)
+}
+
+func (g *gitlabResolver) ArchiveURL(project, sha string) string {
+	owner, repo, err := pathOwnerRepo(project)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/%s/%s/-/archive/%s/%s-%s.tar.gz", g.host, owner, repo, sha, repo, sha)
+}
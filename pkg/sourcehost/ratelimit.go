@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sourcehost
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minRequestInterval is the minimum spacing enforced between requests to
+// the same host. GitHub's resolver is excluded - go-github already honors
+// GitHub's own rate-limit responses (see github.sleepOnRateLimitError) -
+// but GitLab, Bitbucket, and raw git invocations have no such feedback
+// loop, so this package paces them itself.
+const minRequestInterval = 250 * time.Millisecond
+
+// hostLimiter enforces minRequestInterval per host, so a burst of lookups
+// against one forge doesn't also throttle requests to another.
+var hostLimiter = &rateLimiter{interval: minRequestInterval, last: make(map[string]time.Time)}
+
+// rateLimiter enforces a minimum spacing between requests, tracked
+// independently per host key.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[string]time.Time
+}
+
+// wait blocks until interval has elapsed since the last call for host, or
+// returns early if ctx is canceled first.
+func (r *rateLimiter) wait(ctx context.Context, host string) error {
+	r.mu.Lock()
+	now := time.Now()
+	sleep := time.Duration(0)
+	if last, ok := r.last[host]; ok {
+		if elapsed := now.Sub(last); elapsed < r.interval {
+			sleep = r.interval - elapsed
+		}
+	}
+	r.last[host] = now.Add(sleep)
+	r.mu.Unlock()
+
+	if sleep <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(sleep):
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package sourcehost resolves refs and materializes trees for a server's
+// upstream repository regardless of which git forge hosts it. `update-pins`
+// and the security-reviewer's repository-clone step both used to hard-code
+// the GitHub API; they now select a Resolver by inspecting the project
+// URL's host, so GitLab, Bitbucket, and arbitrary git remotes can be pinned
+// and audited the same way.
+package sourcehost
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/docker/mcp-registry/pkg/github"
+	"github.com/docker/mcp-registry/pkg/servers"
+)
+
+// Resolver resolves refs and materializes trees for repositories hosted on
+// a particular git forge.
+type Resolver interface {
+	// ResolveRef resolves ref (a branch, tag, or existing commit) on
+	// project to the commit SHA it currently points at.
+	ResolveRef(ctx context.Context, project, ref string) (string, error)
+	// FetchTree materializes project's tree at sha into dest, as a plain
+	// (non-bare) checkout suitable for auditing or building.
+	FetchTree(ctx context.Context, project, sha, dest string) error
+	// ArchiveURL returns a URL serving a tarball of project at sha, or ""
+	// if the host has no such endpoint (e.g. an arbitrary git remote).
+	ArchiveURL(project, sha string) string
+}
+
+// For selects a Resolver for project by inspecting its URL host, falling
+// back to a generic git-protocol Resolver for hosts without a dedicated
+// API integration (or for a plain filesystem path). It uses only
+// environment-variable/git-credential-helper authentication; use ForServer
+// when a server.yaml's per-repository quirks should also apply.
+func For(project string) Resolver {
+	host := hostOf(project)
+
+	switch {
+	case host == "github.com":
+		return NewGitHub(github.New())
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return newGitLab(host)
+	case host == "bitbucket.org":
+		return newBitbucket(host)
+	default:
+		return newGenericGit(host)
+	}
+}
+
+// ForServer selects a Resolver the same way as For, except that a
+// github.com project is built via github.NewFromServer so per-server
+// authentication quirks (see its doc comment) still apply.
+func ForServer(server servers.Server) Resolver {
+	if hostOf(server.Source.Project) == "github.com" {
+		return NewGitHub(github.NewFromServer(server))
+	}
+	return For(server.Source.Project)
+}
+
+// hostOf returns the lowercased host of project, or "" if project isn't an
+// absolute URL (e.g. a local filesystem path).
+func hostOf(project string) string {
+	u, err := url.Parse(project)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// pathOwnerRepo splits project's URL path into its owner and repo
+// segments, e.g. "https://github.com/docker/mcp-registry" ->
+// ("docker", "mcp-registry").
+func pathOwnerRepo(project string) (string, string, error) {
+	u, err := url.Parse(project)
+	if err != nil {
+		return "", "", fmt.Errorf("sourcehost: parsing %q: %w", project, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("sourcehost: %q does not contain an owner/repo path", project)
+	}
+
+	return parts[0], parts[1], nil
+}
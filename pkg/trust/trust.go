@@ -0,0 +1,362 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package trust lets `update-pins` advance a server's pinned commit only
+// when the candidate revision is trustworthy, per its server.yaml
+// `source.trust` block. GitHub already verifies a commit or tag's GPG/SSH
+// signature for us (see RepositoryCommit.Verification); this package
+// extracts which key made that signature and checks it against the
+// server's configured allowlist, and separately checks a TUF-style
+// targets file when one is configured.
+package trust
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Verification records why a candidate revision was judged trustworthy,
+// so it can be carried into a server.lock file alongside the pin.
+type Verification struct {
+	KeyID      string    `yaml:"keyid" json:"keyid"`
+	Signer     string    `yaml:"signer" json:"signer"`
+	VerifiedAt time.Time `yaml:"verified_at" json:"verified_at"`
+}
+
+// signatureVerification is the subset of a GitHub commit/tag's signature
+// verification this package needs; it's satisfied by
+// *github.SignatureVerification.
+type signatureVerification interface {
+	GetVerified() bool
+	GetReason() string
+	GetSignature() string
+}
+
+// VerifySignature checks that sig was verified by GitHub and was made by
+// one of signers (if signers is non-empty; an empty allowlist trusts any
+// key GitHub itself vouches for). It returns the signer key ID.
+func VerifySignature(ctx context.Context, sig signatureVerification, signers []string) (Verification, error) {
+	if !sig.GetVerified() {
+		return Verification{}, fmt.Errorf("trust: signature not verified by GitHub (%s)", sig.GetReason())
+	}
+
+	keyID, err := signatureKeyID(ctx, sig.GetSignature())
+	if err != nil {
+		return Verification{}, fmt.Errorf("trust: reading signing key: %w", err)
+	}
+
+	if len(signers) > 0 && !slices.Contains(signers, keyID) {
+		return Verification{}, fmt.Errorf("trust: key %s is not in source.trust.signers", keyID)
+	}
+
+	return Verification{KeyID: keyID, Signer: keyID, VerifiedAt: time.Now().UTC()}, nil
+}
+
+// signatureKeyID extracts the ID of the key that produced an armored
+// GPG or SSH signature, as attached to a git commit or tag object.
+func signatureKeyID(ctx context.Context, armored string) (string, error) {
+	switch {
+	case strings.Contains(armored, "BEGIN PGP SIGNATURE"):
+		return gpgSignatureKeyID(ctx, armored)
+	case strings.Contains(armored, "BEGIN SSH SIGNATURE"):
+		return sshSignatureKeyID(armored)
+	default:
+		return "", fmt.Errorf("unrecognized signature format")
+	}
+}
+
+// gpgKeyIDPattern matches the keyid gpg prints for a signature packet, e.g.
+// ":signature packet: algo 1, keyid 1234567890ABCDEF".
+var gpgKeyIDPattern = regexp.MustCompile(`keyid ([0-9A-Fa-f]+)`)
+
+// gpgSignatureKeyID shells out to `gpg --list-packets` to read the key ID
+// a detached PGP signature was made with, without needing the
+// corresponding public key (or any gpg keyring changes) to do so.
+func gpgSignatureKeyID(ctx context.Context, armored string) (string, error) {
+	cmd := exec.CommandContext(ctx, "gpg", "--list-packets")
+	cmd.Stdin = strings.NewReader(armored)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	// gpg exits non-zero when it can't verify the signature (no public
+	// key on hand), which is expected here - we only want the key ID it
+	// printed along the way.
+	_ = cmd.Run()
+
+	match := gpgKeyIDPattern.FindStringSubmatch(stdout.String())
+	if match == nil {
+		return "", fmt.Errorf("gpg --list-packets: no key ID found in output")
+	}
+
+	return strings.ToUpper(match[1]), nil
+}
+
+// sshSignatureKeyID decodes an SSHSIG-framed armored signature (as
+// produced by `ssh-keygen -Y sign` and reported by GitHub's commit
+// verification) and returns the SHA256 fingerprint of the public key
+// embedded in it, in the same "SHA256:base64" form `ssh-keygen -lf` prints.
+func sshSignatureKeyID(armored string) (string, error) {
+	var b64 strings.Builder
+	for _, line := range strings.Split(armored, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		b64.WriteString(line)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(b64.String())
+	if err != nil {
+		return "", fmt.Errorf("decoding SSH signature: %w", err)
+	}
+
+	const magic = "SSHSIG"
+	if !bytes.HasPrefix(raw, []byte(magic)) {
+		return "", fmt.Errorf("not an SSHSIG-framed signature")
+	}
+	r := bytes.NewReader(raw[len(magic):])
+
+	var version uint32
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return "", fmt.Errorf("reading SSH signature version: %w", err)
+	}
+
+	publicKey, err := readSSHString(r)
+	if err != nil {
+		return "", fmt.Errorf("reading SSH public key: %w", err)
+	}
+
+	sum := sha256.Sum256(publicKey)
+	return "SHA256:" + base64.StdEncoding.WithPadding(base64.NoPadding).EncodeToString(sum[:]), nil
+}
+
+// readSSHString reads one length-prefixed ("string") field of the SSH wire
+// format used throughout SSHSIG and OpenSSH key blobs.
+func readSSHString(r *bytes.Reader) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, length)
+	if _, err := r.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// Target is one revision countersigned in a TUF-style targets metadata
+// file, following the update framework's targets role: Sig is a base64
+// standard-encoded ed25519 signature by KeyID over the canonical bytes
+// of the entry's name and SHA (see signedTargetBytes), verified against
+// a public key listed in the same file's root keys (see targetsFile).
+type Target struct {
+	SHA   string `json:"sha"`
+	Sig   string `json:"sig"`
+	KeyID string `json:"keyid"`
+}
+
+// targetsFile is the subset of TUF root-plus-targets metadata this
+// package reads: the root role's signing keys and signature threshold,
+// a targets role with one signed entry per tracked name, and an
+// expiration timestamp that invalidates the whole file once passed.
+type targetsFile struct {
+	Expires time.Time `json:"expires"`
+	// Threshold is the number of distinct signatures a target must
+	// carry to be trusted. Target today carries only one signature, so
+	// any Threshold above 1 can never be satisfied and is rejected
+	// outright rather than silently treated as met by one.
+	Threshold int `json:"threshold"`
+	// Keys maps a key ID (as named by Target.KeyID) to its base64
+	// standard-encoded ed25519 public key.
+	Keys    map[string]string `json:"keys"`
+	Targets map[string]Target `json:"targets"`
+}
+
+// signedTargetBytes returns the canonical bytes a targets-file entry's
+// signature is made over: the entry's name (a git ref or, for
+// pkg/verify's TUFRoot check, an image reference) and its pinned value,
+// joined by a NUL byte so neither can be grown into the other to forge
+// a different (name, value) pair with the same signature.
+func signedTargetBytes(name, value string) []byte {
+	return []byte(name + "\x00" + value)
+}
+
+// verifyTargetSignature checks that target is actually signed: that its
+// KeyID names a key in tf's own root keys, and that Sig is a valid
+// ed25519 signature by that key over signedTargetBytes(name, target.SHA).
+func verifyTargetSignature(tf targetsFile, name string, target Target) error {
+	if tf.Threshold > 1 {
+		return fmt.Errorf("requires %d signatures per target, but a target entry carries only one", tf.Threshold)
+	}
+
+	if target.Sig == "" || target.KeyID == "" {
+		return fmt.Errorf("entry for %s is unsigned", name)
+	}
+
+	encodedKey, ok := tf.Keys[target.KeyID]
+	if !ok {
+		return fmt.Errorf("entry for %s signed by key %s, which isn't in the root key set", name, target.KeyID)
+	}
+
+	pubKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("root key %s is not a valid ed25519 public key", target.KeyID)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(target.Sig)
+	if err != nil {
+		return fmt.Errorf("decoding signature for %s: %w", name, err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pubKey), signedTargetBytes(name, target.SHA), sig) {
+		return fmt.Errorf("signature for %s does not verify against key %s", name, target.KeyID)
+	}
+
+	return nil
+}
+
+// loadTarget reads the TUF targets metadata at path, checks that it
+// hasn't expired and that it actually pins name to value, and returns
+// the parsed file alongside that entry. It does not verify the entry's
+// signature - CheckTargets and VerifyCountersignedTarget do that
+// themselves, since they apply different additional checks on top.
+func loadTarget(path, name, value string) (targetsFile, Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return targetsFile{}, Target{}, fmt.Errorf("trust: reading targets file %s: %w", path, err)
+	}
+
+	var tf targetsFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return targetsFile{}, Target{}, fmt.Errorf("trust: parsing targets file %s: %w", path, err)
+	}
+
+	if !tf.Expires.IsZero() && time.Now().After(tf.Expires) {
+		return targetsFile{}, Target{}, fmt.Errorf("trust: targets file %s expired at %s", path, tf.Expires)
+	}
+
+	target, ok := tf.Targets[name]
+	if !ok {
+		return targetsFile{}, Target{}, fmt.Errorf("trust: targets file %s has no entry for %s", path, name)
+	}
+
+	if target.SHA != value {
+		return targetsFile{}, Target{}, fmt.Errorf("trust: targets file %s pins %s to %s, not %s", path, name, target.SHA, value)
+	}
+
+	return tf, target, nil
+}
+
+// CheckTargets verifies that refname -> sha is countersigned in the TUF
+// targets metadata at path by a key listed in that same file's root keys,
+// that the metadata hasn't expired, and that sha isn't a rollback
+// relative to the currently pinned commit (its committer date must be at
+// or after previousDate's).
+func CheckTargets(path, refname, sha string, previousDate, newDate time.Time) (Verification, error) {
+	tf, target, err := loadTarget(path, refname, sha)
+	if err != nil {
+		return Verification{}, err
+	}
+
+	if err := verifyTargetSignature(tf, refname, target); err != nil {
+		return Verification{}, fmt.Errorf("trust: targets file %s: %w", path, err)
+	}
+
+	if !previousDate.IsZero() && newDate.Before(previousDate) {
+		return Verification{}, fmt.Errorf("trust: %s (%s) is older than the currently pinned commit (%s); refusing to roll back", sha, newDate, previousDate)
+	}
+
+	return Verification{KeyID: target.KeyID, Signer: target.KeyID, VerifiedAt: time.Now().UTC()}, nil
+}
+
+// VerifyCountersignedTarget checks that name is pinned to value in the
+// TUF targets metadata at path, and that the pin is actually
+// countersigned by one of the file's own root keys. Unlike CheckTargets,
+// it applies no rollback check: it's used by pkg/verify's TUFRoot check
+// to countersign an image digest, which (unlike a git ref) has no commit
+// history to compare a rollback against.
+func VerifyCountersignedTarget(path, name, value string) error {
+	tf, target, err := loadTarget(path, name, value)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyTargetSignature(tf, name, target); err != nil {
+		return fmt.Errorf("trust: targets file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// LockPath returns the server.lock path sibling to a server.yaml path.
+func LockPath(serverYamlPath string) string {
+	return filepath.Join(filepath.Dir(serverYamlPath), "server.lock")
+}
+
+// lockFile is the shape written to server.lock, recording why update-pins
+// was willing to advance the pin to Commit.
+type lockFile struct {
+	Commit     string    `yaml:"commit" json:"commit"`
+	KeyID      string    `yaml:"keyid" json:"keyid"`
+	Signer     string    `yaml:"signer" json:"signer"`
+	VerifiedAt time.Time `yaml:"verified_at" json:"verified_at"`
+}
+
+// WriteLock records v's verification metadata for commit in server.lock,
+// sibling to serverYamlPath, so a later audit can see why update-pins
+// trusted this revision without re-deriving it from GitHub.
+func WriteLock(serverYamlPath, commit string, v Verification) error {
+	lock := lockFile{
+		Commit:     commit,
+		KeyID:      v.KeyID,
+		Signer:     v.Signer,
+		VerifiedAt: v.VerifiedAt,
+	}
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("trust: encoding server.lock: %w", err)
+	}
+
+	if err := os.WriteFile(LockPath(serverYamlPath), data, 0o644); err != nil {
+		return fmt.Errorf("trust: writing server.lock: %w", err)
+	}
+
+	return nil
+}
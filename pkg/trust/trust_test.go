@@ -0,0 +1,249 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package trust
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTargetsFile signs name -> sha with signingKey (keyed as keyID in
+// the file's root keys) and writes the resulting targets file to dir,
+// returning its path.
+func writeTargetsFile(t *testing.T, dir string, signingKey ed25519.PrivateKey, keyID, name, sha string) string {
+	t.Helper()
+
+	pub, _ := signingKey.Public().(ed25519.PublicKey)
+	sig := ed25519.Sign(signingKey, signedTargetBytes(name, sha))
+
+	tf := targetsFile{
+		Threshold: 1,
+		Keys: map[string]string{
+			keyID: base64.StdEncoding.EncodeToString(pub),
+		},
+		Targets: map[string]Target{
+			name: {
+				SHA:   sha,
+				Sig:   base64.StdEncoding.EncodeToString(sig),
+				KeyID: keyID,
+			},
+		},
+	}
+
+	data, err := json.Marshal(tf)
+	if err != nil {
+		t.Fatalf("marshaling targets file: %v", err)
+	}
+
+	path := filepath.Join(dir, "targets.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing targets file: %v", err)
+	}
+	return path
+}
+
+func TestCheckTargets_ValidSignature(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := writeTargetsFile(t, dir, priv, "key1", "main", "abc123")
+
+	v, err := CheckTargets(path, "main", "abc123", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("CheckTargets: unexpected error: %v", err)
+	}
+	if v.KeyID != "key1" {
+		t.Errorf("CheckTargets: KeyID = %q, want %q", v.KeyID, "key1")
+	}
+}
+
+func TestCheckTargets_ForgedEntryRejected(t *testing.T) {
+	// A targets file with no corresponding private key: this is the
+	// "anyone who can edit the file fabricates an entry" attack - the
+	// attacker can write any sha/sig/keyid they like, but without the
+	// private key for a trusted root key, the signature can't verify.
+	_, attackerKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	// The attacker signs with their own key, but lists a trusted root
+	// key (belonging to someone else) as the signer.
+	sig := ed25519.Sign(attackerKey, signedTargetBytes("main", "evil"))
+	trustedPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	tf := targetsFile{
+		Threshold: 1,
+		Keys: map[string]string{
+			"trusted-key": base64.StdEncoding.EncodeToString(trustedPub),
+		},
+		Targets: map[string]Target{
+			"main": {
+				SHA:   "evil",
+				Sig:   base64.StdEncoding.EncodeToString(sig),
+				KeyID: "trusted-key",
+			},
+		},
+	}
+	data, err := json.Marshal(tf)
+	if err != nil {
+		t.Fatalf("marshaling targets file: %v", err)
+	}
+	path := filepath.Join(dir, "targets.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing targets file: %v", err)
+	}
+
+	if _, err := CheckTargets(path, "main", "evil", time.Time{}, time.Time{}); err == nil {
+		t.Error("CheckTargets: expected error for a forged entry, got none")
+	}
+}
+
+func TestCheckTargets_UnsignedEntryRejected(t *testing.T) {
+	dir := t.TempDir()
+	tf := targetsFile{
+		Targets: map[string]Target{
+			"main": {SHA: "abc123"},
+		},
+	}
+	data, err := json.Marshal(tf)
+	if err != nil {
+		t.Fatalf("marshaling targets file: %v", err)
+	}
+	path := filepath.Join(dir, "targets.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing targets file: %v", err)
+	}
+
+	if _, err := CheckTargets(path, "main", "abc123", time.Time{}, time.Time{}); err == nil {
+		t.Error("CheckTargets: expected error for an unsigned entry, got none")
+	}
+}
+
+func TestCheckTargets_WrongShaRejected(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := writeTargetsFile(t, dir, priv, "key1", "main", "abc123")
+
+	if _, err := CheckTargets(path, "main", "differentsha", time.Time{}, time.Time{}); err == nil {
+		t.Error("CheckTargets: expected error when sha doesn't match the signed entry, got none")
+	}
+}
+
+func TestCheckTargets_TamperedSignatureRejected(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := writeTargetsFile(t, dir, priv, "key1", "main", "abc123")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading targets file: %v", err)
+	}
+	var tf targetsFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		t.Fatalf("parsing targets file: %v", err)
+	}
+	target := tf.Targets["main"]
+	target.SHA = "differentsha"
+	tf.Targets["main"] = target
+	tampered, err := json.Marshal(tf)
+	if err != nil {
+		t.Fatalf("marshaling tampered targets file: %v", err)
+	}
+	if err := os.WriteFile(path, tampered, 0o644); err != nil {
+		t.Fatalf("writing tampered targets file: %v", err)
+	}
+
+	if _, err := CheckTargets(path, "main", "differentsha", time.Time{}, time.Time{}); err == nil {
+		t.Error("CheckTargets: expected error for a sha changed after signing, got none")
+	}
+}
+
+func TestCheckTargets_ExpiredRejected(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := writeTargetsFile(t, dir, priv, "key1", "main", "abc123")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading targets file: %v", err)
+	}
+	var tf targetsFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		t.Fatalf("parsing targets file: %v", err)
+	}
+	tf.Expires = time.Now().Add(-time.Hour)
+	expired, err := json.Marshal(tf)
+	if err != nil {
+		t.Fatalf("marshaling expired targets file: %v", err)
+	}
+	if err := os.WriteFile(path, expired, 0o644); err != nil {
+		t.Fatalf("writing expired targets file: %v", err)
+	}
+
+	if _, err := CheckTargets(path, "main", "abc123", time.Time{}, time.Time{}); err == nil {
+		t.Error("CheckTargets: expected error for an expired targets file, got none")
+	}
+}
+
+func TestVerifyCountersignedTarget(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := writeTargetsFile(t, dir, priv, "key1", "docker.io/mcp/fetch", "sha256:abc123")
+
+	if err := VerifyCountersignedTarget(path, "docker.io/mcp/fetch", "sha256:abc123"); err != nil {
+		t.Errorf("VerifyCountersignedTarget: unexpected error: %v", err)
+	}
+
+	if err := VerifyCountersignedTarget(path, "docker.io/mcp/fetch", "sha256:different"); err == nil {
+		t.Error("VerifyCountersignedTarget: expected error for a digest not matching the signed entry, got none")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package verify checks that an audit target's pinned commit and image
+// still match what a trusted party produced, before cmd/ci hands it to
+// review agents. Unlike pkg/trust (which gates update-pins' own decision to
+// advance source.commit, using GitHub's API to learn who signed a
+// candidate), this package works against an already-cloned local
+// repository and a plain OCI registry, so it applies uniformly in CI
+// regardless of which host a server's project lives on - and it's scoped
+// by a standalone trust.yaml rather than each server.yaml's own
+// source.trust, so one allowlist can cover every project under a prefix.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-registry/pkg/signing"
+	"github.com/docker/mcp-registry/pkg/trust"
+)
+
+// ProjectPolicy is the trust configuration that applies to one project (or
+// every project under a prefix).
+type ProjectPolicy struct {
+	// Signers allowlists the identities a pin may be signed by: cosign
+	// Fulcio identities (emails, workflow URIs) for VerifyImage, or GPG/SSH
+	// key fingerprints for VerifyGit. Empty trusts any key/identity the
+	// underlying tool itself was able to validate.
+	Signers []string `yaml:"signers,omitempty"`
+	// TUFRoot points at a TUF root metadata JSON file, relative to
+	// trust.yaml's own directory, that VerifyImage additionally
+	// countersigns the target's digest against when set.
+	TUFRoot string `yaml:"tufRoot,omitempty"`
+}
+
+// policyEntry is one routing rule in trust.yaml: Prefix is matched against
+// a server's source.project/image registry to select which ProjectPolicy
+// governs it.
+type policyEntry struct {
+	Prefix        string `yaml:"prefix"`
+	ProjectPolicy `yaml:",inline"`
+}
+
+// policyFile is the on-disk shape of trust.yaml.
+type policyFile struct {
+	Projects []policyEntry `yaml:"projects"`
+}
+
+// Policy maps a project or image reference to the ProjectPolicy that
+// governs it, read from a trust.yaml.
+type Policy struct {
+	entries []policyEntry
+}
+
+// LoadPolicy reads the project trust allowlist at path. A missing file is
+// not an error: it yields an empty Policy, under which For never matches
+// and every target is treated as untrusted - the safe default for a
+// --require-signed run with no trust.yaml configured yet.
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Policy{}, nil
+		}
+		return Policy{}, fmt.Errorf("verify: reading trust policy %s: %w", path, err)
+	}
+
+	var file policyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Policy{}, fmt.Errorf("verify: parsing trust policy %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i, entry := range file.Projects {
+		if entry.TUFRoot != "" {
+			file.Projects[i].TUFRoot = filepath.Join(dir, entry.TUFRoot)
+		}
+	}
+
+	return Policy{entries: file.Projects}, nil
+}
+
+// For returns the ProjectPolicy governing ref (a project URL or image
+// reference), matching whichever configured prefix is longest, and
+// whether any prefix matched at all. An unmatched ref gets the zero
+// ProjectPolicy, which VerifyImage/VerifyGit treat as "trust any key the
+// tool itself validated" - callers that want to reject unconfigured
+// projects outright should check the second return value themselves.
+func (p Policy) For(ref string) (ProjectPolicy, bool) {
+	var best policyEntry
+	matched := false
+	for _, entry := range p.entries {
+		if entry.Prefix == "" || !strings.HasPrefix(ref, entry.Prefix) {
+			continue
+		}
+		if !matched || len(entry.Prefix) > len(best.Prefix) {
+			best = entry
+			matched = true
+		}
+	}
+	return best.ProjectPolicy, matched
+}
+
+// VerifyImage resolves ref to its digest and checks that it carries a
+// cosign signature from an identity in policy.Signers (keyless
+// verification against any identity, when Signers is empty).
+func VerifyImage(ctx context.Context, ref string, policy ProjectPolicy) (signing.Result, error) {
+	result, err := signing.Verify(ctx, ref, nil)
+	if err != nil {
+		return result, fmt.Errorf("verify: checking signature for %s: %w", ref, err)
+	}
+	if !result.Verified() {
+		return result, fmt.Errorf("verify: no verified cosign signature for %s", ref)
+	}
+
+	if len(policy.Signers) > 0 {
+		signed := false
+		for _, signer := range result.Signers {
+			if slices.Contains(policy.Signers, signer.Identity) {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			return result, fmt.Errorf("verify: %s is signed, but not by an identity in trust.yaml", ref)
+		}
+	}
+
+	if policy.TUFRoot != "" {
+		if err := trust.VerifyCountersignedTarget(policy.TUFRoot, ref, result.Digest); err != nil {
+			return result, fmt.Errorf("verify: %s: %w", ref, err)
+		}
+	}
+
+	return result, nil
+}
+
+// VerifyGit checks that commit, already fetched into repoDir (e.g. by
+// cmd/ci's fetchCommit), carries a GPG/SSH signature git itself can
+// validate against the local keyring, made by a key in policy.Signers
+// (any key git validated, when Signers is empty).
+func VerifyGit(ctx context.Context, repoDir, commit string, policy ProjectPolicy) (trust.Verification, error) {
+	cmd := exec.CommandContext(ctx, "git", "verify-commit", "--raw", commit)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return trust.Verification{}, fmt.Errorf("verify: %s is not signed by a key in the local keyring: %w", commit, err)
+	}
+
+	keyID, err := validSigKeyID(stderr.String())
+	if err != nil {
+		return trust.Verification{}, fmt.Errorf("verify: %s: %w", commit, err)
+	}
+
+	if len(policy.Signers) > 0 && !slices.Contains(policy.Signers, keyID) {
+		return trust.Verification{}, fmt.Errorf("verify: %s signed by key %s, not in trust.yaml", commit, keyID)
+	}
+
+	return trust.Verification{KeyID: keyID, Signer: keyID, VerifiedAt: time.Now().UTC()}, nil
+}
+
+// validSigKeyID extracts the signing key's fingerprint from git's GnuPG
+// status-protocol output (`--raw`), which emits a "[GNUPG:] VALIDSIG
+// <fingerprint> ..." line once the signature checks out.
+func validSigKeyID(raw string) (string, error) {
+	for _, line := range strings.Split(raw, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "VALIDSIG" {
+			return fields[2], nil
+		}
+	}
+	return "", fmt.Errorf("no VALIDSIG reported by git verify-commit")
+}
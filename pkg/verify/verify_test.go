@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Docker, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicy_TUFRootResolvedRelativeToTrustFile(t *testing.T) {
+	dir := t.TempDir()
+	subdir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	trustFile := filepath.Join(subdir, "trust.yaml")
+	policyYAML := `
+projects:
+  - prefix: docker.io/mcp/
+    tufRoot: tuf-root.json
+`
+	if err := os.WriteFile(trustFile, []byte(policyYAML), 0o644); err != nil {
+		t.Fatalf("writing trust.yaml: %v", err)
+	}
+
+	policy, err := LoadPolicy(trustFile)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+
+	projectPolicy, matched := policy.For("docker.io/mcp/fetch")
+	if !matched {
+		t.Fatalf("For: expected a match")
+	}
+
+	want := filepath.Join(subdir, "tuf-root.json")
+	if projectPolicy.TUFRoot != want {
+		t.Errorf("TUFRoot = %q, want %q (resolved relative to trust.yaml's directory)", projectPolicy.TUFRoot, want)
+	}
+}